@@ -44,11 +44,14 @@ func init() {
 
 		m.Restore(sys.Meta())
 
-		abacusCmd.Flags().StringVarP(&m.Abacus.Tag, "tag", "", "rev_", "decoy tag")
+		abacusCmd.Flags().StringVarP(&m.Abacus.Tag, "tag", "", "rev_", "decoy tag, a prefix or a comma-separated list of prefix:, suffix: and regex: rules")
+		abacusCmd.Flags().StringVarP(&m.Abacus.UniqueBy, "uniqueness", "", "", "comma-separated uniqueness categories counted as unique (protein-unique, isoform-shared, gene-unique, cross-gene-shared), implies --uniqueonly")
 		abacusCmd.Flags().Float64VarP(&m.Abacus.ProtProb, "prtProb", "", 0.9, "minimum protein probability")
 		abacusCmd.Flags().Float64VarP(&m.Abacus.PepProb, "pepProb", "", 0.5, "minimum peptide probability")
 		abacusCmd.Flags().BoolVarP(&m.Abacus.Protein, "protein", "", false, "global level protein report")
 		abacusCmd.Flags().BoolVarP(&m.Abacus.Peptide, "peptide", "", false, "global level peptide report")
+		abacusCmd.Flags().BoolVarP(&m.Abacus.Gene, "gene", "", false, "global level gene report")
+		abacusCmd.Flags().BoolVarP(&m.Abacus.Sites, "sites", "", false, "global level modification site report")
 		abacusCmd.Flags().BoolVarP(&m.Abacus.Razor, "razor", "", false, "use razor peptides for protein FDR scoring")
 		abacusCmd.Flags().BoolVarP(&m.Abacus.Picked, "picked", "", false, "apply the picked FDR algorithm before the protein scoring")
 		abacusCmd.Flags().BoolVarP(&m.Abacus.Unique, "uniqueonly", "", false, "report TMT quantification based on only unique peptides")
@@ -22,8 +22,10 @@ var databaseCmd = &cobra.Command{
 
 		m = dat.Run(m)
 
-		// store parameters on meta data
-		m.Serialize()
+		// store parameters on meta data, a dry run leaves the workspace untouched
+		if !m.Database.DryRun {
+			m.Serialize()
+		}
 
 		msg.Done()
 	},
@@ -37,15 +39,31 @@ func init() {
 
 		databaseCmd.Flags().StringVarP(&m.Database.ID, "id", "", "", "UniProt proteome ID")
 		databaseCmd.Flags().StringVarP(&m.Database.Annot, "annotate", "", "", "process a ready-to-use database")
-		databaseCmd.Flags().StringVarP(&m.Database.Enz, "enzyme", "", "trypsin", "enzyme for digestion (trypsin, lys_c, lys_n, glu_c, chymotrypsin)")
+		databaseCmd.Flags().StringVarP(&m.Database.Enz, "enzyme", "", "trypsin", "enzyme for digestion (trypsin, trypsin/p, lys_c, lys_n, arg_c, asp_n, glu_c, chymotrypsin, pepsin, nonspecific, combinations with + or custom:<regex>)")
 		databaseCmd.Flags().StringVarP(&m.Database.Tag, "prefix", "", "rev_", "define a decoy prefix")
+		databaseCmd.Flags().StringVarP(&m.Database.DecoyMethod, "decoy-method", "", "reverse", "decoy generation method (reverse, pseudo-reverse, shuffle, debruijn)")
+		databaseCmd.Flags().Int64VarP(&m.Database.DecoySeed, "decoy-seed", "", 0, "random seed for the shuffle and debruijn decoy methods (default is a random seed)")
 		databaseCmd.Flags().StringVarP(&m.Database.Add, "add", "", "", "add custom sequences (UniProt FASTA format only)")
+		databaseCmd.Flags().StringVarP(&m.Database.Local, "local", "", "", "use a local UniProt proteome FASTA file or mirror directory instead of downloading (gzip files are accepted)")
+		databaseCmd.Flags().StringVarP(&m.Database.URL, "uniprot-url", "", "https://rest.uniprot.org", "address of the UniProt REST API")
+		databaseCmd.Flags().StringVarP(&m.Database.Variant, "variant", "", "", "add variant protein or peptide sequences (e.g. translated from a VCF file)")
+		databaseCmd.Flags().StringVarP(&m.Database.Novel, "novel", "", "", "add novel protein sequences (e.g. from 3-frame or 6-frame translations)")
+		databaseCmd.Flags().StringVarP(&m.Database.Entrapment, "entrapment", "", "", "add entrapment sequences from a foreign proteome to validate the FDR estimation")
+		databaseCmd.Flags().StringVarP(&m.Database.Template, "template", "", "", "header template, a regular expression with (?P<id>), (?P<entry>), (?P<gene>), (?P<description>) and (?P<organism>) groups, or a file with one template per line")
 		databaseCmd.Flags().StringVarP(&m.Database.Custom, "custom", "", "", "use a pre-formatted custom database")
 		databaseCmd.Flags().BoolVarP(&m.Database.Crap, "contam", "", false, "add common contaminants")
+		databaseCmd.Flags().StringVarP(&m.Database.ContamLib, "contam-library", "", "", "comma-separated contaminant libraries, crap for the embedded cRAP list or FASTA files like the MaxQuant contaminants (implies --contam)")
 		databaseCmd.Flags().BoolVarP(&m.Database.CrapTag, "contamprefix", "", false, "mark the contaminant sequences with a prefix tag")
 		databaseCmd.Flags().BoolVarP(&m.Database.Rev, "reviewed", "", false, "use only reviwed sequences from Swiss-Prot")
 		databaseCmd.Flags().BoolVarP(&m.Database.Iso, "isoform", "", false, "add isoform sequences")
 		databaseCmd.Flags().BoolVarP(&m.Database.NoD, "nodecoys", "", false, "don't add decoys to the database")
+		databaseCmd.Flags().BoolVarP(&m.Database.DryRun, "dryrun", "", false, "print how each header would be parsed without changing the workspace")
+		databaseCmd.Flags().BoolVarP(&m.Database.Digest, "digest", "", false, "digest the database in silico and report peptide statistics")
+		databaseCmd.Flags().BoolVarP(&m.Database.Peptides, "peptides", "", false, "write the deduplicated digested peptides as FASTA and TSV files")
+		databaseCmd.Flags().StringVarP(&m.Database.Specificity, "specificity", "", "full", "digestion specificity (full, semi, semi-n, semi-c, non)")
+		databaseCmd.Flags().IntVarP(&m.Database.Missed, "missed", "", 2, "maximum number of missed cleavages for the digestion")
+		databaseCmd.Flags().IntVarP(&m.Database.MinLen, "minlen", "", 7, "minimum peptide length for the digestion")
+		databaseCmd.Flags().IntVarP(&m.Database.MaxLen, "maxlen", "", 50, "maximum peptide length for the digestion")
 	}
 
 	RootCmd.AddCommand(databaseCmd)
@@ -59,10 +59,16 @@ func init() {
 
 		filterCmd.Flags().StringVarP(&m.Filter.Pex, "pepxml", "", "", "pepXML file or directory containing a set of pepXML files")
 		filterCmd.Flags().StringVarP(&m.Filter.Pox, "protxml", "", "", "protXML file path")
-		filterCmd.Flags().StringVarP(&m.Filter.Tag, "tag", "", "rev_", "decoy tag")
+		filterCmd.Flags().StringVarP(&m.Filter.Tag, "tag", "", "rev_", "decoy tag, a prefix or a comma-separated list of prefix:, suffix: and regex: rules")
 		filterCmd.Flags().StringVarP(&m.Filter.Mods, "mods", "", "", "list of modifications for a stratified FDR filtering")
-		filterCmd.Flags().StringVarP(&m.Filter.RazorBin, "razorbin", "", "", "use a custom razor assignment for the filtering")
+		filterCmd.Flags().StringVarP(&m.Filter.RazorBin, "razorbin", "", "", "import a razor assignment for the filtering (TSV, JSON or razor.bin)")
+		filterCmd.Flags().StringVarP(&m.Filter.RazorOut, "razorout", "", "", "export the razor assignment to a TSV file, or to a JSON file when the name ends with .json")
+		filterCmd.Flags().StringVarP(&m.Filter.Enzyme, "enzyme", "", "", "recompute the enzymatic termini and missed cleavages with an enzyme (trypsin, trypsin/p, lys_c, lys_n, arg_c, asp_n, glu_c, chymotrypsin, pepsin, nonspecific, combinations with + or custom:<regex>)")
+		filterCmd.Flags().StringVarP(&m.Filter.Score, "score", "", "probability", "score used to rank and filter the identifications (probability, expectation, hyperscore, xcorr, discriminant)")
+		filterCmd.Flags().StringVarP(&m.Filter.Direction, "direction", "", "", "direction in which the score improves (higher, lower), the default depends on the score")
+		filterCmd.Flags().StringVarP(&m.Filter.ProtScore, "protScore", "", "top", "protein score used for the protein FDR (top, bestpep, pepproduct, logodds, maxquant)")
 		filterCmd.Flags().Float64VarP(&m.Filter.IonFDR, "ion", "", 0.01, "peptide ion FDR level")
+		filterCmd.Flags().Float64VarP(&m.Filter.FormFDR, "peptidoform", "", 0, "peptidoform (modified sequence) FDR level, the peptidoform level is skipped when not set")
 		filterCmd.Flags().Float64VarP(&m.Filter.PepFDR, "pep", "", 0.01, "peptide FDR level")
 		filterCmd.Flags().Float64VarP(&m.Filter.PsmFDR, "psm", "", 0.01, "psm FDR level")
 		filterCmd.Flags().Float64VarP(&m.Filter.PtFDR, "prot", "", 0.01, "protein FDR level")
@@ -76,8 +82,8 @@ func init() {
 		filterCmd.Flags().BoolVarP(&m.Filter.Picked, "picked", "", false, "apply the picked FDR algorithm before the protein scoring")
 		filterCmd.Flags().BoolVarP(&m.Filter.Mapmods, "mapmods", "", false, "map modifications")
 		filterCmd.Flags().BoolVarP(&m.Filter.Inference, "inference", "", false, "extremely fast and efficient protein inference compatible with 2D and Sequential filters")
+		filterCmd.Flags().BoolVarP(&m.Filter.Gene, "gene", "", false, "collapse the proteins to genes with the annotated database and apply a picked gene-level FDR")
 		filterCmd.Flags().MarkHidden("mods")
-		filterCmd.Flags().MarkHidden("razorbin")
 	}
 
 	RootCmd.AddCommand(filterCmd)
@@ -43,7 +43,9 @@ var labelquantCmd = &cobra.Command{
 			msg.InputNotFound(errors.New("unknown file format"), "fatal")
 		}
 
-		m.Quantify = qua.RunIsobaricLabelQuantification(m.Quantify, m.Filter.Mapmods)
+		m.Quantify.Mod = qua.MapmodsModification(m.Quantify.Mod, m.Filter.Mapmods)
+
+		m.Quantify = qua.RunIsobaricLabelQuantification(m.Quantify)
 
 		// store parameters on meta data
 		m.Serialize()
@@ -64,7 +66,9 @@ func init() {
 		labelquantCmd.Flags().StringVarP(&m.Quantify.Annot, "annot", "", "", "annotation file with custom names for the TMT channels")
 		labelquantCmd.Flags().StringVarP(&m.Quantify.Plex, "plex", "", "", "number of reporter ion channels")
 		labelquantCmd.Flags().StringVarP(&m.Quantify.Dir, "dir", "", "", "folder path containing the raw files")
+		labelquantCmd.Flags().StringVarP(&m.Quantify.Mod, "mod", "", "", "summarize the modified PSMs separately, defined by residues and mass (e.g. STY:79.9663, K:114.0429, n:42.0106), STY:79.9663 when the workspace was filtered with --mapmods")
 		labelquantCmd.Flags().StringVarP(&m.Quantify.Brand, "brand", "", "", "isobaric labeling brand (tmt, itraq)")
+		labelquantCmd.Flags().StringVarP(&m.Quantify.UniqueBy, "uniqueness", "", "", "comma-separated uniqueness categories rolled up as unique (protein-unique, isoform-shared, gene-unique, cross-gene-shared), implies --uniqueonly")
 		labelquantCmd.Flags().Float64VarP(&m.Quantify.Tol, "tol", "", 20, "m/z tolerance in ppm")
 		labelquantCmd.Flags().IntVarP(&m.Quantify.Level, "level", "", 2, "ms level for the quantification")
 		labelquantCmd.Flags().Float64VarP(&m.Quantify.Purity, "purity", "", 0.5, "ion purity threshold")
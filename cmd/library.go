@@ -0,0 +1,56 @@
+// Package cmd Library top level command
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"philosopher/lib/met"
+	"philosopher/lib/msg"
+	"philosopher/lib/spl"
+	"philosopher/lib/sys"
+
+	"github.com/spf13/cobra"
+)
+
+// libraryCmd represents the library command
+var libraryCmd = &cobra.Command{
+	Use:   "library",
+	Short: "Spectral library generation from filtered results",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		m.FunctionInitCheckUp()
+
+		if len(m.Library.Dir) < 1 {
+			msg.InputNotFound(errors.New("you need to provide the path to the mzML files"), "fatal")
+		}
+
+		msg.Executing("Library ", Version)
+
+		spl.Run(m)
+
+		// store parameters on meta data
+		m.Serialize()
+
+		// clean tmp
+		met.CleanTemp(m.Temp)
+
+		msg.Done()
+	},
+}
+
+func init() {
+
+	if len(os.Args) > 1 && os.Args[1] == "library" {
+
+		m.Restore(sys.Meta())
+
+		libraryCmd.Flags().StringVarP(&m.Library.Dir, "dir", "", "", "folder path containing the mzML files")
+		libraryCmd.Flags().StringVarP(&m.Library.IRT, "irt", "", "", "file with the anchor peptides and their iRT values (default is the Biognosys iRT kit)")
+		libraryCmd.Flags().Float64VarP(&m.Library.Tol, "tol", "", 20, "fragment m/z tolerance in ppm")
+		libraryCmd.Flags().IntVarP(&m.Library.TopN, "topn", "", 12, "maximum number of fragments per peptide ion")
+		libraryCmd.Flags().IntVarP(&m.Library.MinFrag, "minfrag", "", 4, "minimum number of annotated fragments per peptide ion")
+	}
+
+	RootCmd.AddCommand(libraryCmd)
+}
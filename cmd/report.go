@@ -44,6 +44,9 @@ func init() {
 		reportCmd.Flags().BoolVarP(&m.Report.MSstats, "msstats", "", false, "create an output compatible with MSstats")
 		reportCmd.Flags().BoolVarP(&m.Report.MZID, "mzid", "", false, "create a mzID output")
 		reportCmd.Flags().BoolVarP(&m.Report.IonMob, "ionmobility", "", false, "forces the printing of the ion mobility column")
+		reportCmd.Flags().BoolVarP(&m.Report.Sites, "sites", "", false, "create a site-level report with the localized modifications mapped to the proteins")
+		reportCmd.Flags().BoolVarP(&m.Report.QC, "qc", "", false, "create a self-contained HTML quality control report")
+		reportCmd.Flags().BoolVarP(&m.Report.CovMap, "coverage", "", false, "create the sequence coverage maps of the reported proteins")
 	}
 
 	RootCmd.AddCommand(reportCmd)
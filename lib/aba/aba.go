@@ -21,8 +21,8 @@ type DataSetLabelNames struct {
 // TODO update error methos on the abacus function
 func Run(m met.Data, args []string) {
 
-	if !m.Abacus.Peptide && !m.Abacus.Protein {
-		msg.Custom(errors.New("you need to specify a peptide or protein combined file for the Abacus analysis"), "fatal")
+	if !m.Abacus.Peptide && !m.Abacus.Protein && !m.Abacus.Sites && !m.Abacus.Gene {
+		msg.Custom(errors.New("you need to specify a peptide, protein, gene or site combined file for the Abacus analysis"), "fatal")
 	}
 
 	if m.Abacus.Peptide {
@@ -32,6 +32,14 @@ func Run(m met.Data, args []string) {
 	if m.Abacus.Protein {
 		proteinLevelAbacus(m, args)
 	}
+
+	if m.Abacus.Gene {
+		geneLevelAbacus(m, args)
+	}
+
+	if m.Abacus.Sites {
+		siteLevelAbacus(m, args)
+	}
 }
 
 // addCustomNames adds to the label structures user-defined names to be used on the TMT labels
@@ -0,0 +1,151 @@
+// Package aba (Abacus), gene level
+package aba
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"philosopher/lib/met"
+	"philosopher/lib/msg"
+	"philosopher/lib/rep"
+	"philosopher/lib/sys"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Create gene combined report
+func geneLevelAbacus(m met.Data, args []string) {
+
+	var names []string
+	var geneMap = make(map[string]*rep.CombinedGeneEvidence)
+
+	logrus.Info("Restoring gene results")
+
+	local, _ := os.Getwd()
+	local, _ = filepath.Abs(local)
+
+	for _, i := range args {
+
+		os.Chdir(i)
+
+		var genes rep.GeneEvidenceList
+		rep.RestoreGenes(&genes)
+
+		os.Chdir(local)
+
+		if len(genes) == 0 {
+			logrus.Warning("no gene results found in ", i, ", run the filter with the gene option")
+		}
+
+		// collect project names
+		prjName := filepath.Base(i)
+		names = append(names, prjName)
+
+		for _, j := range genes {
+
+			if j.IsDecoy {
+				continue
+			}
+
+			g, ok := geneMap[j.GeneName]
+			if !ok {
+				g = &rep.CombinedGeneEvidence{
+					GeneName:       j.GeneName,
+					Proteins:       make(map[string]uint8),
+					TopPepProb:     make(map[string]float64),
+					Spc:            make(map[string]int),
+					RazorSpc:       make(map[string]int),
+					RazorIntensity: make(map[string]float64),
+				}
+				geneMap[j.GeneName] = g
+			}
+
+			for _, k := range j.Proteins {
+				g.Proteins[k] = 0
+			}
+
+			g.TopPepProb[prjName] = j.TopPepProb
+			g.Spc[prjName] = j.TotalSpC
+			g.RazorSpc[prjName] = j.RazorSpC
+			g.RazorIntensity[prjName] = j.RazorIntensity
+		}
+	}
+
+	os.Chdir(local)
+
+	sort.Strings(names)
+
+	var evidences rep.CombinedGeneEvidenceList
+	for _, v := range geneMap {
+		evidences = append(evidences, *v)
+	}
+
+	saveGeneAbacusResult(m.Temp, evidences, names)
+}
+
+// saveGeneAbacusResult creates a single gene report using 1 or more philosopher result files
+func saveGeneAbacusResult(session string, evidences rep.CombinedGeneEvidenceList, namesList []string) {
+
+	output := fmt.Sprintf("%s%scombined_gene.tsv", session, string(filepath.Separator))
+
+	// create result file
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(e, "error")
+	}
+	defer file.Close()
+
+	line := "Gene\tProteins\tCombined Spectral Count\t"
+
+	for _, i := range namesList {
+		line += fmt.Sprintf("%s Top Peptide Probability\t", i)
+		line += fmt.Sprintf("%s Spectral Count\t", i)
+		line += fmt.Sprintf("%s Razor Spectral Count\t", i)
+		line += fmt.Sprintf("%s Razor Intensity\t", i)
+	}
+
+	line += "\n"
+	_, e = io.WriteString(file, line)
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	sort.Sort(evidences)
+
+	for _, i := range evidences {
+
+		var proteins []string
+		for j := range i.Proteins {
+			proteins = append(proteins, j)
+		}
+		sort.Strings(proteins)
+
+		var combined int
+		for _, j := range i.RazorSpc {
+			combined += j
+		}
+
+		line := fmt.Sprintf("%s\t%s\t%d\t",
+			i.GeneName,
+			strings.Join(proteins, ", "),
+			combined,
+		)
+
+		for _, j := range namesList {
+			line += fmt.Sprintf("%.4f\t%d\t%d\t%.4f\t", i.TopPepProb[j], i.Spc[j], i.RazorSpc[j], i.RazorIntensity[j])
+		}
+
+		line += "\n"
+		_, e = io.WriteString(file, line)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+
+	// copy to work directory
+	sys.CopyFile(output, filepath.Base(output))
+}
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"philosopher/lib/cla"
 	"philosopher/lib/fil"
 	"philosopher/lib/id"
 	"philosopher/lib/met"
@@ -97,7 +98,11 @@ func peptideLevelAbacus(m met.Data, args []string) {
 
 	os.Chdir(local)
 
-	savePeptideAbacusResult(m.Temp, evidences, datasets, names, m.Abacus.Unique, false, labelList)
+	// the global FDR set is the same on every data set
+	var global fil.GlobalFDR
+	global.RestoreWithPath(args[0])
+
+	savePeptideAbacusResult(m.Temp, evidences, datasets, names, m.Abacus.Unique, false, labelList, global)
 
 }
 
@@ -116,7 +121,7 @@ func processPeptideCombinedFile(a met.Abacus) {
 		var pep id.PepXML
 		pep.DecoyTag = a.Tag
 
-		pepID, _ = id.ReadPepXMLInput("combined.pep.xml", a.Tag, sys.GetTemp(), false)
+		pepID, _ = id.ReadPepXMLInput("combined.pep.xml", a.Tag, sys.GetTemp(), false, cla.Matcher(a.Tag))
 
 		//uniqPsms := fil.GetUniquePSMs(pepID)
 		uniqPeps := fil.GetUniquePeptides(pepID)
@@ -138,7 +143,7 @@ func collectPeptideDatafromExperiments(datasets map[string]rep.PSMEvidenceList,
 	var evidences rep.CombinedPeptideEvidenceList
 
 	for _, i := range pep {
-		if !strings.HasPrefix(i.Protein, decoyTag) {
+		if !cla.IsDecoy(i.Protein, decoyTag) {
 			var e rep.CombinedPeptideEvidence
 			e.Spc = make(map[string]int)
 			e.Intensity = make(map[string]float64)
@@ -257,7 +262,7 @@ func SummarizeAttributes(evidences rep.CombinedPeptideEvidenceList, datasets map
 }
 
 // savePeptideAbacusResult creates a single report using 1 or more philosopher result files
-func savePeptideAbacusResult(session string, evidences rep.CombinedPeptideEvidenceList, datasets map[string]rep.PSMEvidenceList, namesList []string, uniqueOnly, hasTMT bool, labelsList []DataSetLabelNames) {
+func savePeptideAbacusResult(session string, evidences rep.CombinedPeptideEvidenceList, datasets map[string]rep.PSMEvidenceList, namesList []string, uniqueOnly, hasTMT bool, labelsList []DataSetLabelNames, global fil.GlobalFDR) {
 
 	// create result file
 	output := fmt.Sprintf("%s%scombined_peptide.tsv", session, string(filepath.Separator))
@@ -276,6 +281,12 @@ func savePeptideAbacusResult(session string, evidences rep.CombinedPeptideEviden
 		line += fmt.Sprintf("%s Intensity\t", i)
 	}
 
+	// flag the peptides accepted by the global FDR filter
+	hasGlobal := len(global.Peptides) > 0
+	if hasGlobal {
+		line += "Passes Global FDR\t"
+	}
+
 	line += "\n"
 	_, e = io.WriteString(file, line)
 	if e != nil {
@@ -318,6 +329,10 @@ func savePeptideAbacusResult(session string, evidences rep.CombinedPeptideEviden
 			line += fmt.Sprintf("%d\t%.4f\t", i.Spc[j], i.Intensity[j])
 		}
 
+		if hasGlobal {
+			line += fmt.Sprintf("%t\t", global.IsPeptideAccepted(i.Sequence))
+		}
+
 		line += "\n"
 		_, e = io.WriteString(file, line)
 		if e != nil {
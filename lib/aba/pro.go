@@ -16,6 +16,7 @@ import (
 	"philosopher/lib/msg"
 	"philosopher/lib/uti"
 
+	"philosopher/lib/cla"
 	"philosopher/lib/dat"
 	"philosopher/lib/fil"
 	"philosopher/lib/id"
@@ -121,15 +122,31 @@ func proteinLevelAbacus(m met.Data, args []string) {
 	logrus.Info("Processing intensities")
 	evidences = sumProteinIntensities(evidences, datasets)
 
+	// the selected uniqueness categories replace the unique peptides from the protein inference
+	categories, e := rep.ParseUniqueness(m.Abacus.UniqueBy)
+	if e != nil {
+		msg.Custom(e, "fatal")
+	}
+
+	if len(categories) > 0 {
+		logrus.Info("Processing uniqueness categories")
+		evidences = getProteinUniquenessCounts(evidences, datasets, categories)
+		m.Abacus.Unique = true
+	}
+
 	// collect TMT labels
 	if m.Abacus.Labels {
 		evidences = getProteinLabelIntensities(evidences, datasets, m.Abacus.Tag)
 	}
 
+	// the global FDR set is the same on every data set
+	var global fil.GlobalFDR
+	global.RestoreWithPath(args[0])
+
 	if m.Abacus.Labels {
-		saveProteinAbacusResult(m.Temp, evidences, datasets, names, m.Abacus.Unique, true, m.Abacus.Full, labelList)
+		saveProteinAbacusResult(m.Temp, evidences, datasets, names, m.Abacus.Unique, true, m.Abacus.Full, labelList, global)
 	} else {
-		saveProteinAbacusResult(m.Temp, evidences, datasets, names, m.Abacus.Unique, false, m.Abacus.Full, labelList)
+		saveProteinAbacusResult(m.Temp, evidences, datasets, names, m.Abacus.Unique, false, m.Abacus.Full, labelList, global)
 	}
 
 	if m.Abacus.Reprint {
@@ -158,7 +175,7 @@ func processProteinCombinedFile(a met.Abacus, database dat.Base) rep.CombinedPro
 		protxml.MarkUniquePeptides(1)
 
 		// promote decoy proteins with indistinguishable target proteins
-		protxml.PromoteProteinIDs()
+		protxml.PromoteProteinIDs(cla.Matcher(a.Tag))
 
 		// applies pickedFDR algorithm
 		if a.Picked {
@@ -174,7 +191,7 @@ func processProteinCombinedFile(a met.Abacus, database dat.Base) rep.CombinedPro
 
 		for _, j := range proid {
 
-			if !strings.HasPrefix(j.ProteinName, a.Tag) {
+			if !cla.IsDecoy(j.ProteinName, a.Tag) {
 
 				var ce rep.CombinedProteinEvidence
 
@@ -214,7 +231,7 @@ func processProteinCombinedFile(a met.Abacus, database dat.Base) rep.CombinedPro
 
 	for i := range list {
 		for _, j := range database.Records {
-			if strings.Contains(j.OriginalHeader, list[i].ProteinName) && strings.HasPrefix(j.OriginalHeader, list[i].ProteinID) && !strings.Contains(j.OriginalHeader, a.Tag) {
+			if strings.Contains(j.OriginalHeader, list[i].ProteinName) && strings.HasPrefix(j.OriginalHeader, list[i].ProteinID) && !cla.Matcher(a.Tag).IsDecoy(j.PartHeader) {
 				//if strings.Contains(j.OriginalHeader, list[i].ProteinName) && !strings.Contains(j.OriginalHeader, a.Tag) {
 				list[i].ProteinName = j.PartHeader
 				list[i].ProteinID = j.ID
@@ -237,7 +254,7 @@ func getProteinSpectralCounts(combined rep.CombinedProteinEvidenceList, datasets
 	for i := range combined {
 		for k, v := range datasets {
 			for _, j := range v.Proteins {
-				if combined[i].ProteinID == j.ProteinID && !strings.Contains(j.OriginalHeader, decoyTag) {
+				if combined[i].ProteinID == j.ProteinID && !cla.IsDecoy(j.PartHeader, decoyTag) {
 					combined[i].UniqueSpc[k] = j.UniqueSpC
 					combined[i].TotalSpc[k] = j.TotalSpC
 					combined[i].UrazorSpc[k] = j.URazorSpC
@@ -262,7 +279,7 @@ func getProteinToPeptideCounts(combined rep.CombinedProteinEvidenceList, dataset
 
 		for k, v := range datasets {
 			for _, j := range v.Proteins {
-				if combined[i].ProteinName == j.PartHeader && !strings.Contains(j.OriginalHeader, decoyTag) {
+				if combined[i].ProteinName == j.PartHeader && !cla.IsDecoy(j.PartHeader, decoyTag) {
 
 					for l := range j.TotalPeptides {
 						total = append(total, l)
@@ -311,7 +328,7 @@ func getProteinLabelIntensities(combined rep.CombinedProteinEvidenceList, datase
 
 		for i := range combined {
 			for _, j := range v.Proteins {
-				if combined[i].ProteinID == j.ProteinID && !strings.Contains(j.OriginalHeader, decoyTag) {
+				if combined[i].ProteinID == j.ProteinID && !cla.IsDecoy(j.PartHeader, decoyTag) {
 					combined[i].TotalLabels[k] = j.TotalLabels
 					combined[i].UniqueLabels[k] = j.UniqueLabels
 					combined[i].URazorLabels[k] = j.URazorLabels
@@ -325,6 +342,67 @@ func getProteinLabelIntensities(combined rep.CombinedProteinEvidenceList, datase
 	return combined
 }
 
+// getProteinUniquenessCounts recomputes the unique spectral counts, peptides and intensities of each protein
+// using the PSMs and ions that belong to the selected uniqueness categories
+func getProteinUniquenessCounts(combined rep.CombinedProteinEvidenceList, datasets map[string]rep.Evidence, categories map[string]bool) rep.CombinedProteinEvidenceList {
+
+	for k, v := range datasets {
+
+		var spc = make(map[string]int)
+		var peptides = make(map[string]map[string]bool)
+		var intensity = make(map[string]float64)
+
+		for _, i := range v.PSM {
+
+			if i.IsDecoy || !categories[i.Uniqueness] {
+				continue
+			}
+
+			for _, j := range mappedProteinList(i.Protein, i.MappedProteins) {
+
+				spc[j]++
+
+				if _, ok := peptides[j]; !ok {
+					peptides[j] = make(map[string]bool)
+				}
+				peptides[j][i.Peptide] = false
+			}
+		}
+
+		for _, i := range v.Ions {
+
+			if i.IsDecoy || !categories[i.Uniqueness] {
+				continue
+			}
+
+			for _, j := range mappedProteinList(i.Protein, i.MappedProteins) {
+				intensity[j] += i.Intensity
+			}
+		}
+
+		for i := range combined {
+			combined[i].UniqueSpc[k] = spc[combined[i].ProteinName]
+			combined[i].UniquePeptides[k] = peptides[combined[i].ProteinName]
+			combined[i].UniqueIntensity[k] = intensity[combined[i].ProteinName]
+		}
+	}
+
+	return combined
+}
+
+// mappedProteinList returns the main protein followed by the other proteins a peptide maps to
+func mappedProteinList(protein string, mapped map[string]int) []string {
+
+	var list = []string{protein}
+	for i := range mapped {
+		if i != protein {
+			list = append(list, i)
+		}
+	}
+
+	return list
+}
+
 // sumIntensities calculates the protein intensity
 func sumProteinIntensities(combined rep.CombinedProteinEvidenceList, datasets map[string]rep.Evidence) rep.CombinedProteinEvidenceList {
 
@@ -352,7 +430,7 @@ func sumProteinIntensities(combined rep.CombinedProteinEvidenceList, datasets ma
 }
 
 // saveProteinAbacusResult creates a single report using 1 or more philosopher result files
-func saveProteinAbacusResult(session string, evidences rep.CombinedProteinEvidenceList, datasets map[string]rep.Evidence, namesList []string, uniqueOnly, hasTMT, full bool, labelsList []DataSetLabelNames) {
+func saveProteinAbacusResult(session string, evidences rep.CombinedProteinEvidenceList, datasets map[string]rep.Evidence, namesList []string, uniqueOnly, hasTMT, full bool, labelsList []DataSetLabelNames, global fil.GlobalFDR) {
 
 	var summTotalSpC = make(map[string]int)
 	var summUniqueSpC = make(map[string]int)
@@ -471,6 +549,12 @@ func saveProteinAbacusResult(session string, evidences rep.CombinedProteinEviden
 
 	header += "\tIndistinguishable Proteins"
 
+	// flag the proteins accepted by the global FDR filter
+	hasGlobal := len(global.Proteins) > 0
+	if hasGlobal {
+		header += "\tPasses Global FDR"
+	}
+
 	header += "\n"
 	_, e = io.WriteString(file, header)
 	if e != nil {
@@ -592,6 +676,10 @@ func saveProteinAbacusResult(session string, evidences rep.CombinedProteinEviden
 			ip := strings.Join(i.IndiProtein, ", ")
 			line += fmt.Sprintf("%s\t", ip)
 
+			if hasGlobal {
+				line += fmt.Sprintf("%t\t", global.IsProteinAccepted(i.ProteinName))
+			}
+
 			line += "\n"
 			_, e := io.WriteString(file, line)
 			if e != nil {
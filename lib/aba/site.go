@@ -0,0 +1,179 @@
+// Package aba (Abacus), site level
+package aba
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"philosopher/lib/iso"
+	"philosopher/lib/met"
+	"philosopher/lib/msg"
+	"philosopher/lib/rep"
+	"philosopher/lib/sys"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Create modification site combined report
+func siteLevelAbacus(m met.Data, args []string) {
+
+	var names []string
+	var siteMap = make(map[string]*rep.CombinedSiteEvidence)
+	var channelNames = make(map[string][]string)
+
+	// the reporter ion channels follow the labelquant plex, the protein table prints 10 channels
+	var channels int
+	if m.Abacus.Labels {
+		channels = 10
+		if len(m.Quantify.Plex) > 0 {
+			channels, _ = strconv.Atoi(m.Quantify.Plex)
+		}
+	}
+
+	logrus.Info("Restoring site results")
+
+	local, _ := os.Getwd()
+	local, _ = filepath.Abs(local)
+
+	for _, i := range args {
+
+		os.Chdir(i)
+
+		var evi rep.Evidence
+		rep.RestorePSM(&evi.PSM)
+		evi.AssembleSiteReport()
+
+		os.Chdir(local)
+
+		// collect project names
+		prjName := filepath.Base(i)
+		names = append(names, prjName)
+
+		if channels > 0 && len(evi.Sites) > 0 {
+			channelNames[prjName] = rep.LabelNames(evi.Sites[0].Labels, channels, true)
+		}
+
+		for _, j := range evi.Sites {
+
+			if j.IsDecoy {
+				continue
+			}
+
+			key := fmt.Sprintf("%s#%d#%s", j.Protein, j.Position, j.Modification)
+
+			s, ok := siteMap[key]
+			if !ok {
+				s = &rep.CombinedSiteEvidence{
+					Protein:      j.Protein,
+					ProteinID:    j.ProteinID,
+					EntryName:    j.EntryName,
+					GeneName:     j.GeneName,
+					Modification: j.Modification,
+					AminoAcid:    j.AminoAcid,
+					Position:     j.Position,
+					Probability:  make(map[string]float64),
+					Spc:          make(map[string]int),
+					LocalizedSpc: make(map[string]int),
+					Intensity:    make(map[string]float64),
+					Labels:       make(map[string]iso.Labels),
+				}
+				siteMap[key] = s
+			}
+
+			if j.Scored {
+				s.Probability[prjName] = j.Probability
+			}
+			s.Spc[prjName] = len(j.Spectra)
+			s.LocalizedSpc[prjName] = j.LocalizedSpc
+			s.Intensity[prjName] = j.Intensity
+			s.Labels[prjName] = j.Labels
+		}
+	}
+
+	os.Chdir(local)
+
+	sort.Strings(names)
+
+	var evidences rep.CombinedSiteEvidenceList
+	for _, v := range siteMap {
+		evidences = append(evidences, *v)
+	}
+
+	saveSiteAbacusResult(m.Temp, evidences, names, channels, channelNames)
+}
+
+// saveSiteAbacusResult creates a single site report using 1 or more philosopher result files
+func saveSiteAbacusResult(session string, evidences rep.CombinedSiteEvidenceList, namesList []string, channels int, channelNames map[string][]string) {
+
+	output := fmt.Sprintf("%s%scombined_site.tsv", session, string(filepath.Separator))
+
+	// create result file
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(e, "error")
+	}
+	defer file.Close()
+
+	line := "Protein\tProtein ID\tEntry Name\tGene\tPosition\tAmino Acid\tModification\t"
+
+	for _, i := range namesList {
+		line += fmt.Sprintf("%s Best Localization Probability\t", i)
+		line += fmt.Sprintf("%s Spectral Count\t", i)
+		line += fmt.Sprintf("%s Localized Spectral Count\t", i)
+		line += fmt.Sprintf("%s Intensity\t", i)
+	}
+
+	// reporter ion intensities for each data set
+	for _, i := range namesList {
+		for _, j := range channelNames[i] {
+			line += fmt.Sprintf("%s %s Intensity\t", i, j)
+		}
+	}
+
+	line += "\n"
+	_, e = io.WriteString(file, line)
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	sort.Sort(evidences)
+
+	for _, i := range evidences {
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%d\t%s\t%s\t",
+			i.Protein,
+			i.ProteinID,
+			i.EntryName,
+			i.GeneName,
+			i.Position,
+			i.AminoAcid,
+			i.Modification,
+		)
+
+		for _, j := range namesList {
+			p, scored := i.Probability[j]
+			line += fmt.Sprintf("%s\t%d\t%d\t%.4f\t", rep.SiteProbability(p, scored), i.Spc[j], i.LocalizedSpc[j], i.Intensity[j])
+		}
+
+		for _, j := range namesList {
+			if _, ok := channelNames[j]; ok {
+				for _, k := range rep.LabelIntensities(i.Labels[j], channels) {
+					line += fmt.Sprintf("%.4f\t", k)
+				}
+			}
+		}
+
+		line += "\n"
+		_, e = io.WriteString(file, line)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+
+	// copy to work directory
+	sys.CopyFile(output, filepath.Base(output))
+}
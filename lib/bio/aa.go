@@ -18,6 +18,35 @@ type AminoAcid struct {
 // OligoPeptide is an array of Aminoacids
 type OligoPeptide []AminoAcid
 
+// aminoAcidNames lists the standard amino acids known by New
+var aminoAcidNames = []string{"Alanine", "Arginine", "Asparagine", "Aspartic Acid", "Cysteine", "Glutamine", "Glutamic Acid",
+	"Glycine", "Histidine", "Isoleucine", "Leucine", "Lysine", "Methionine", "Phenylalanine", "Proline", "Serine",
+	"Threonine", "Tryptophan", "Tyrosine", "Valine"}
+
+// residueMasses holds the monoisotopic residue masses by one-letter code, it is built once at package
+// initialization so concurrent digestions can read it safely
+var residueMasses = func() map[byte]float64 {
+
+	var masses = make(map[byte]float64)
+	for _, i := range AminoAcids() {
+		masses[i.Code[0]] = i.MonoIsotopeMass
+	}
+
+	return masses
+}()
+
+// AminoAcids returns the 20 standard amino acids
+func AminoAcids() OligoPeptide {
+
+	var list OligoPeptide
+
+	for _, i := range aminoAcidNames {
+		list = append(list, New(i))
+	}
+
+	return list
+}
+
 // New return the correct information for the give aminoacid
 func New(name string) AminoAcid {
 
@@ -72,3 +101,15 @@ func New(name string) AminoAcid {
 
 	return aa
 }
+
+// PeptideMass returns the monoisotopic neutral mass of an unmodified peptide, residues
+// outside the 20 standard amino acids are not counted
+func PeptideMass(seq string) float64 {
+
+	mass := Water
+	for i := 0; i < len(seq); i++ {
+		mass += residueMasses[seq[i]]
+	}
+
+	return mass
+}
@@ -1,6 +1,7 @@
 package bio
 
 import (
+	"math"
 	"reflect"
 	"testing"
 )
@@ -28,3 +29,33 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestPeptideMass(t *testing.T) {
+
+	tests := []struct {
+		name string
+		seq  string
+		want float64
+	}{
+		{"Empty peptide", "", Water},
+		{"Standard residues", "PEPTIDE", 799.359964},
+		{"Unknown residues are not counted", "PEPTIDEX", 799.359964},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// concurrent digestions share the residue masses
+			var got = make(chan float64, 4)
+			for i := 0; i < cap(got); i++ {
+				go func() { got <- PeptideMass(tt.seq) }()
+			}
+
+			for i := 0; i < cap(got); i++ {
+				if mass := <-got; math.Abs(mass-tt.want) > 1e-5 {
+					t.Errorf("PeptideMass() = %v, want %v", mass, tt.want)
+				}
+			}
+		})
+	}
+}
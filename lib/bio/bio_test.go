@@ -145,3 +145,36 @@ func TestEnzymes(t *testing.T) {
 		t.Errorf("Enzyme is incorrect, got %s, want %s", e.Name, "glu_c")
 	}
 }
+
+func TestNewEnzyme(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		enzyme  string
+		want    string
+		wantErr bool
+	}{
+		{"Library enzyme", "Trypsin", "trypsin", false},
+		{"Combined enzymes", "trypsin+glu_c", "trypsin+glu_c", false},
+		{"Nonspecific", "nonspecific", "nonspecific", false},
+		{"Custom pattern", "custom:[KR][^P]", "custom", false},
+		{"Typo", "trypsn", "", true},
+		{"Typo in a combination", "trypsin+gluc", "", true},
+		{"Empty name", "", "", true},
+		{"Invalid custom pattern", "custom:[KR", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, e := NewEnzyme(tt.enzyme)
+			if (e != nil) != tt.wantErr {
+				t.Fatalf("NewEnzyme() error = %v, wantErr %v", e, tt.wantErr)
+			}
+
+			if got.Name != tt.want {
+				t.Errorf("NewEnzyme() = %v, want %v", got.Name, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package bio
+
+import (
+	"strings"
+)
+
+// digestion specificities
+const (
+	FullySpecific = "full"
+	SemiSpecific  = "semi"
+	SemiNSpecific = "semi-n"
+	SemiCSpecific = "semi-c"
+	NonSpecific   = "non"
+)
+
+// Peptide is a product of an in-silico digestion, Start and End are 1-based protein positions
+type Peptide struct {
+	Sequence        string
+	Start           int
+	End             int
+	PrevAA          string
+	NextAA          string
+	MissedCleavages int
+}
+
+// CleavageSites returns the positions of the bonds cut by the enzyme, including both protein termini.
+// A position i is the bond between residues i-1 and i
+func (e Enzyme) CleavageSites(seq string) []int {
+
+	sites := []int{0}
+
+	for i := 1; i < len(seq); i++ {
+		if e.IsNonSpecific() || e.Cleaves(seq[i-1], seq[i]) {
+			sites = append(sites, i)
+		}
+	}
+
+	if len(seq) > 0 {
+		sites = append(sites, len(seq))
+	}
+
+	return sites
+}
+
+// Digest cleaves a protein sequence in silico. Peptides with more than missed cleavages or with a length
+// outside minLen and maxLen are discarded, a maxLen of zero means no upper limit. Semi-specific digestions
+// require one enzymatic terminus (semi-n and semi-c choose which one), non-specific digestions none
+func (e Enzyme) Digest(seq string, missed, minLen, maxLen int, specificity string) []Peptide {
+
+	var list []Peptide
+
+	sites := e.CleavageSites(seq)
+
+	var isSite = make([]bool, len(seq)+1)
+	for _, i := range sites {
+		isSite[i] = true
+	}
+
+	// counts the cleavage sites before each position, used for the missed cleavages
+	var before = make([]int, len(seq)+2)
+	for i := 1; i <= len(seq); i++ {
+		before[i+1] = before[i]
+		if i < len(seq) && isSite[i] && !e.IsNonSpecific() {
+			before[i+1]++
+		}
+	}
+
+	for start := 0; start < len(seq); start++ {
+
+		for end := start + 1; end <= len(seq); end++ {
+
+			length := end - start
+
+			if maxLen > 0 && length > maxLen {
+				break
+			}
+
+			if length < minLen {
+				continue
+			}
+
+			mc := before[end] - before[start+1]
+			if mc > missed {
+				break
+			}
+
+			if !acceptTermini(isSite[start], isSite[end], specificity) {
+				continue
+			}
+
+			list = append(list, newPeptide(seq, start, end, mc))
+		}
+	}
+
+	return list
+}
+
+// acceptTermini checks the enzymatic termini of a peptide against the digestion specificity
+func acceptTermini(n, c bool, specificity string) bool {
+
+	switch specificity {
+	case SemiSpecific:
+		return n || c
+	case SemiNSpecific:
+		return n
+	case SemiCSpecific:
+		return c
+	case NonSpecific:
+		return true
+	}
+
+	return n && c
+}
+
+// newPeptide builds a digestion product from the protein sequence boundaries
+func newPeptide(seq string, start, end, missed int) Peptide {
+
+	p := Peptide{
+		Sequence:        seq[start:end],
+		Start:           start + 1,
+		End:             end,
+		PrevAA:          "-",
+		NextAA:          "-",
+		MissedCleavages: missed,
+	}
+
+	if start > 0 {
+		p.PrevAA = seq[start-1 : start]
+	}
+
+	if end < len(seq) {
+		p.NextAA = seq[end : end+1]
+	}
+
+	return p
+}
+
+// NumberOfEnzymaticTermini counts how many peptide ends are consistent with the enzyme, a dash in the
+// flanking residues marks the protein termini
+func (e Enzyme) NumberOfEnzymaticTermini(prev, peptide, next string) int {
+
+	if len(peptide) == 0 {
+		return 0
+	}
+
+	var ntt int
+
+	if prev == "-" || len(prev) == 0 || e.IsNonSpecific() || e.Cleaves(prev[len(prev)-1], peptide[0]) {
+		ntt++
+	}
+
+	if next == "-" || len(next) == 0 || e.IsNonSpecific() || e.Cleaves(peptide[len(peptide)-1], next[0]) {
+		ntt++
+	}
+
+	return ntt
+}
+
+// MissedCleavages counts the internal bonds of a peptide the enzyme would have cut
+func (e Enzyme) MissedCleavages(peptide string) int {
+
+	if e.IsNonSpecific() {
+		return 0
+	}
+
+	var mc int
+	for i := 1; i < len(peptide); i++ {
+		if e.Cleaves(peptide[i-1], peptide[i]) {
+			mc++
+		}
+	}
+
+	return mc
+}
+
+// Coverage returns the percentage of the protein sequence covered by the peptides, isoleucine and leucine
+// are treated as the same residue
+func Coverage(seq string, peptides []string) float64 {
+
+	if len(seq) == 0 {
+		return 0
+	}
+
+	var count int
+	for _, i := range CoverageMap(seq, peptides) {
+		if i > 0 {
+			count++
+		}
+	}
+
+	return float64(count) / float64(len(seq)) * 100
+}
+
+// CoverageMap returns the number of peptides covering each residue of the protein sequence
+func CoverageMap(seq string, peptides []string) []int {
+
+	var depth = make([]int, len(seq))
+
+	for _, i := range peptides {
+		for _, j := range PeptidePositions(seq, i) {
+			for k := j - 1; k < j-1+len(i); k++ {
+				depth[k]++
+			}
+		}
+	}
+
+	return depth
+}
+
+// PeptidePositions returns the 1-based start of every occurrence of the peptide in the protein sequence,
+// isoleucine and leucine are treated as the same residue
+func PeptidePositions(seq, peptide string) []int {
+
+	var positions []int
+
+	if len(peptide) == 0 {
+		return positions
+	}
+
+	protein := strings.Replace(seq, "I", "L", -1)
+	pep := strings.Replace(peptide, "I", "L", -1)
+
+	offset := 0
+	for {
+		idx := strings.Index(protein[offset:], pep)
+		if idx < 0 {
+			break
+		}
+
+		positions = append(positions, offset+idx+1)
+		offset += idx + 1
+	}
+
+	return positions
+}
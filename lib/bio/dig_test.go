@@ -0,0 +1,116 @@
+package bio_test
+
+import (
+	. "philosopher/lib/bio"
+	"reflect"
+	"testing"
+)
+
+func TestEnzyme_Digest(t *testing.T) {
+
+	seq := "MPEPTIDEKAAGLSRPQWVNKLLSEDGHTYK"
+
+	tests := []struct {
+		name        string
+		enzyme      string
+		missed      int
+		specificity string
+		want        int
+	}{
+		{"Trypsin, no missed cleavages", "trypsin", 0, FullySpecific, 3},
+		{"Trypsin, one missed cleavage", "trypsin", 1, FullySpecific, 5},
+		{"Trypsin/P cuts before proline", "trypsin/p", 0, FullySpecific, 4},
+		{"Trypsin and Glu-C", "trypsin+glu_c", 0, FullySpecific, 7},
+		{"Custom regular expression", "custom:[KR][^P]", 0, FullySpecific, 3},
+		{"Semi-specific trypsin", "trypsin", 0, SemiSpecific, 59},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var e Enzyme
+			e.Synth(tt.enzyme)
+
+			got := e.Digest(seq, tt.missed, 1, 0, tt.specificity)
+			if len(got) != tt.want {
+				t.Errorf("Digest() = %d peptides, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestEnzyme_NumberOfEnzymaticTermini(t *testing.T) {
+
+	var e Enzyme
+	e.Synth("trypsin")
+
+	tests := []struct {
+		name    string
+		prev    string
+		peptide string
+		next    string
+		ntt     int
+		mc      int
+	}{
+		{"Fully tryptic", "K", "AAGLSRPQWVNK", "L", 2, 0},
+		{"Protein N-terminus", "-", "MPEPTIDEK", "A", 2, 0},
+		{"Semi tryptic", "E", "KAAGLSR", "P", 0, 1},
+		{"Missed cleavage", "K", "AAGLSRPQWVNKLLSEDGHTYK", "-", 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := e.NumberOfEnzymaticTermini(tt.prev, tt.peptide, tt.next); got != tt.ntt {
+				t.Errorf("NumberOfEnzymaticTermini() = %d, want %d", got, tt.ntt)
+			}
+
+			if got := e.MissedCleavages(tt.peptide); got != tt.mc {
+				t.Errorf("MissedCleavages() = %d, want %d", got, tt.mc)
+			}
+		})
+	}
+}
+
+func TestCoverage(t *testing.T) {
+
+	got := Coverage("MPEPTIDEKAAGLSR", []string{"MPEPTIDEK", "PEPT", "AAGISR"})
+	if got != 100 {
+		t.Errorf("Coverage() = %.2f, want 100", got)
+	}
+
+	got = Coverage("MPEPTIDEKAAGLSR", []string{"AAGLSR"})
+	if got != 40 {
+		t.Errorf("Coverage() = %.2f, want 40", got)
+	}
+}
+
+func TestCoverageMap(t *testing.T) {
+
+	got := CoverageMap("MPEPTIDEKPEPTLDEK", []string{"PEPTIDEK", "DEKPEP"})
+	want := []int{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CoverageMap() = %v, want %v", got, want)
+	}
+}
+
+func TestPeptidePositions(t *testing.T) {
+
+	tests := []struct {
+		peptide string
+		want    []int
+	}{
+		{"PEPTIDEK", []int{2, 10}},
+		{"AAA", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.peptide, func(t *testing.T) {
+			if got := PeptidePositions("MPEPTIDEKPEPTLDEK", tt.peptide); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PeptidePositions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package bio
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	"philosopher/lib/msg"
@@ -12,33 +15,157 @@ type Enzyme struct {
 	Name    string
 	Pattern string
 	Join    string
+	Rules   []CleavageRule
+	Regex   *regexp.Regexp
 }
 
-// Synth is an enzyme builder
+// CleavageRule describes where an enzyme cuts. Residues in Sites are cleaved on their C-terminal side
+// when Terminus is "C" and on their N-terminal side when Terminus is "N". Residues in NoCut on the
+// other side of the bond block the cleavage
+type CleavageRule struct {
+	Sites    string
+	NoCut    string
+	Terminus string
+}
+
+// enzymeRules is the library of supported enzymes
+var enzymeRules = map[string][]CleavageRule{
+	"trypsin":      {{Sites: "KR", NoCut: "P", Terminus: "C"}},
+	"trypsin/p":    {{Sites: "KR", Terminus: "C"}},
+	"lys_c":        {{Sites: "K", NoCut: "P", Terminus: "C"}},
+	"lys_n":        {{Sites: "K", Terminus: "N"}},
+	"arg_c":        {{Sites: "R", NoCut: "P", Terminus: "C"}},
+	"asp_n":        {{Sites: "D", Terminus: "N"}},
+	"chymotrypsin": {{Sites: "FWYL", NoCut: "P", Terminus: "C"}},
+	"glu_c":        {{Sites: "DE", NoCut: "P", Terminus: "C"}},
+	"pepsin":       {{Sites: "FL", Terminus: "C"}},
+	"nonspecific":  {},
+}
+
+// customPrefix marks an enzyme defined by a regular expression
+const customPrefix = "custom:"
+
+// Synth is an enzyme builder. Enzymes can be combined with a plus sign (trypsin+glu_c) and custom
+// enzymes are defined by a regular expression matched against the two residues flanking each
+// bond, for example custom:[KR][^P]. An unknown enzyme is fatal
 func (e *Enzyme) Synth(t string) {
 
-	if strings.EqualFold(strings.ToLower(t), "trypsin") {
-		e.Name = "trypsin"
-		e.Pattern = "KR[^P]"
-		e.Join = "KR"
-	} else if strings.EqualFold(strings.ToLower(t), "lys_c") {
-		e.Name = "lys_c"
-		e.Pattern = "K[^P]"
-		e.Join = "K"
-	} else if strings.EqualFold(strings.ToLower(t), "lys_n") {
-		e.Name = "lys_n"
-		e.Pattern = "K"
-		e.Join = "K"
-	} else if strings.EqualFold(strings.ToLower(t), "chymotrypsin") {
-		e.Name = "chymotrypsin"
-		e.Pattern = "FWYL[^P]"
-		e.Join = "K"
-	} else if strings.EqualFold(strings.ToLower(t), "glu_c") {
-		e.Name = "glu_c"
-		e.Pattern = "DE[^P]"
-		e.Join = "K"
-	} else {
-		msg.Custom(errors.New("Enzyme not supported"), "warning")
+	enzyme, err := NewEnzyme(t)
+	if err != nil {
+		msg.Custom(err, "fatal")
+	}
+
+	*e = enzyme
+}
+
+// NewEnzyme builds an enzyme from its name, see Synth for the accepted names
+func NewEnzyme(t string) (Enzyme, error) {
+
+	var e Enzyme
+
+	name := strings.ToLower(strings.TrimSpace(t))
+
+	if strings.HasPrefix(name, customPrefix) {
+
+		pattern := strings.TrimSpace(t)[len(customPrefix):]
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return e, errors.New("the custom enzyme pattern is not a valid regular expression")
+		}
+
+		e.Name = "custom"
+		e.Pattern = pattern
+		e.Regex = re
+
+		return e, nil
+	}
+
+	var names []string
+	for _, i := range strings.Split(name, "+") {
+
+		rules, ok := enzymeRules[i]
+		if !ok {
+			return e, fmt.Errorf("enzyme %s not supported, use %s, combinations with + or custom:<regex>", i, strings.Join(enzymeNames(), ", "))
+		}
+
+		names = append(names, i)
+		e.Rules = append(e.Rules, rules...)
+	}
+
+	e.Name = strings.Join(names, "+")
+
+	var sites []string
+	var patterns []string
+	for _, i := range e.Rules {
+
+		sites = append(sites, i.Sites)
+
+		p := i.Sites
+		if len(i.NoCut) > 0 {
+			p += "[^" + i.NoCut + "]"
+		}
+		patterns = append(patterns, p)
+	}
+
+	e.Join = uniqueResidues(strings.Join(sites, ""))
+	e.Pattern = strings.Join(patterns, "|")
+
+	return e, nil
+}
+
+// Cleaves reports whether the enzyme cuts the bond between residues a and b
+func (e Enzyme) Cleaves(a, b byte) bool {
+
+	if e.Regex != nil {
+		return e.Regex.MatchString(string([]byte{a, b}))
+	}
+
+	for _, i := range e.Rules {
+		if i.Terminus == "N" {
+			if strings.IndexByte(i.Sites, b) >= 0 && strings.IndexByte(i.NoCut, a) < 0 {
+				return true
+			}
+		} else if strings.IndexByte(i.Sites, a) >= 0 && strings.IndexByte(i.NoCut, b) < 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNonSpecific reports whether the enzyme has no cleavage rules
+func (e Enzyme) IsNonSpecific() bool {
+	return e.Regex == nil && len(e.Rules) == 0
+}
+
+// uniqueResidues returns the distinct residues of a string in alphabetical order
+func uniqueResidues(s string) string {
+
+	var seen = make(map[rune]uint8)
+	var list []string
+
+	for _, i := range s {
+		if _, ok := seen[i]; !ok {
+			seen[i] = 0
+			list = append(list, string(i))
+		}
+	}
+
+	sort.Strings(list)
+
+	return strings.Join(list, "")
+}
+
+// enzymeNames returns the names in the enzyme library in alphabetical order
+func enzymeNames() []string {
+
+	var list []string
+	for k := range enzymeRules {
+		list = append(list, k)
 	}
 
+	sort.Strings(list)
+
+	return list
 }
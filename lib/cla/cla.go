@@ -3,8 +3,6 @@
 package cla
 
 import (
-	"strings"
-
 	"philosopher/lib/id"
 )
 
@@ -15,8 +13,9 @@ func IsDecoyPSM(p id.PeptideIdentification, tag string) bool {
 	// default for TRUE (DECOY)
 	// updated to FALSE
 	var class bool
+	var m = Matcher(tag)
 
-	if strings.HasPrefix(p.Protein, tag) {
+	if m.IsDecoy(p.Protein) {
 		class = true
 	} else {
 		class = false
@@ -26,7 +25,7 @@ func IsDecoyPSM(p id.PeptideIdentification, tag string) bool {
 	// only one evidence is enough to promote the PSM as a "no-decoy"
 	if len(p.AlternativeProteins) > 1 {
 		for i := range p.AlternativeProteins {
-			if !strings.HasPrefix(i, tag) {
+			if !m.IsDecoy(i) {
 				class = false
 				break
 			}
@@ -43,7 +42,7 @@ func IsDecoyProtein(p id.ProteinIdentification, tag string) bool {
 	// updated to FALSE
 	var class bool
 
-	if strings.HasPrefix(string(p.ProteinName), tag) {
+	if Matcher(tag).IsDecoy(string(p.ProteinName)) {
 		class = true
 	} else {
 		class = false
@@ -59,7 +58,7 @@ func IsDecoy(name string, tag string) bool {
 	// updated to FALSE
 	var class bool
 
-	if strings.HasPrefix(name, tag) {
+	if Matcher(tag).IsDecoy(name) {
 		class = true
 	} else {
 		class = false
@@ -73,9 +72,10 @@ func IsDecoyIon(names map[string]int, tag string) bool {
 
 	// default for TRUE ( DECOY)
 	var class = true
+	var m = Matcher(tag)
 
 	for i := range names {
-		if strings.HasPrefix(i, tag) {
+		if m.IsDecoy(i) {
 			class = true
 		} else {
 			class = false
@@ -0,0 +1,162 @@
+package cla
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"philosopher/lib/msg"
+)
+
+// decoy rule kinds, a rule without a kind is a prefix
+const (
+	prefixRule = "prefix"
+	suffixRule = "suffix"
+	regexRule  = "regex"
+)
+
+// decoyRule is a single way of marking a decoy name
+type decoyRule struct {
+	kind  string
+	tag   string
+	regex *regexp.Regexp
+}
+
+// DecoyMatcher recognizes decoy names and pairs them with their targets. It is built from the decoy tag,
+// a comma-separated list of rules where each rule is a prefix (rev_ or prefix:rev_), a suffix (suffix:_REVERSED)
+// or a regular expression matching the decoy marker (regex:^(rev|DECOY)_). Databases built with different
+// conventions are handled by listing one rule for each of them
+type DecoyMatcher struct {
+	rules []decoyRule
+}
+
+// matchers caches the decoy matchers by tag
+var matchers = make(map[string]DecoyMatcher)
+var matchersMutex sync.Mutex
+
+// NewDecoyMatcher parses a decoy tag into a matcher
+func NewDecoyMatcher(tag string) (DecoyMatcher, error) {
+
+	var self DecoyMatcher
+
+	for _, i := range strings.Split(tag, ",") {
+
+		var r decoyRule
+
+		switch {
+		case strings.HasPrefix(i, prefixRule+":"):
+			r.kind = prefixRule
+			r.tag = strings.TrimPrefix(i, prefixRule+":")
+		case strings.HasPrefix(i, suffixRule+":"):
+			r.kind = suffixRule
+			r.tag = strings.TrimPrefix(i, suffixRule+":")
+		case strings.HasPrefix(i, regexRule+":"):
+			exp, e := regexp.Compile(strings.TrimPrefix(i, regexRule+":"))
+			if e != nil {
+				return self, e
+			}
+			r.kind = regexRule
+			r.regex = exp
+		default:
+			r.kind = prefixRule
+			r.tag = i
+		}
+
+		self.rules = append(self.rules, r)
+	}
+
+	return self, nil
+}
+
+// Matcher returns the matcher for a decoy tag, an invalid tag is fatal
+func Matcher(tag string) DecoyMatcher {
+
+	matchersMutex.Lock()
+	defer matchersMutex.Unlock()
+
+	m, ok := matchers[tag]
+	if ok {
+		return m
+	}
+
+	m, e := NewDecoyMatcher(tag)
+	if e != nil {
+		msg.Custom(e, "fatal")
+	}
+
+	matchers[tag] = m
+
+	return m
+}
+
+// IsDecoy checks if the name carries any of the decoy markers
+func (m DecoyMatcher) IsDecoy(name string) bool {
+
+	for _, i := range m.rules {
+		if i.match(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Target returns the name of the target paired to a decoy, target names are returned unchanged
+func (m DecoyMatcher) Target(name string) string {
+
+	for _, i := range m.rules {
+		if !i.match(name) {
+			continue
+		}
+
+		switch i.kind {
+		case suffixRule:
+			return strings.TrimSuffix(name, i.tag)
+		case regexRule:
+			return i.regex.ReplaceAllString(name, "")
+		default:
+			return strings.TrimPrefix(name, i.tag)
+		}
+	}
+
+	return name
+}
+
+// Decoy returns the name of the decoy paired to a target using the first prefix or suffix rule. Regular
+// expressions can not be reversed, so the name is empty when the tag only has them
+func (m DecoyMatcher) Decoy(name string) string {
+
+	for _, i := range m.rules {
+		switch i.kind {
+		case prefixRule:
+			return i.tag + name
+		case suffixRule:
+			return name + i.tag
+		}
+	}
+
+	return ""
+}
+
+// match checks a name against a single rule
+func (r decoyRule) match(name string) bool {
+
+	switch r.kind {
+	case suffixRule:
+		return strings.HasSuffix(name, r.tag)
+	case regexRule:
+		return r.regex.MatchString(name)
+	}
+
+	return strings.HasPrefix(name, r.tag)
+}
+
+// TargetName returns the target paired to a decoy name
+func TargetName(name, tag string) string {
+	return Matcher(tag).Target(name)
+}
+
+// DecoyName returns the decoy paired to a target name
+func DecoyName(name, tag string) string {
+	return Matcher(tag).Decoy(name)
+}
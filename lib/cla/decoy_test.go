@@ -0,0 +1,53 @@
+package cla
+
+import "testing"
+
+func TestDecoyMatcher(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		tag     string
+		protein string
+		isDecoy bool
+		target  string
+		decoy   string
+	}{
+		{"Prefix decoy", "rev_", "rev_sp|P00001|A", true, "sp|P00001|A", "rev_rev_sp|P00001|A"},
+		{"Prefix target", "rev_", "sp|P00001|A", false, "sp|P00001|A", "rev_sp|P00001|A"},
+		{"Explicit prefix", "prefix:DECOY_", "DECOY_sp|P00001|A", true, "sp|P00001|A", "DECOY_DECOY_sp|P00001|A"},
+		{"Suffix decoy", "suffix:_REVERSED", "sp|P00001|A_REVERSED", true, "sp|P00001|A", "sp|P00001|A_REVERSED_REVERSED"},
+		{"Suffix target", "suffix:_REVERSED", "sp|P00001|A", false, "sp|P00001|A", "sp|P00001|A_REVERSED"},
+		{"Regex decoy", `regex:^(rev|DECOY)_`, "DECOY_sp|P00001|A", true, "sp|P00001|A", ""},
+		{"Regex target", `regex:^(rev|DECOY)_`, "sp|P00001|A", false, "sp|P00001|A", ""},
+		{"Mixed rules", "rev_,suffix:_REVERSED", "ENSP0001_REVERSED", true, "ENSP0001", "rev_ENSP0001_REVERSED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			m, e := NewDecoyMatcher(tt.tag)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if got := m.IsDecoy(tt.protein); got != tt.isDecoy {
+				t.Errorf("IsDecoy() = %v, want %v", got, tt.isDecoy)
+			}
+
+			if got := m.Target(tt.protein); got != tt.target {
+				t.Errorf("Target() = %v, want %v", got, tt.target)
+			}
+
+			if got := m.Decoy(tt.protein); got != tt.decoy {
+				t.Errorf("Decoy() = %v, want %v", got, tt.decoy)
+			}
+		})
+	}
+}
+
+func TestNewDecoyMatcherInvalidRegex(t *testing.T) {
+
+	if _, e := NewDecoyMatcher("regex:^(rev_"); e == nil {
+		t.Error("NewDecoyMatcher() expected an error for an invalid expression")
+	}
+}
@@ -0,0 +1,146 @@
+package dat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"philosopher/lib/cla"
+	"philosopher/lib/fas"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CrapLibrary is the name of the embedded cRAP contaminant library
+const CrapLibrary = "crap"
+
+// contaminant header prefixes, the first one is added with the contaminant tag option and the
+// second one is the MaxQuant convention for contaminant accessions
+const (
+	ContaminantTag = "contam_"
+	MaxQuantTag    = "CON__"
+)
+
+// maxQuantHeader matches the headers of the MaxQuant contaminants file, like P00761 SWISS-PROT:P00761|TRYP_PIG Trypsin
+var maxQuantHeader = regexp.MustCompile(`^\S+\s+(SWISS-PROT|TREMBL|REFSEQ|ENSEMBL|H-INV):`)
+
+// LoadContaminants reads the contaminant libraries in the given order. The embedded cRAP library is selected
+// by name and any other value is a FASTA file. MaxQuant-style entries get the CON__ prefix on their accession,
+// and sequences already present in a previous library are skipped
+func (d *Base) LoadContaminants(libraries []string, temp string, cTag bool) []fas.Entry {
+
+	if len(libraries) == 0 {
+		libraries = []string{CrapLibrary}
+	}
+
+	var contaminants []fas.Entry
+	var sequences = make(map[string]uint8)
+
+	for _, i := range libraries {
+
+		i = strings.TrimSpace(i)
+
+		var entries []fas.Entry
+		if strings.ToLower(i) == CrapLibrary {
+			d.Deploy(temp)
+			entries = readFasta(d.CrapDB)
+		} else {
+			entries = readFasta(i)
+		}
+
+		var added int
+		for _, j := range entries {
+
+			if _, ok := sequences[j.Sequence]; ok {
+				continue
+			}
+
+			if maxQuantHeader.MatchString(j.Header) && !strings.HasPrefix(j.Header, MaxQuantTag) {
+				j.Header = MaxQuantTag + j.Header
+			}
+
+			if cTag {
+				j.Header = ContaminantTag + j.Header
+			}
+
+			sequences[j.Sequence] = 0
+			contaminants = append(contaminants, j)
+			added++
+		}
+
+		logrus.Info("Added ", added, " contaminants from ", i)
+	}
+
+	return contaminants
+}
+
+// addContaminants merges the contaminants into the database. Targets with the same sequence as a contaminant
+// are replaced by it, and the contaminant part headers are recorded to flag their records
+func (d *Base) addContaminants(db, contaminants []fas.Entry) []fas.Entry {
+
+	var sequences = make(map[string]uint8)
+	for _, i := range contaminants {
+		sequences[i.Sequence] = 0
+	}
+
+	var kept []fas.Entry
+	var removed int
+	for _, i := range db {
+		if _, ok := sequences[i.Sequence]; ok {
+			removed++
+			continue
+		}
+		kept = append(kept, i)
+	}
+
+	if removed > 0 {
+		logrus.Info(fmt.Sprintf("Replaced %d target entries identical to contaminant sequences", removed))
+	}
+
+	// every downloaded file gets the same contaminants, they are recorded once
+	d.indexContaminants()
+	for _, i := range contaminants {
+		a := i.Accession()
+		if _, ok := d.contaminants[a]; !ok {
+			d.contaminants[a] = struct{}{}
+			d.Contaminants = append(d.Contaminants, a)
+		}
+	}
+
+	return mergeEntries(kept, contaminants)
+}
+
+// IsContaminant checks if a header belongs to a contaminant, by its prefix or by the contaminant list of the database
+func (d *Base) IsContaminant(header, decoyTag string) bool {
+
+	h := cla.TargetName(header, decoyTag)
+
+	if strings.HasPrefix(h, ContaminantTag) || strings.HasPrefix(h, MaxQuantTag) {
+		return true
+	}
+
+	d.indexContaminants()
+	_, ok := d.contaminants[strings.Split(h, " ")[0]]
+
+	return ok
+}
+
+// indexContaminants builds the lookup of the recorded contaminant accessions. The list is also restored from the
+// meta data, so the index is rebuilt, and the list deduplicated, whenever the index does not cover the whole list
+func (d *Base) indexContaminants() {
+
+	if d.contaminants != nil && len(d.contaminants) == len(d.Contaminants) {
+		return
+	}
+
+	accessions := make([]string, 0, len(d.Contaminants))
+	d.contaminants = make(map[string]struct{}, len(d.Contaminants))
+	for _, i := range d.Contaminants {
+		if _, ok := d.contaminants[i]; !ok {
+			d.contaminants[i] = struct{}{}
+			accessions = append(accessions, i)
+		}
+	}
+
+	d.Contaminants = accessions
+}
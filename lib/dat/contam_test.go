@@ -0,0 +1,193 @@
+package dat_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	. "philosopher/lib/dat"
+	"philosopher/lib/fas"
+	"philosopher/lib/met"
+	"philosopher/lib/sys"
+	"reflect"
+	"testing"
+)
+
+func TestBase_LoadContaminants(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "contam")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	maxquant := filepath.Join(dir, "contaminants.fasta")
+	ioutil.WriteFile(maxquant, []byte(">P00761 SWISS-PROT:P00761|TRYP_PIG Trypsin - Sus scrofa (Pig).\nFPTDDDDKIVGGYTCAANSIPYQVSLNSGSHFCGGSLINSQWVVSAAHCYK\n>Streptavidin (S.avidinii)\nDPSKDSKAQVSAAEAGITGTWYNQLGSTFIVTAGADGALTGTYESAVGNAESR\n"), 0644)
+
+	custom := filepath.Join(dir, "custom.fas")
+	ioutil.WriteFile(custom, []byte(">sp|P00001|CONT_HUMAN Contaminant\nMPEPTIDEK\n>sp|P00761|TRYP_PIG Trypsin\nFPTDDDDKIVGGYTCAANSIPYQVSLNSGSHFCGGSLINSQWVVSAAHCYK\n"), 0644)
+
+	tests := []struct {
+		name      string
+		libraries []string
+		cTag      bool
+		want      []string
+	}{
+		{"MaxQuant library", []string{maxquant}, false, []string{"CON__P00761", "Streptavidin"}},
+		{"Libraries are merged by sequence", []string{maxquant, custom}, false, []string{"CON__P00761", "Streptavidin", "sp|P00001|CONT_HUMAN"}},
+		{"Contaminant tag", []string{custom}, true, []string{"contam_sp|P00001|CONT_HUMAN", "contam_sp|P00761|TRYP_PIG"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			d := New()
+
+			var got []string
+			for _, i := range d.LoadContaminants(tt.libraries, dir, tt.cTag) {
+				got = append(got, i.Accession())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadContaminants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBase_IsContaminant(t *testing.T) {
+
+	d := New()
+	d.Contaminants = []string{"sp|P00761|TRYP_PIG"}
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"sp|P00761|TRYP_PIG Trypsin", true},
+		{"rev_sp|P00761|TRYP_PIG Trypsin", true},
+		{"contam_sp|P02768|ALBU_HUMAN Albumin", true},
+		{"CON__P02768 SWISS-PROT:P02768", true},
+		{"sp|P02768|ALBU_HUMAN Albumin", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := d.IsContaminant(tt.header, "rev_"); got != tt.want {
+				t.Errorf("IsContaminant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBase_CreateReplacesIdenticalTargets(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "contam")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	target := filepath.Join(dir, "target.fas")
+	ioutil.WriteFile(target, []byte(">sp|P00002|PROT_HUMAN Protein\nMKVLLAGGR\n>sp|P00003|KRT1_HUMAN Keratin\nMPEPTIDEK\n"), 0644)
+
+	library := filepath.Join(dir, "library.fas")
+	ioutil.WriteFile(library, []byte(">sp|P00001|K1C_CONT Keratin\nMPEPTIDEK\n"), 0644)
+
+	d := New()
+	d.DownloadedFiles = []string{target}
+	d.ContamLibs = []string{library}
+	d.Create(dir, "", "", "", "", "trypsin", "rev_", ReverseMethod, 0, true, true, false)
+
+	want := []fas.Entry{
+		{Header: "sp|P00002|PROT_HUMAN Protein", Sequence: "MKVLLAGGR"},
+		{Header: "sp|P00001|K1C_CONT Keratin", Sequence: "MPEPTIDEK"},
+	}
+
+	if !reflect.DeepEqual(d.TaDeDB, want) {
+		t.Errorf("Create() = %v, want %v", d.TaDeDB, want)
+	}
+
+	if !reflect.DeepEqual(d.Contaminants, []string{"sp|P00001|K1C_CONT"}) {
+		t.Errorf("Contaminants = %v, want [sp|P00001|K1C_CONT]", d.Contaminants)
+	}
+}
+
+func TestBase_CreateRecordsContaminantsOnce(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "contam")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	first := filepath.Join(dir, "first.fas")
+	ioutil.WriteFile(first, []byte(">sp|P00002|PROT_HUMAN Protein\nMKVLLAGGR\n"), 0644)
+
+	second := filepath.Join(dir, "second.fas")
+	ioutil.WriteFile(second, []byte(">sp|P00003|PROT_MOUSE Protein\nMKVLLAGGK\n"), 0644)
+
+	library := filepath.Join(dir, "library.fas")
+	ioutil.WriteFile(library, []byte(">sp|P00001|K1C_CONT Keratin\nMPEPTIDEK\n"), 0644)
+
+	d := New()
+	d.DownloadedFiles = []string{first, second}
+	d.ContamLibs = []string{library}
+	d.Create(dir, "", "", "", "", "trypsin", "rev_", ReverseMethod, 0, true, true, false)
+
+	if !reflect.DeepEqual(d.Contaminants, []string{"sp|P00001|K1C_CONT"}) {
+		t.Errorf("Contaminants = %v, want [sp|P00001|K1C_CONT]", d.Contaminants)
+	}
+
+	// a list restored with repeated accessions is deduplicated by the lookup
+	d = New()
+	d.Contaminants = []string{"sp|P00001|K1C_CONT", "sp|P00001|K1C_CONT"}
+
+	if !d.IsContaminant("sp|P00001|K1C_CONT Keratin", "rev_") || len(d.Contaminants) != 1 {
+		t.Errorf("IsContaminant() with repeated accessions, Contaminants = %v", d.Contaminants)
+	}
+}
+
+func TestRun_AnnotateFlagsRecordedContaminants(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "contam")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	os.Chdir(dir)
+	os.Mkdir(sys.MetaDir(), 0755)
+
+	database := filepath.Join(dir, "database.fas")
+	ioutil.WriteFile(database, []byte(">sp|P00002|PROT_HUMAN Protein\nMKVLLAGGR\n>sp|P00761|TRYP_PIG Trypsin\nFPTDDDDKIVGGYTCAANSIPYQVSLNSGSHFCGGSLINSQWVVSAAHCYK\n>rev_sp|P00761|TRYP_PIG Trypsin\nKYCHAASVVWQSNILSGGCFHSGSNLSVQYPISNAACTYGGVIKDDDDTPF\n"), 0644)
+
+	// the accessions recorded on the workspace when the database was created
+	var m met.Data
+	m.Database.Annot = database
+	m.Database.Tag = "rev_"
+	m.Database.Contaminants = []string{"sp|P00761|TRYP_PIG"}
+
+	Run(m)
+
+	var d Base
+	d.Restore()
+
+	want := map[string]bool{
+		"sp|P00002|PROT_HUMAN":   false,
+		"sp|P00761|TRYP_PIG":     true,
+		"rev_sp|P00761|TRYP_PIG": true,
+	}
+
+	if len(d.Records) != len(want) {
+		t.Fatalf("Run() annotated %d records, want %d", len(d.Records), len(want))
+	}
+
+	for _, i := range d.Records {
+		if i.IsContaminant != want[i.PartHeader] {
+			t.Errorf("%s IsContaminant = %v, want %v", i.PartHeader, i.IsContaminant, want[i.PartHeader])
+		}
+	}
+}
@@ -4,13 +4,10 @@ package dat
 import (
 	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -31,8 +28,12 @@ type Base struct {
 	CrapDB          string
 	Prefix          string
 	DownloadedFiles []string
-	TaDeDB          map[string]string
+	Templates       []string
+	ContamLibs      []string
+	Contaminants    []string
+	TaDeDB          []fas.Entry
 	Records         []Record
+	contaminants    map[string]struct{}
 }
 
 // New constructor
@@ -40,7 +41,7 @@ func New() Base {
 
 	var self Base
 
-	self.TaDeDB = make(map[string]string)
+	self.TaDeDB = []fas.Entry{}
 	self.Records = []Record{}
 
 	return self
@@ -51,10 +52,68 @@ func Run(m met.Data) met.Data {
 
 	var db = New()
 
-	if len(m.Database.ID) == 0 && (len(m.Database.Annot) == 0 || m.Database.Annot == "--contam" || m.Database.Annot == "--prefix") && (len(m.Database.Custom) == 0 || m.Database.Custom == "--contam" || m.Database.Custom == "--prefix") {
+	if len(m.Database.Template) > 0 {
+		m.Database.Templates = LoadTemplates(m.Database.Template)
+	}
+
+	db.Templates = m.Database.Templates
+
+	// contaminants without a tagged header are only known by the accessions recorded when the database was created
+	db.Contaminants = m.Database.Contaminants
+
+	if len(m.Database.ContamLib) > 0 {
+		db.ContamLibs = strings.Split(m.Database.ContamLib, ",")
+		m.Database.Crap = true
+	}
+
+	if m.Database.DryRun {
+
+		file := m.Database.Annot
+		if len(file) == 0 {
+			file = m.Database.Custom
+		}
+
+		if len(file) == 0 {
+			msg.InputNotFound(errors.New("provide a protein FASTA file with --annotate or --custom for the dry run"), "fatal")
+		}
+
+		db.DryRun(file, m.Database.Tag, os.Stdout)
+
+		return m
+	}
+
+	if !m.Database.Digest && len(m.Database.ID) == 0 && len(m.Database.Local) == 0 && (len(m.Database.Annot) == 0 || m.Database.Annot == "--contam" || m.Database.Annot == "--prefix") && (len(m.Database.Custom) == 0 || m.Database.Custom == "--contam" || m.Database.Custom == "--prefix") {
 		msg.InputNotFound(errors.New("provide a protein FASTA file or Proteome ID"), "fatal")
 	}
 
+	if m.Database.Digest {
+
+		if len(m.Database.Annot) > 0 {
+			db.ProcessDB(m.Database.Annot, m.Database.Tag)
+		} else if len(m.Database.Custom) > 0 {
+			db.ProcessDB(m.Database.Custom, m.Database.Tag)
+		} else {
+			db.Restore()
+		}
+
+		if len(db.Records) == 0 {
+			msg.InputNotFound(errors.New("provide a protein FASTA file or annotate a database before the digestion"), "fatal")
+		}
+
+		logrus.Info("Digesting the database")
+		dig := db.Digest(m.Database.Enz, m.Database.Specificity, m.Database.Missed, m.Database.MinLen, m.Database.MaxLen)
+		dig.Summary()
+
+		dig.WriteSummary(m.Home)
+		dig.WriteProteins(m.Home)
+
+		if m.Database.Peptides {
+			dig.WritePeptides(m.Home)
+		}
+
+		return m
+	}
+
 	if len(m.Database.Annot) > 0 {
 
 		logrus.Info("Annotating the database")
@@ -68,8 +127,8 @@ func Run(m met.Data) met.Data {
 		return m
 	}
 
-	if len(m.Database.ID) < 1 && len(m.Database.Custom) < 1 {
-		msg.InputNotFound(errors.New("you need to provide a taxon ID or a custom FASTA file"), "fatal")
+	if len(m.Database.ID) < 1 && len(m.Database.Custom) < 1 && len(m.Database.Local) < 1 {
+		msg.InputNotFound(errors.New("you need to provide a taxon ID, a local proteome or a custom FASTA file"), "fatal")
 	}
 
 	if !m.Database.Crap {
@@ -82,12 +141,21 @@ func Run(m met.Data) met.Data {
 
 		dbs := strings.Split(m.Database.ID, ",")
 		for _, i := range dbs {
-			logrus.Info("Fetching database ", i)
 
 			currentTime := time.Now()
 			m.Database.TimeStamp = currentTime.Format("2006.01.02 15:04:05")
 
-			db.Fetch(i, m.Temp, m.Database.Iso, m.Database.Rev)
+			if len(m.Database.Local) > 0 {
+				logrus.Info("Reading local database ", i)
+				db.FetchLocal(i, m.Database.Local, m.Temp, m.Database.Iso, m.Database.Rev)
+			} else {
+				logrus.Info("Fetching database ", i)
+				m.Database.Release = db.Fetch(i, m.Temp, m.Database.URL, m.Database.Iso, m.Database.Rev)
+
+				if len(m.Database.Release) > 0 {
+					logrus.Info("UniProt release ", m.Database.Release)
+				}
+			}
 		}
 
 	} else {
@@ -96,8 +164,17 @@ func Run(m met.Data) met.Data {
 		db.DownloadedFiles = append(db.DownloadedFiles, dbPath)
 	}
 
-	logrus.Info("Generating the target-decoy database")
-	db.Create(m.Temp, m.Database.Add, m.Database.Enz, m.Database.Tag, m.Database.Crap, m.Database.NoD, m.Database.CrapTag)
+	if len(m.Database.DecoyMethod) == 0 {
+		m.Database.DecoyMethod = ReverseMethod
+	}
+
+	// a random seed is drawn and recorded when none is given so the decoys can be rebuilt
+	if m.Database.DecoySeed == 0 && (m.Database.DecoyMethod == ShuffleMethod || m.Database.DecoyMethod == DeBruijnMethod) {
+		m.Database.DecoySeed = time.Now().UnixNano()
+	}
+
+	logrus.Info("Generating the target-decoy database using the ", m.Database.DecoyMethod, " decoy method")
+	db.Create(m.Temp, m.Database.Add, m.Database.Variant, m.Database.Novel, m.Database.Entrapment, m.Database.Enz, m.Database.Tag, m.Database.DecoyMethod, m.Database.DecoySeed, m.Database.Crap, m.Database.NoD, m.Database.CrapTag)
 
 	logrus.Info("Creating file")
 	customDB := db.Save(m.Home, m.Temp, m.Database.ID, m.Database.Tag, m.Database.Rev, m.Database.Iso, m.Database.NoD, m.Database.Crap)
@@ -105,78 +182,71 @@ func Run(m met.Data) met.Data {
 	db.ProcessDB(customDB, m.Database.Tag)
 
 	logrus.Info("Processing decoys")
-	db.Create(m.Temp, m.Database.Add, m.Database.Enz, m.Database.Tag, m.Database.Crap, m.Database.NoD, m.Database.CrapTag)
+	db.Create(m.Temp, m.Database.Add, m.Database.Variant, m.Database.Novel, m.Database.Entrapment, m.Database.Enz, m.Database.Tag, m.Database.DecoyMethod, m.Database.DecoySeed, m.Database.Crap, m.Database.NoD, m.Database.CrapTag)
 
 	logrus.Info("Creating file")
 	db.Save(m.Home, m.Temp, m.Database.ID, m.Database.Tag, m.Database.Rev, m.Database.Iso, m.Database.NoD, m.Database.Crap)
 
 	db.Prefix = m.Database.Tag
 
+	m.Database.Contaminants = db.Contaminants
+
 	db.Serialize()
 
 	return m
 }
 
-// ProcessDB determines the type of sequence and sends it to the appropriate parsing function
+// ProcessDB determines the type of sequence and sends it to the appropriate parsing function,
+// user-defined header templates are tried before the built-in parsers. Records keep the file order
 func (d *Base) ProcessDB(file, decoyTag string) {
 
-	fastaMap := fas.ParseFile(file)
+	entries := readFasta(file)
 	d.FileName = path.Base(file)
 
-	for k, v := range fastaMap {
+	reportDuplicates(entries)
 
-		class := Classify(k, decoyTag)
+	templates := CompileTemplates(d.Templates)
 
-		if class == "uniprot" {
+	for _, i := range entries {
 
-			db := ProcessUniProtKB(k, v, decoyTag)
-			d.Records = append(d.Records, db)
-
-		} else if class == "ncbi" {
-
-			db := ProcessNCBI(k, v, decoyTag)
-			d.Records = append(d.Records, db)
-
-		} else if class == "ensembl" {
-
-			db := ProcessENSEMBL(k, v, decoyTag)
-			d.Records = append(d.Records, db)
-
-		} else if class == "generic" {
-
-			db := ProcessGeneric(k, v, decoyTag)
-			d.Records = append(d.Records, db)
-
-		} else if class == "uniref" {
-
-			db := ProcessUniRef(k, v, decoyTag)
-			d.Records = append(d.Records, db)
-
-		} else {
-			msg.ParsingFASTA(errors.New(""), "fatal")
+		k, v := i.Header, i.Sequence
+		db, _, ok := parseWithTemplates(templates, k, v, decoyTag)
+		if !ok {
+			db = processHeader(Classify(k, decoyTag), k, v, decoyTag)
 		}
+
+		db.Class = SequenceClass(k, decoyTag)
+		db.IsContaminant = d.IsContaminant(k, decoyTag)
+		db.IsEntrapment = IsEntrapment(k, decoyTag)
+		d.Records = append(d.Records, db)
 	}
 
 }
 
-// Fetch downloads a database file from UniProt
-func (d *Base) Fetch(id, temp string, iso, rev bool) {
+// processHeader parses a header with the built-in parser for its database type
+func processHeader(class, k, v, decoyTag string) Record {
+
+	if class == "uniprot" {
+		return ProcessUniProtKB(k, v, decoyTag)
+	} else if class == "ncbi" {
+		return ProcessNCBI(k, v, decoyTag)
+	} else if class == "ensembl" {
+		return ProcessENSEMBL(k, v, decoyTag)
+	} else if class == "generic" {
+		return ProcessGeneric(k, v, decoyTag)
+	} else if class == "uniref" {
+		return ProcessUniRef(k, v, decoyTag)
+	}
 
-	var query string
+	msg.ParsingFASTA(errors.New(""), "fatal")
 
-	d.UniProtDB = fmt.Sprintf("%s%s%s.fas", temp, string(filepath.Separator), id)
+	return Record{}
+}
 
-	if rev {
-		query = fmt.Sprintf("%s%s%s", "http://www.uniprot.org/uniprot/?query=reviewed:yes+AND+proteome:", id, "&format=fasta")
-	} else {
-		query = fmt.Sprintf("%s%s%s", "http://www.uniprot.org/uniprot/?query=proteome:", id, "&format=fasta")
-	}
+// Fetch downloads a database file from UniProt and returns the UniProt release
+func (d *Base) Fetch(id, temp, base string, iso, rev bool) string {
 
-	if iso {
-		query = fmt.Sprintf("%s&include=yes", query)
-	} else {
-		query = fmt.Sprintf("%s&include=no", query)
-	}
+	d.UniProtDB = fmt.Sprintf("%s%s%s.fas", temp, string(filepath.Separator), id)
 
 	// tries to create an output file
 	output, e := os.Create(d.UniProtDB)
@@ -185,84 +255,119 @@ func (d *Base) Fetch(id, temp string, iso, rev bool) {
 	}
 	defer output.Close()
 
-	// Tries to query data from Uniprot
-	response, e := http.Get(query)
+	client := NewUniProtClient(base)
+
+	entries, e := client.Download(client.ProteomeQuery(id, iso, rev), output)
 	if e != nil {
-		msg.Custom(errors.New("uniProt query failed, please check your connection"), "fatal")
+		msg.Custom(fmt.Errorf("UniProt download failed, please check your connection: %s", e), "fatal")
 	}
 
-	if response.ContentLength != -1 {
+	if entries == 0 {
 		msg.Custom(errors.New("no sequences downloaded, check your proteome ID and parameters"), "fatal")
 	}
-	defer response.Body.Close()
-
-	// Tries to download data from Uniprot
-	_, e = io.Copy(output, response.Body)
-	if e != nil {
-		msg.Custom(errors.New("UniProt download failed, please check your connection"), "fatal")
-	}
 
 	d.DownloadedFiles = append(d.DownloadedFiles, d.UniProtDB)
 
+	return client.Release
 }
 
-// Create processes the given fasta file and add decoy sequences
-func (d *Base) Create(temp, add, enz, tag string, crap, noD, cTag bool) {
+// Create processes the given fasta file and add decoy sequences, entries keep the order of the input files
+func (d *Base) Create(temp, add, variant, novel, entrapment, enz, tag, method string, seed int64, crap, noD, cTag bool) {
 
-	d.TaDeDB = make(map[string]string)
+	d.TaDeDB = []fas.Entry{}
+	d.Contaminants = []string{}
+	d.contaminants = make(map[string]struct{})
 
 	for _, i := range d.DownloadedFiles {
 
 		dbfile, _ := filepath.Abs(i)
-		db := fas.ParseFile(dbfile)
+		db := readFasta(dbfile)
 
 		if len(add) > 0 {
-			add := fas.ParseFile(add)
+			db = mergeEntries(db, readFasta(add))
+		}
 
-			for k, v := range add {
-				db[k] = v
-			}
+		// proteogenomic sequences keep their provenance on the header
+		if len(variant) > 0 {
+			db = addClassified(db, variant, VariantTag)
+		}
+
+		if len(novel) > 0 {
+			db = addClassified(db, novel, NovelTag)
+		}
+
+		// foreign sequences that can only produce false discoveries, used to check the FDR estimation
+		if len(entrapment) > 0 {
+			db = addClassified(db, entrapment, EntrapmentTag)
 		}
 
 		// adding contaminants to database before reversion
-		// repeated entries are removed and substituted by contaminants
+		// targets identical to a contaminant sequence are removed and substituted by the contaminants
 		if crap {
+			db = d.addContaminants(db, d.LoadContaminants(d.ContamLibs, temp, cTag))
+		}
 
-			d.Deploy(temp)
+		generator := NewDecoyGenerator(method, enz, seed, db)
 
-			crapMap := fas.ParseFile(d.CrapDB)
+		d.TaDeDB = append(d.TaDeDB, db...)
 
-			for k, v := range crapMap {
+		if !noD {
+			for _, j := range db {
+				d.TaDeDB = append(d.TaDeDB, fas.Entry{Header: tag + j.Header, Sequence: generator.Generate(j.Sequence)})
+			}
+		}
 
-				if cTag {
-					k = "contam_" + k
-				}
+	}
 
-				split := strings.Split(k, "|")
-				for i := range db {
-					if strings.Contains(i, split[1]) {
-						delete(db, i)
-					}
-				}
-				db[k] = v
-			}
+}
 
-		}
+// readFasta reads the entries of a FASTA file in order
+func readFasta(file string) []fas.Entry {
+
+	entries, e := fas.ReadFile(file)
+	if e != nil {
+		msg.ReadFile(e, "fatal")
+	}
 
-		for h, s := range db {
+	return entries
+}
 
-			th := ">" + h
-			d.TaDeDB[th] = s
+// mergeEntries adds the extra entries to the list, an entry with a header already on the list replaces it
+func mergeEntries(entries, extra []fas.Entry) []fas.Entry {
 
-			if !noD {
-				dh := ">" + tag + h
-				d.TaDeDB[dh] = reverseSeq(s)
-			}
+	var index = make(map[string]int)
+	for n, i := range entries {
+		index[i.Header] = n
+	}
 
+	for _, i := range extra {
+		if n, ok := index[i.Header]; ok {
+			entries[n] = i
+		} else {
+			index[i.Header] = len(entries)
+			entries = append(entries, i)
 		}
+	}
 
+	return entries
+}
+
+// reportDuplicates warns about repeated accessions and entries with identical sequences
+func reportDuplicates(entries []fas.Entry) {
+
+	dup := fas.FindDuplicates(entries)
+
+	if len(dup.Accessions) > 0 {
+		msg.Custom(fmt.Errorf("%d accessions are used by more than one entry: %s", len(dup.Accessions), strings.Join(dup.Accessions, ", ")), "warning")
 	}
 
+	if len(dup.Sequences) > 0 {
+		var entries int
+		for _, i := range dup.Sequences {
+			entries += len(i)
+		}
+		logrus.Info(entries, " entries share their sequence with another entry in ", len(dup.Sequences), " groups")
+	}
 }
 
 // Deploy crap file to session folder
@@ -324,21 +429,19 @@ func (d *Base) Save(home, temp, ids, tag string, isRev, hasIso, noD, Crap bool)
 	}
 	defer file.Close()
 
-	var headers []string
-	for k := range d.TaDeDB {
-		headers = append(headers, k)
-	}
-
-	sort.Strings(headers)
-
-	for _, i := range headers {
-		line := i + "\n" + d.TaDeDB[i] + "\n"
-		_, e = io.WriteString(file, line)
+	w := fas.NewWriter(file)
+	for _, i := range d.TaDeDB {
+		e = w.Write(i)
 		if e != nil {
 			msg.WriteFile(e, "fatal")
 		}
 	}
 
+	e = w.Flush()
+	if e != nil {
+		msg.WriteFile(e, "fatal")
+	}
+
 	sys.CopyFile(workfile, outfile)
 
 	return outfile
@@ -1,44 +1,56 @@
 package dat_test
 
 import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	. "philosopher/lib/dat"
+	"philosopher/lib/fas"
 	"philosopher/lib/sys"
+	"strings"
 	"testing"
 )
 
 func TestBase_Fetch(t *testing.T) {
-	type fields struct {
-		UniProtDB string
-		CrapDB    string
-		TaDeDB    map[string]string
-		Records   []Record
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Query().Get("query") != "proteome:UP000005640 AND reviewed:true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("X-UniProt-Release", "2022_05")
+		io.WriteString(w, ">sp|P00001|A_HUMAN Protein A\nMPEPTIDEK\n>sp|P00002|B_HUMAN Protein B\nAAGLSR\n")
+	}))
+	defer server.Close()
+
+	temp, e := ioutil.TempDir("", "fetch")
+	if e != nil {
+		t.Fatal(e)
 	}
-	type args struct {
-		id   string
-		temp string
-		iso  bool
-		rev  bool
+	defer os.RemoveAll(temp)
+
+	var d Base
+
+	release := d.Fetch("UP000005640", temp, server.URL, false, true)
+	if release != "2022_05" {
+		t.Errorf("Fetch() release = %v, want %v", release, "2022_05")
 	}
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-	}{
-		{
-			name: "Download human proteome",
-			args: args{id: "UP000005640", temp: sys.GetTemp(), iso: false, rev: true},
-		},
+
+	b, e := ioutil.ReadFile(d.UniProtDB)
+	if e != nil {
+		t.Fatal(e)
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			d := &Base{
-				UniProtDB: tt.fields.UniProtDB,
-				CrapDB:    tt.fields.CrapDB,
-				TaDeDB:    tt.fields.TaDeDB,
-				Records:   tt.fields.Records,
-			}
-			d.Fetch(tt.args.id, tt.args.temp, tt.args.iso, tt.args.rev)
-		})
+
+	if strings.Count(string(b), ">") != 2 {
+		t.Errorf("Fetch() wrote %d entries, want %d", strings.Count(string(b), ">"), 2)
+	}
+
+	if len(d.DownloadedFiles) != 1 || d.DownloadedFiles[0] != d.UniProtDB {
+		t.Errorf("Fetch() downloaded files = %v, want %v", d.DownloadedFiles, []string{d.UniProtDB})
 	}
 }
 
@@ -46,7 +58,7 @@ func TestBase_ProcessDB(t *testing.T) {
 	type fields struct {
 		UniProtDB string
 		CrapDB    string
-		TaDeDB    map[string]string
+		TaDeDB    []fas.Entry
 		Records   []Record
 	}
 	type args struct {
@@ -65,6 +77,12 @@ func TestBase_ProcessDB(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+
+			// the proteome is no longer downloaded by the tests
+			if _, e := os.Stat(tt.args.file); e != nil {
+				t.Skip("human proteome not available at ", tt.args.file)
+			}
+
 			d := &Base{
 				UniProtDB: tt.fields.UniProtDB,
 				CrapDB:    tt.fields.CrapDB,
@@ -84,7 +102,7 @@ func TestBase_Deploy(t *testing.T) {
 	type fields struct {
 		UniProtDB string
 		CrapDB    string
-		TaDeDB    map[string]string
+		TaDeDB    []fas.Entry
 		Records   []Record
 	}
 	type args struct {
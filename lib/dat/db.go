@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"philosopher/lib/cla"
 	"philosopher/lib/msg"
 )
 
@@ -22,9 +23,11 @@ type Record struct {
 	SequenceVersion  string
 	Description      string
 	Sequence         string
+	Class            string
 	Length           int
 	IsDecoy          bool
 	IsContaminant    bool
+	IsEntrapment     bool
 }
 
 // ProcessENSEMBL parses ENSEMBL like FASTA records
@@ -93,7 +96,7 @@ func ProcessENSEMBL(k, v, decoyTag string) Record {
 	// Length
 	e.Length = len(v)
 
-	if strings.HasPrefix(k, decoyTag) {
+	if cla.Matcher(decoyTag).IsDecoy(e.PartHeader) {
 		e.IsDecoy = true
 	} else {
 		e.IsDecoy = false
@@ -187,7 +190,7 @@ func ProcessNCBI(k, v, decoyTag string) Record {
 	// Length
 	e.Length = len(v)
 
-	if strings.HasPrefix(k, decoyTag) {
+	if cla.Matcher(decoyTag).IsDecoy(e.PartHeader) {
 		e.IsDecoy = true
 	} else {
 		e.IsDecoy = false
@@ -321,7 +324,7 @@ func ProcessUniProtKB(k, v, decoyTag string) Record {
 	e.Sequence = v
 	e.Length = len(v)
 
-	if strings.HasPrefix(k, decoyTag) {
+	if cla.Matcher(decoyTag).IsDecoy(e.PartHeader) {
 		e.IsDecoy = true
 	} else {
 		e.IsDecoy = false
@@ -372,7 +375,7 @@ func ProcessUniRef(k, v, decoyTag string) Record {
 	e.Sequence = v
 	e.Length = len(v)
 
-	if strings.HasPrefix(k, decoyTag) {
+	if cla.Matcher(decoyTag).IsDecoy(e.PartHeader) {
 		e.IsDecoy = true
 	} else {
 		e.IsDecoy = false
@@ -414,7 +417,7 @@ func ProcessGeneric(k, v, decoyTag string) Record {
 
 	e.PartHeader = part[0]
 
-	if strings.HasPrefix(k, decoyTag) {
+	if cla.Matcher(decoyTag).IsDecoy(e.PartHeader) {
 		e.IsDecoy = true
 	} else {
 		e.IsDecoy = false
@@ -427,8 +430,13 @@ func ProcessGeneric(k, v, decoyTag string) Record {
 func Classify(s, decoyTag string) string {
 
 	// remove the decoy and contamintant tags so we can see better the seq header
-	seq := strings.Replace(s, decoyTag, "", -1)
+	seq := cla.TargetName(s, decoyTag)
 	seq = strings.Replace(seq, "con_", "", -1)
+	seq = strings.TrimPrefix(seq, ContaminantTag)
+	seq = strings.TrimPrefix(seq, MaxQuantTag)
+	seq = strings.TrimPrefix(seq, EntrapmentTag)
+	seq = strings.TrimPrefix(seq, VariantTag)
+	seq = strings.TrimPrefix(seq, NovelTag)
 
 	if strings.HasPrefix(seq, "sp|") || strings.HasPrefix(seq, "tr|") || strings.HasPrefix(seq, "db|") {
 		return "uniprot"
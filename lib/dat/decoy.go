@@ -0,0 +1,232 @@
+package dat
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+
+	"philosopher/lib/bio"
+	"philosopher/lib/fas"
+	"philosopher/lib/msg"
+)
+
+// decoy generation methods
+const (
+	ReverseMethod       = "reverse"
+	PseudoReverseMethod = "pseudo-reverse"
+	ShuffleMethod       = "shuffle"
+	DeBruijnMethod      = "debruijn"
+)
+
+// maxShuffleAttempts is the number of times a peptide is reshuffled before falling back to its pseudo-reversed form
+const maxShuffleAttempts = 10
+
+// DecoyGenerator builds decoy sequences from targets using one of the supported methods
+type DecoyGenerator struct {
+	Method  string
+	Seed    int64
+	enzyme  bio.Enzyme
+	random  *rand.Rand
+	targets map[string]uint8
+}
+
+// NewDecoyGenerator constructor. The target sequences are digested to check shuffled peptides for collisions
+func NewDecoyGenerator(method, enz string, seed int64, targets []fas.Entry) DecoyGenerator {
+
+	var self DecoyGenerator
+
+	switch method {
+	case "", ReverseMethod:
+		self.Method = ReverseMethod
+	case PseudoReverseMethod, ShuffleMethod, DeBruijnMethod:
+		self.Method = method
+	default:
+		msg.Custom(errors.New("decoy method not supported, use reverse, pseudo-reverse, shuffle or debruijn"), "fatal")
+	}
+
+	self.enzyme.Synth(enz)
+
+	self.Seed = seed
+	self.random = rand.New(rand.NewSource(seed))
+
+	self.targets = make(map[string]uint8)
+	if self.Method == ShuffleMethod {
+		for _, i := range targets {
+			for _, p := range self.segments(i.Sequence) {
+				self.targets[equateIL(p)] = 0
+			}
+		}
+	}
+
+	return self
+}
+
+// Generate returns the decoy version of a target sequence
+func (g *DecoyGenerator) Generate(s string) string {
+
+	switch g.Method {
+	case PseudoReverseMethod:
+		return g.pseudoReverse(s)
+	case ShuffleMethod:
+		return g.shuffle(s)
+	case DeBruijnMethod:
+		return g.deBruijn(s)
+	}
+
+	return reverseSeq(s)
+}
+
+// segments splits a sequence at the enzyme cleavage sites
+func (g *DecoyGenerator) segments(s string) []string {
+
+	if g.enzyme.IsNonSpecific() {
+		return []string{s}
+	}
+
+	var list []string
+
+	sites := g.enzyme.CleavageSites(s)
+	for i := 1; i < len(sites); i++ {
+		list = append(list, s[sites[i-1]:sites[i]])
+	}
+
+	return list
+}
+
+// anchor splits a peptide into its movable residues and the cleavage residues that must stay in place
+func (g *DecoyGenerator) anchor(p string) (string, string, string) {
+
+	if len(p) < 2 {
+		return "", p, ""
+	}
+
+	if g.enzyme.Regex != nil {
+		return "", p[:len(p)-1], p[len(p)-1:]
+	}
+
+	var head, tail int
+	for _, i := range g.enzyme.Rules {
+		if i.Terminus == "N" && strings.IndexByte(i.Sites, p[0]) >= 0 {
+			head = 1
+		} else if i.Terminus != "N" && strings.IndexByte(i.Sites, p[len(p)-1]) >= 0 {
+			tail = 1
+		}
+	}
+
+	if head+tail >= len(p) {
+		return "", p, ""
+	}
+
+	return p[:head], p[head : len(p)-tail], p[len(p)-tail:]
+}
+
+// pseudoReverse reverses each peptide between cleavage sites keeping the cleavage residues in place,
+// decoy peptides keep the precursor masses of their targets
+func (g *DecoyGenerator) pseudoReverse(s string) string {
+
+	var b strings.Builder
+
+	for _, p := range g.segments(s) {
+		head, body, tail := g.anchor(p)
+		b.WriteString(head)
+		b.WriteString(reverseSeq(body))
+		b.WriteString(tail)
+	}
+
+	return b.String()
+}
+
+// shuffle randomizes each peptide between cleavage sites with the seeded generator. Shuffled peptides
+// that are also target peptides are reshuffled, and pseudo-reversed if no unique sequence is found
+func (g *DecoyGenerator) shuffle(s string) string {
+
+	var b strings.Builder
+
+	for _, p := range g.segments(s) {
+
+		head, body, tail := g.anchor(p)
+
+		var decoy string
+		var unique bool
+
+		for i := 0; i < maxShuffleAttempts; i++ {
+
+			r := []byte(body)
+			g.random.Shuffle(len(r), func(i, j int) {
+				r[i], r[j] = r[j], r[i]
+			})
+
+			decoy = head + string(r) + tail
+
+			if _, ok := g.targets[equateIL(decoy)]; !ok {
+				unique = true
+				break
+			}
+		}
+
+		if !unique {
+			decoy = head + reverseSeq(body) + tail
+		}
+
+		b.WriteString(decoy)
+	}
+
+	return b.String()
+}
+
+// deBruijn builds a decoy with the same dipeptide composition as the target by walking a random
+// Eulerian path over the de Bruijn graph of the sequence residues
+func (g *DecoyGenerator) deBruijn(s string) string {
+
+	if len(s) < 3 {
+		return reverseSeq(s)
+	}
+
+	var nodes []byte
+	var graph = make(map[byte][]byte)
+	for i := 0; i < len(s)-1; i++ {
+		if _, ok := graph[s[i]]; !ok {
+			nodes = append(nodes, s[i])
+		}
+		graph[s[i]] = append(graph[s[i]], s[i+1])
+	}
+
+	// shuffle the edges in a fixed node order so the walk depends only on the seed
+	for _, i := range nodes {
+		edges := graph[i]
+		g.random.Shuffle(len(edges), func(i, j int) {
+			edges[i], edges[j] = edges[j], edges[i]
+		})
+	}
+
+	// Hierholzer's algorithm starting from the first residue
+	var path []byte
+	stack := []byte{s[0]}
+
+	for len(stack) > 0 {
+
+		v := stack[len(stack)-1]
+
+		if len(graph[v]) > 0 {
+			next := graph[v][len(graph[v])-1]
+			graph[v] = graph[v][:len(graph[v])-1]
+			stack = append(stack, next)
+		} else {
+			path = append(path, v)
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	decoy := reverseSeq(string(path))
+
+	if decoy == s {
+		return reverseSeq(s)
+	}
+
+	return decoy
+}
+
+// equateIL replaces isoleucine by leucine since both have the same mass
+func equateIL(s string) string {
+	return strings.Replace(s, "I", "L", -1)
+}
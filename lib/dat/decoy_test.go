@@ -0,0 +1,88 @@
+package dat_test
+
+import (
+	. "philosopher/lib/dat"
+	"philosopher/lib/fas"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDecoyGenerator_Generate(t *testing.T) {
+
+	targets := []fas.Entry{{Header: "sp|P00001|TEST", Sequence: "MPEPTIDEKAAGLSRPQWVNK"}}
+
+	tests := []struct {
+		name   string
+		method string
+		enz    string
+		seq    string
+		want   string
+	}{
+		{
+			name:   "Full reversal",
+			method: ReverseMethod,
+			enz:    "trypsin",
+			seq:    "MPEPTIDEKAAGLSR",
+			want:   "RSLGAAKEDITPEPM",
+		},
+		{
+			name:   "Pseudo-reversal keeps tryptic termini",
+			method: PseudoReverseMethod,
+			enz:    "trypsin",
+			seq:    "MPEPTIDEKAAGLSRPQWVNK",
+			want:   "EDITPEPMKNVWQPRSLGAAK",
+		},
+		{
+			name:   "Pseudo-reversal keeps Lys-N termini",
+			method: PseudoReverseMethod,
+			enz:    "lys_n",
+			seq:    "MPEPTIDEKAAGLSR",
+			want:   "EDITPEPMKRSLGAA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewDecoyGenerator(tt.method, tt.enz, 1, targets)
+			if got := g.Generate(tt.seq); got != tt.want {
+				t.Errorf("Generate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoyGenerator_Seeded(t *testing.T) {
+
+	seq := "MPEPTIDEKAAGLSRPQWVNKLLSEDGHTYK"
+	targets := []fas.Entry{{Header: "sp|P00001|TEST", Sequence: seq}}
+
+	for _, method := range []string{ShuffleMethod, DeBruijnMethod} {
+		t.Run(method, func(t *testing.T) {
+
+			a := NewDecoyGenerator(method, "trypsin", 42, targets)
+			b := NewDecoyGenerator(method, "trypsin", 42, targets)
+
+			x := a.Generate(seq)
+			y := b.Generate(seq)
+
+			if x != y {
+				t.Errorf("decoys from the same seed differ, %v and %v", x, y)
+			}
+
+			if x == seq {
+				t.Errorf("decoy is identical to the target")
+			}
+
+			if sorted(x) != sorted(seq) {
+				t.Errorf("decoy composition differs from the target, got %v", x)
+			}
+		})
+	}
+}
+
+func sorted(s string) string {
+	r := strings.Split(s, "")
+	sort.Strings(r)
+	return strings.Join(r, "")
+}
@@ -0,0 +1,364 @@
+package dat
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"philosopher/lib/bio"
+	"philosopher/lib/msg"
+
+	"github.com/sirupsen/logrus"
+)
+
+// massBinWidth is the width in Daltons of the peptide mass distribution bins
+const massBinWidth = 100
+
+// DigestedPeptide is a unique peptide sequence and the proteins that produce it
+type DigestedPeptide struct {
+	Sequence        string
+	Mass            float64
+	MissedCleavages int
+	Proteins        []string
+}
+
+// DigestedProtein holds the theoretical digestion results for a protein
+type DigestedProtein struct {
+	PartHeader      string
+	ID              string
+	GeneNames       string
+	Length          int
+	Peptides        int
+	UniquePeptides  int
+	Coverage        float64
+	UniqueCoverage  float64
+	IsContaminant   bool
+	coveredResidues []bool
+	uniqueResidues  []bool
+}
+
+// Digestion is the in-silico digestion of a database
+type Digestion struct {
+	Enzyme      string
+	Specificity string
+	Missed      int
+	MinLength   int
+	MaxLength   int
+	Total       int
+	Peptides    []DigestedPeptide
+	Proteins    []DigestedProtein
+}
+
+// Digest cleaves all target records in silico, isoleucine and leucine are considered the same
+// residue when deciding if a peptide is shared between proteins
+func (d *Base) Digest(enz, specificity string, missed, minLen, maxLen int) Digestion {
+
+	var dig = Digestion{
+		Enzyme:      enz,
+		Specificity: specificity,
+		Missed:      missed,
+		MinLength:   minLen,
+		MaxLength:   maxLen,
+	}
+
+	var e bio.Enzyme
+	e.Synth(enz)
+
+	var index = make(map[string]int)
+
+	type location struct {
+		protein int
+		start   int
+		end     int
+		peptide int
+	}
+
+	var locations []location
+
+	for _, i := range d.Records {
+
+		if i.IsDecoy {
+			continue
+		}
+
+		pro := DigestedProtein{
+			PartHeader:      i.PartHeader,
+			ID:              i.ID,
+			GeneNames:       i.GeneNames,
+			Length:          len(i.Sequence),
+			IsContaminant:   i.IsContaminant,
+			coveredResidues: make([]bool, len(i.Sequence)),
+			uniqueResidues:  make([]bool, len(i.Sequence)),
+		}
+
+		var seen = make(map[string]uint8)
+
+		for _, j := range e.Digest(i.Sequence, missed, minLen, maxLen, specificity) {
+
+			dig.Total++
+
+			key := strings.Replace(j.Sequence, "I", "L", -1)
+
+			idx, ok := index[key]
+			if !ok {
+				idx = len(dig.Peptides)
+				index[key] = idx
+				dig.Peptides = append(dig.Peptides, DigestedPeptide{
+					Sequence:        j.Sequence,
+					Mass:            bio.PeptideMass(j.Sequence),
+					MissedCleavages: j.MissedCleavages,
+				})
+			}
+
+			if _, ok := seen[key]; !ok {
+				seen[key] = 0
+				dig.Peptides[idx].Proteins = append(dig.Peptides[idx].Proteins, i.PartHeader)
+				pro.Peptides++
+			}
+
+			locations = append(locations, location{protein: len(dig.Proteins), start: j.Start, end: j.End, peptide: idx})
+		}
+
+		dig.Proteins = append(dig.Proteins, pro)
+	}
+
+	for _, i := range locations {
+
+		pro := &dig.Proteins[i.protein]
+		unique := len(dig.Peptides[i.peptide].Proteins) == 1
+
+		for j := i.start - 1; j < i.end; j++ {
+			pro.coveredResidues[j] = true
+			if unique {
+				pro.uniqueResidues[j] = true
+			}
+		}
+	}
+
+	for i := range dig.Proteins {
+		dig.Proteins[i].Coverage = residuePercentage(dig.Proteins[i].coveredResidues)
+		dig.Proteins[i].UniqueCoverage = residuePercentage(dig.Proteins[i].uniqueResidues)
+		dig.Proteins[i].coveredResidues = nil
+		dig.Proteins[i].uniqueResidues = nil
+	}
+
+	var proteinIndex = make(map[string]int)
+	for i := range dig.Proteins {
+		proteinIndex[dig.Proteins[i].PartHeader] = i
+	}
+
+	for _, i := range dig.Peptides {
+		if len(i.Proteins) == 1 {
+			dig.Proteins[proteinIndex[i.Proteins[0]]].UniquePeptides++
+		}
+	}
+
+	sort.Slice(dig.Proteins, func(i, j int) bool {
+		return dig.Proteins[i].PartHeader < dig.Proteins[j].PartHeader
+	})
+
+	sort.Slice(dig.Peptides, func(i, j int) bool {
+		return dig.Peptides[i].Sequence < dig.Peptides[j].Sequence
+	})
+
+	return dig
+}
+
+// residuePercentage returns the percentage of marked residues
+func residuePercentage(residues []bool) float64 {
+
+	if len(residues) == 0 {
+		return 0
+	}
+
+	var count int
+	for _, i := range residues {
+		if i {
+			count++
+		}
+	}
+
+	return float64(count) / float64(len(residues)) * 100
+}
+
+// SharedPeptides counts the unique sequences produced by more than one protein
+func (dig Digestion) SharedPeptides() int {
+
+	var shared int
+	for _, i := range dig.Peptides {
+		if len(i.Proteins) > 1 {
+			shared++
+		}
+	}
+
+	return shared
+}
+
+// Summary prints the digestion statistics
+func (dig Digestion) Summary() {
+
+	var shared = dig.SharedPeptides()
+
+	logrus.WithFields(logrus.Fields{
+		"proteins": len(dig.Proteins),
+		"peptides": dig.Total,
+		"unique":   len(dig.Peptides),
+		"shared":   shared,
+	}).Info("In-silico digestion")
+}
+
+// WriteSummary writes the peptide counts and the length and mass distributions
+func (dig Digestion) WriteSummary(home string) {
+
+	output := fmt.Sprintf("%s%sdigest_summary.tsv", home, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(e, "fatal")
+	}
+	defer file.Close()
+
+	var shared = dig.SharedPeptides()
+	var fraction float64
+	if len(dig.Peptides) > 0 {
+		fraction = float64(shared) / float64(len(dig.Peptides))
+	}
+
+	var lengths = make(map[int]int)
+	var masses = make(map[int]int)
+	for _, i := range dig.Peptides {
+		lengths[len(i.Sequence)]++
+		masses[int(math.Floor(i.Mass/massBinWidth))*massBinWidth]++
+	}
+
+	var lines []string
+	lines = append(lines, "Statistic\tValue\n")
+	lines = append(lines, fmt.Sprintf("Enzyme\t%s\n", dig.Enzyme))
+	lines = append(lines, fmt.Sprintf("Specificity\t%s\n", dig.Specificity))
+	lines = append(lines, fmt.Sprintf("Missed Cleavages\t%d\n", dig.Missed))
+	lines = append(lines, fmt.Sprintf("Minimum Length\t%d\n", dig.MinLength))
+	lines = append(lines, fmt.Sprintf("Maximum Length\t%d\n", dig.MaxLength))
+	lines = append(lines, fmt.Sprintf("Proteins\t%d\n", len(dig.Proteins)))
+	lines = append(lines, fmt.Sprintf("Total Peptides\t%d\n", dig.Total))
+	lines = append(lines, fmt.Sprintf("Unique Sequences\t%d\n", len(dig.Peptides)))
+	lines = append(lines, fmt.Sprintf("Shared Sequences\t%d\n", shared))
+	lines = append(lines, fmt.Sprintf("Shared Fraction\t%.4f\n", fraction))
+
+	for _, k := range sortedKeys(lengths) {
+		lines = append(lines, fmt.Sprintf("Length %d\t%d\n", k, lengths[k]))
+	}
+
+	for _, k := range sortedKeys(masses) {
+		lines = append(lines, fmt.Sprintf("Mass %d-%d\t%d\n", k, k+massBinWidth, masses[k]))
+	}
+
+	for _, i := range lines {
+		_, e = io.WriteString(file, i)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+}
+
+// WriteProteins writes the number of theoretical peptides and the coverage of each protein
+func (dig Digestion) WriteProteins(home string) {
+
+	output := fmt.Sprintf("%s%sdigest_proteins.tsv", home, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(e, "fatal")
+	}
+	defer file.Close()
+
+	_, e = io.WriteString(file, "Protein\tProtein ID\tGene\tLength\tPeptides\tUnique Peptides\tTheoretical Coverage\tUnique Coverage\tContaminant\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for _, i := range dig.Proteins {
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%d\t%d\t%d\t%.2f\t%.2f\t%t\n",
+			i.PartHeader,
+			i.ID,
+			i.GeneNames,
+			i.Length,
+			i.Peptides,
+			i.UniquePeptides,
+			i.Coverage,
+			i.UniqueCoverage,
+			i.IsContaminant,
+		)
+
+		_, e = io.WriteString(file, line)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+}
+
+// WritePeptides writes the deduplicated peptides as a FASTA file and as a table
+func (dig Digestion) WritePeptides(home string) {
+
+	fasta := fmt.Sprintf("%s%speptides.fas", home, string(filepath.Separator))
+	table := fmt.Sprintf("%s%speptides.tsv", home, string(filepath.Separator))
+
+	ff, e := os.Create(fasta)
+	if e != nil {
+		msg.WriteFile(e, "fatal")
+	}
+	defer ff.Close()
+
+	tf, e := os.Create(table)
+	if e != nil {
+		msg.WriteFile(e, "fatal")
+	}
+	defer tf.Close()
+
+	_, e = io.WriteString(tf, "Peptide\tLength\tMass\tMissed Cleavages\tNumber of Proteins\tProteins\tIs Unique\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for n, i := range dig.Peptides {
+
+		header := fmt.Sprintf(">peptide_%d %s\n%s\n", n+1, strings.Join(i.Proteins, ";"), i.Sequence)
+
+		_, e = io.WriteString(ff, header)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+
+		line := fmt.Sprintf("%s\t%d\t%.6f\t%d\t%d\t%s\t%t\n",
+			i.Sequence,
+			len(i.Sequence),
+			i.Mass,
+			i.MissedCleavages,
+			len(i.Proteins),
+			strings.Join(i.Proteins, ", "),
+			len(i.Proteins) == 1,
+		)
+
+		_, e = io.WriteString(tf, line)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+}
+
+// sortedKeys returns the keys of a distribution in ascending order
+func sortedKeys(m map[int]int) []int {
+
+	var keys []int
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Ints(keys)
+
+	return keys
+}
@@ -0,0 +1,39 @@
+package dat_test
+
+import (
+	. "philosopher/lib/dat"
+	"testing"
+)
+
+func TestBase_Digest(t *testing.T) {
+
+	d := &Base{
+		Records: []Record{
+			{PartHeader: "sp|P00001|A", Sequence: "MPEPTIDEKAAGLSRPQWVNK"},
+			{PartHeader: "sp|P00002|B", Sequence: "AAGISRPQWVNKLLSEDGHTYK"},
+			{PartHeader: "rev_sp|P00001|A", Sequence: "KNVWQPRSLGAAKEDITPEPM", IsDecoy: true},
+		},
+	}
+
+	dig := d.Digest("trypsin", "full", 0, 7, 50)
+
+	if len(dig.Proteins) != 2 {
+		t.Errorf("Number of digested proteins is incorrect, got %d, want %d", len(dig.Proteins), 2)
+	}
+
+	if dig.Total != 4 {
+		t.Errorf("Number of peptides is incorrect, got %d, want %d", dig.Total, 4)
+	}
+
+	if len(dig.Peptides) != 3 {
+		t.Errorf("Number of unique sequences is incorrect, got %d, want %d", len(dig.Peptides), 3)
+	}
+
+	if dig.SharedPeptides() != 1 {
+		t.Errorf("Number of shared sequences is incorrect, got %d, want %d", dig.SharedPeptides(), 1)
+	}
+
+	if dig.Proteins[0].UniquePeptides != 1 || dig.Proteins[0].Coverage != 100 {
+		t.Errorf("Protein digestion is incorrect, got %d unique peptides and %.2f coverage", dig.Proteins[0].UniquePeptides, dig.Proteins[0].Coverage)
+	}
+}
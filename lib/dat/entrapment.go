@@ -0,0 +1,45 @@
+package dat
+
+import (
+	"strings"
+
+	"philosopher/lib/cla"
+)
+
+// EntrapmentTag marks the foreign sequences added to validate the FDR estimation
+const EntrapmentTag = "entrapment_"
+
+// IsEntrapment checks if a header or protein name belongs to an entrapment entry
+func IsEntrapment(header, decoyTag string) bool {
+
+	h := strings.TrimPrefix(header, ">")
+	h = cla.TargetName(h, decoyTag)
+
+	return strings.HasPrefix(h, EntrapmentTag)
+}
+
+// EntrapmentRatio returns the size of the entrapment database relative to the target database, in
+// number of target entries. Contaminants are not part of either database
+func (d *Base) EntrapmentRatio() float64 {
+
+	var targets, entrapments float64
+
+	for _, i := range d.Records {
+
+		if i.IsDecoy || i.IsContaminant {
+			continue
+		}
+
+		if i.IsEntrapment {
+			entrapments++
+		} else {
+			targets++
+		}
+	}
+
+	if targets == 0 {
+		return 0
+	}
+
+	return entrapments / targets
+}
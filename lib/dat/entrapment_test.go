@@ -0,0 +1,27 @@
+package dat_test
+
+import (
+	. "philosopher/lib/dat"
+	"testing"
+)
+
+func TestBase_EntrapmentRatio(t *testing.T) {
+
+	d := New()
+	d.Records = []Record{
+		{PartHeader: "sp|P1|A"},
+		{PartHeader: "sp|P2|B"},
+		{PartHeader: "sp|P3|C", IsContaminant: true},
+		{PartHeader: "entrapment_sp|Q1|X", IsEntrapment: true},
+		{PartHeader: "rev_entrapment_sp|Q1|X", IsEntrapment: true, IsDecoy: true},
+		{PartHeader: "rev_sp|P1|A", IsDecoy: true},
+	}
+
+	if got := d.EntrapmentRatio(); got != 0.5 {
+		t.Errorf("EntrapmentRatio() = %v, want %v", got, 0.5)
+	}
+
+	if !IsEntrapment("rev_entrapment_sp|Q1|X", "rev_") || IsEntrapment("sp|P1|A", "rev_") {
+		t.Errorf("IsEntrapment() does not recognize the entrapment headers")
+	}
+}
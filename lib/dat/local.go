@@ -0,0 +1,199 @@
+package dat
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"philosopher/lib/fas"
+	"philosopher/lib/msg"
+)
+
+// fastaExtensions are the file extensions accepted for local proteome files
+var fastaExtensions = []string{".fasta", ".fas", ".fa"}
+
+// FetchLocal builds the database from a local UniProt proteome bundle instead of querying UniProt.
+// The source is either a FASTA file, with the isoforms expected in a sibling <name>_additional file,
+// or a mirror directory searched for the files of the given proteome ID. Files can be gzip-compressed
+func (d *Base) FetchLocal(id, local, temp string, iso, rev bool) {
+
+	canonical, additional := locateProteome(id, local)
+
+	if len(canonical) == 0 {
+		msg.InputNotFound(fmt.Errorf("no local FASTA file found for proteome %s in %s", id, local), "fatal")
+	}
+
+	name := id
+	if len(name) == 0 {
+		name = trimFastaExtension(filepath.Base(canonical))
+	}
+
+	d.UniProtDB = fmt.Sprintf("%s%s%s.fas", temp, string(filepath.Separator), name)
+
+	output, e := os.Create(d.UniProtDB)
+	if e != nil {
+		msg.WriteFile(errors.New("cannot create a local database file"), "fatal")
+	}
+	defer output.Close()
+
+	files := []string{canonical}
+	if iso {
+		if len(additional) > 0 {
+			files = append(files, additional)
+		} else {
+			msg.Custom(errors.New("no additional isoform file found for the local proteome"), "warning")
+		}
+	}
+
+	var entries int
+	for _, i := range files {
+		entries += copyLocalFasta(i, output, rev)
+	}
+
+	if entries == 0 {
+		msg.Custom(errors.New("no sequences found, check your local proteome files and parameters"), "fatal")
+	}
+
+	d.DownloadedFiles = append(d.DownloadedFiles, d.UniProtDB)
+}
+
+// locateProteome finds the canonical and the additional isoform FASTA files of a proteome
+func locateProteome(id, local string) (string, string) {
+
+	info, e := os.Stat(local)
+	if e != nil {
+		msg.InputNotFound(e, "fatal")
+	}
+
+	if !info.IsDir() {
+
+		base := trimFastaExtension(local)
+
+		var additional string
+		for _, ext := range fastaExtensions {
+			for _, gz := range []string{"", ".gz"} {
+				candidate := base + "_additional" + ext + gz
+				if _, e := os.Stat(candidate); e == nil {
+					additional = candidate
+				}
+			}
+		}
+
+		return local, additional
+	}
+
+	if len(id) == 0 {
+		msg.InputNotFound(errors.New("a proteome ID is required to search a local mirror directory"), "fatal")
+	}
+
+	var candidates []string
+
+	e = filepath.Walk(local, func(path string, f os.FileInfo, err error) error {
+
+		if err != nil || f.IsDir() {
+			return nil
+		}
+
+		name := f.Name()
+		if isFastaFile(name) && (strings.HasPrefix(name, id+"_") || strings.HasPrefix(name, id+".")) {
+			candidates = append(candidates, path)
+		}
+
+		return nil
+	})
+
+	if e != nil {
+		msg.ReadFile(e, "fatal")
+	}
+
+	sort.Strings(candidates)
+
+	var canonical, additional string
+	for _, i := range candidates {
+		if strings.Contains(filepath.Base(i), "_additional") {
+			if len(additional) == 0 {
+				additional = i
+			}
+		} else if len(canonical) == 0 {
+			canonical = i
+		}
+	}
+
+	return canonical, additional
+}
+
+// copyLocalFasta copies the FASTA entries to the output, only Swiss-Prot entries are kept when reviewed
+// is set. It returns the number of entries written
+func copyLocalFasta(file string, output io.Writer, reviewed bool) int {
+
+	reader, e := fas.Open(file)
+	if e != nil {
+		msg.ReadFile(e, "fatal")
+	}
+	defer reader.Close()
+
+	w := fas.NewWriter(output)
+
+	var entries int
+	for {
+
+		entry, ok := reader.Next()
+		if !ok {
+			break
+		}
+
+		if reviewed && !strings.HasPrefix(entry.Header, "sp|") {
+			continue
+		}
+
+		e = w.Write(entry)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+
+		entries++
+	}
+
+	if e := reader.Err(); e != nil {
+		msg.ReadFile(e, "fatal")
+	}
+
+	e = w.Flush()
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	return entries
+}
+
+// isFastaFile checks the file extension, ignoring the gzip suffix
+func isFastaFile(name string) bool {
+
+	name = strings.TrimSuffix(strings.ToLower(name), ".gz")
+
+	for _, i := range fastaExtensions {
+		if strings.HasSuffix(name, i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trimFastaExtension removes the FASTA and gzip extensions from a file name
+func trimFastaExtension(name string) string {
+
+	name = strings.TrimSuffix(name, ".gz")
+
+	for _, i := range fastaExtensions {
+		if strings.HasSuffix(strings.ToLower(name), i) {
+			return name[:len(name)-len(i)]
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,65 @@
+package dat_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	. "philosopher/lib/dat"
+	"philosopher/lib/fas"
+	"testing"
+)
+
+func writeGzip(t *testing.T, file, content string) {
+
+	f, e := os.Create(file)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer f.Close()
+
+	w := gzip.NewWriter(f)
+	w.Write([]byte(content))
+	w.Close()
+}
+
+func TestBase_FetchLocal(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "local")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	mirror := filepath.Join(dir, "Eukaryota", "UP000000001")
+	os.MkdirAll(mirror, 0755)
+
+	writeGzip(t, filepath.Join(mirror, "UP000000001_9999.fasta.gz"), ">sp|P00001|A_TEST Protein A OS=Test OX=9999 GN=A PE=1 SV=1\nMPEPTIDEK\n>tr|Q00001|B_TEST Protein B OS=Test OX=9999 GN=B PE=4 SV=1\nAAGLSR\n")
+	writeGzip(t, filepath.Join(mirror, "UP000000001_9999_additional.fasta.gz"), ">sp|P00001-2|A_TEST Isoform 2 of Protein A OS=Test OX=9999 GN=A\nMPEPTLDEK\n")
+
+	tests := []struct {
+		name  string
+		local string
+		iso   bool
+		rev   bool
+		want  int
+	}{
+		{"Mirror directory", dir, false, false, 2},
+		{"Mirror directory with isoforms", dir, true, false, 3},
+		{"Reviewed entries with isoforms", dir, true, true, 2},
+		{"Proteome file", filepath.Join(mirror, "UP000000001_9999.fasta.gz"), true, true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			d := New()
+			d.FetchLocal("UP000000001", tt.local, dir, tt.iso, tt.rev)
+
+			got := fas.ParseFile(d.UniProtDB)
+			if len(got) != tt.want {
+				t.Errorf("Number of FASTA entries is incorrect, got %d, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
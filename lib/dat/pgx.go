@@ -0,0 +1,80 @@
+package dat
+
+import (
+	"strings"
+
+	"philosopher/lib/cla"
+	"philosopher/lib/fas"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sequence classes for proteogenomic databases
+const (
+	CanonicalClass = "canonical"
+	VariantClass   = "variant"
+	NovelClass     = "novel"
+)
+
+// header prefixes that keep the provenance of the proteogenomic sequences
+const (
+	VariantTag = "variant_"
+	NovelTag   = "novel_"
+)
+
+// SequenceClass returns the class of a database entry from its header prefix
+func SequenceClass(header, decoyTag string) string {
+
+	h := strings.TrimPrefix(header, ">")
+	h = cla.TargetName(h, decoyTag)
+	h = strings.TrimPrefix(h, ContaminantTag)
+
+	if strings.HasPrefix(h, VariantTag) {
+		return VariantClass
+	} else if strings.HasPrefix(h, NovelTag) {
+		return NovelClass
+	}
+
+	return CanonicalClass
+}
+
+// ClassRank orders the sequence classes, a peptide explained by a canonical sequence is canonical
+// even if it also maps to variant or novel entries
+func ClassRank(class string) int {
+
+	switch class {
+	case VariantClass:
+		return 1
+	case NovelClass:
+		return 2
+	}
+
+	return 0
+}
+
+// addClassified merges variant, novel or entrapment sequences into the database, marking their headers with the class
+// tag. Sequences identical to a database entry do not add new evidence and are skipped
+func addClassified(db []fas.Entry, file, tag string) []fas.Entry {
+
+	var sequences = make(map[string]uint8)
+	for _, i := range db {
+		sequences[i.Sequence] = 0
+	}
+
+	var added, skipped int
+	for _, i := range readFasta(file) {
+
+		if _, ok := sequences[i.Sequence]; ok {
+			skipped++
+			continue
+		}
+
+		db = append(db, fas.Entry{Header: tag + i.Header, Sequence: i.Sequence})
+		sequences[i.Sequence] = 0
+		added++
+	}
+
+	logrus.Info("Added ", added, " ", strings.TrimSuffix(tag, "_"), " sequences, ", skipped, " identical to existing entries")
+
+	return db
+}
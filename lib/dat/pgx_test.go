@@ -0,0 +1,87 @@
+package dat_test
+
+import (
+	. "philosopher/lib/dat"
+	"testing"
+)
+
+func TestSequenceClass(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		header string
+		class  string
+		source string
+	}{
+		{"Canonical UniProt entry", "sp|P00001|A_HUMAN Protein A", CanonicalClass, "uniprot"},
+		{"Variant UniProt entry", "variant_sp|P00001|A_HUMAN Protein A p.G12V", VariantClass, "uniprot"},
+		{"Decoy of a variant entry", "rev_variant_sp|P00001|A_HUMAN Protein A p.G12V", VariantClass, "uniprot"},
+		{"Novel translation", "novel_chr1_12345_frame2", NovelClass, "generic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := SequenceClass(tt.header, "rev_"); got != tt.class {
+				t.Errorf("SequenceClass() = %v, want %v", got, tt.class)
+			}
+
+			if got := Classify(tt.header, "rev_"); got != tt.source {
+				t.Errorf("Classify() = %v, want %v", got, tt.source)
+			}
+		})
+	}
+}
+
+func TestSequenceClass_DecoyRules(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		header string
+		tag    string
+		class  string
+		source string
+	}{
+		{"Regular expression", "DECOY_variant_sp|P00001|A_HUMAN Protein A p.G12V", `regex:^(rev|DECOY)_`, VariantClass, "uniprot"},
+		{"Second prefix", "decoy_novel_chr1_12345_frame2", "rev_,decoy_", NovelClass, "generic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := SequenceClass(tt.header, tt.tag); got != tt.class {
+				t.Errorf("SequenceClass() = %v, want %v", got, tt.class)
+			}
+
+			if got := Classify(tt.header, tt.tag); got != tt.source {
+				t.Errorf("Classify() = %v, want %v", got, tt.source)
+			}
+
+			if got := ProcessGeneric(tt.header, "PEPTIDE", tt.tag); !got.IsDecoy {
+				t.Errorf("ProcessGeneric() IsDecoy = %v, want true", got.IsDecoy)
+			}
+		})
+	}
+}
+
+func TestProcess_DecoySuffixWithDescription(t *testing.T) {
+
+	tag := "rev_,suffix:_REVERSED"
+
+	tests := []struct {
+		name   string
+		record Record
+	}{
+		{"UniProt", ProcessUniProtKB("sp|P00001|A_HUMAN_REVERSED Protein A OS=Homo sapiens OX=9606 GN=A PE=1 SV=1", "PEPTIDE", tag)},
+		{"Generic", ProcessGeneric("novel_chr1_12345_REVERSED translated frame 2", "PEPTIDE", tag)},
+		{"Prefix", ProcessUniProtKB("rev_sp|P00001|A_HUMAN Protein A OS=Homo sapiens OX=9606 GN=A PE=1 SV=1", "PEPTIDE", tag)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.record.IsDecoy {
+				t.Errorf("%s IsDecoy = false, want true", tt.record.PartHeader)
+			}
+		})
+	}
+}
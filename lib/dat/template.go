@@ -0,0 +1,194 @@
+package dat
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"philosopher/lib/cla"
+	"philosopher/lib/msg"
+)
+
+// templateGroups are the named capture groups recognized in a header template
+var templateGroups = []string{"id", "entry", "gene", "description", "organism"}
+
+// HeaderTemplate is a user-defined regular expression that parses FASTA headers
+type HeaderTemplate struct {
+	Pattern string
+	Regex   *regexp.Regexp
+}
+
+// LoadTemplates reads the header templates from a file, one regular expression per line, or takes
+// the argument as a single template when it is not a file. Lines starting with # are ignored
+func LoadTemplates(source string) []string {
+
+	var templates []string
+
+	f, e := os.Open(source)
+	if e != nil {
+		templates = append(templates, source)
+	} else {
+
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if len(line) > 0 && !strings.HasPrefix(line, "#") {
+				templates = append(templates, line)
+			}
+		}
+
+		if e := scanner.Err(); e != nil {
+			msg.ReadFile(e, "fatal")
+		}
+	}
+
+	// validates the templates before they are stored on the workspace
+	CompileTemplates(templates)
+
+	return templates
+}
+
+// CompileTemplates builds the header templates, each one needs at least the id capture group
+func CompileTemplates(patterns []string) []HeaderTemplate {
+
+	var list []HeaderTemplate
+
+	for _, i := range patterns {
+
+		re, e := regexp.Compile(i)
+		if e != nil {
+			msg.Custom(fmt.Errorf("the header template %s is not a valid regular expression", i), "fatal")
+		}
+
+		if subexpIndex(re, "id") < 0 {
+			msg.Custom(fmt.Errorf("the header template %s has no (?P<id>...) group", i), "fatal")
+		}
+
+		list = append(list, HeaderTemplate{Pattern: i, Regex: re})
+	}
+
+	return list
+}
+
+// Parse builds a database record from a header, the second value is false when the template does not match
+func (t HeaderTemplate) Parse(k, v, decoyTag string) (Record, bool) {
+
+	var e Record
+
+	// the templates describe the original headers, without decoy or provenance prefixes
+	header := cla.TargetName(k, decoyTag)
+	header = strings.TrimPrefix(header, ContaminantTag)
+	header = strings.TrimPrefix(header, MaxQuantTag)
+	header = strings.TrimPrefix(header, EntrapmentTag)
+	header = strings.TrimPrefix(header, VariantTag)
+	header = strings.TrimPrefix(header, NovelTag)
+
+	match := t.Regex.FindStringSubmatch(header)
+	if match == nil {
+		return e, false
+	}
+
+	var fields = make(map[string]string)
+	for _, i := range templateGroups {
+		if idx := subexpIndex(t.Regex, i); idx > 0 {
+			fields[i] = strings.TrimSpace(match[idx])
+		}
+	}
+
+	if len(fields["id"]) == 0 {
+		return e, false
+	}
+
+	part := strings.Split(k, " ")
+	e.PartHeader = part[0]
+
+	e.ID = fields["id"]
+	e.EntryName = fields["entry"]
+	e.GeneNames = fields["gene"]
+	e.Description = fields["description"]
+	e.ProteinName = fields["description"]
+	e.Organism = fields["organism"]
+
+	if len(e.EntryName) == 0 {
+		e.EntryName = e.ID
+	}
+
+	e.Sequence = v
+	e.Length = len(v)
+	e.OriginalHeader = k
+	e.IsDecoy = cla.Matcher(decoyTag).IsDecoy(e.PartHeader)
+
+	return e, true
+}
+
+// subexpIndex returns the index of a named capture group, or -1 when the group is missing
+func subexpIndex(re *regexp.Regexp, name string) int {
+
+	for n, i := range re.SubexpNames() {
+		if i == name {
+			return n
+		}
+	}
+
+	return -1
+}
+
+// parseWithTemplates tries the templates in order and returns the first match
+func parseWithTemplates(templates []HeaderTemplate, k, v, decoyTag string) (Record, int, bool) {
+
+	for n, i := range templates {
+		if r, ok := i.Parse(k, v, decoyTag); ok {
+			return r, n, true
+		}
+	}
+
+	return Record{}, -1, false
+}
+
+// DryRun prints how each header of a FASTA file would be parsed, without changing the workspace
+func (d *Base) DryRun(file, decoyTag string, output io.Writer) {
+
+	templates := CompileTemplates(d.Templates)
+
+	entries := readFasta(file)
+
+	_, e := io.WriteString(output, "Header\tParser\tID\tEntry Name\tGene\tDescription\tOrganism\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	var unmatched int
+	for _, i := range entries {
+
+		k := i.Header
+
+		r, n, ok := parseWithTemplates(templates, k, i.Sequence, decoyTag)
+
+		parser := fmt.Sprintf("template %d", n+1)
+		if !ok {
+			parser = Classify(k, decoyTag)
+			r = processHeader(parser, k, i.Sequence, decoyTag)
+
+			if len(templates) > 0 {
+				unmatched++
+			}
+		}
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n", k, parser, r.ID, r.EntryName, r.GeneNames, r.Description, r.Organism)
+
+		_, e = io.WriteString(output, line)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+
+	if unmatched > 0 {
+		msg.Custom(errors.New(fmt.Sprint(unmatched, " headers did not match any template")), "warning")
+	}
+}
@@ -0,0 +1,48 @@
+package dat_test
+
+import (
+	. "philosopher/lib/dat"
+	"testing"
+)
+
+func TestHeaderTemplate_Parse(t *testing.T) {
+
+	templates := CompileTemplates([]string{
+		`^(?P<id>FBpp\d+)\s+type=\S+;.*?name=(?P<entry>[^;]+);.*?parent=(?P<gene>FBgn\d+)`,
+		`^(?P<id>AT\dG\d+\.\d+)\s*\|\s*Symbols:\s*(?P<gene>[^|]*)\|\s*(?P<description>[^|]+)\|`,
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		tmpl   int
+		id     string
+		entry  string
+		gene   string
+		decoy  bool
+	}{
+		{"FlyBase", "FBpp0070000 type=protein; loc=X:19961297..19969323; name=CG11023-PA; parent=FBgn0031081,FBtr0070000", 0, "FBpp0070000", "CG11023-PA", "FBgn0031081", false},
+		{"Araport", "AT1G01010.1 | Symbols: NAC001 | NAC domain containing protein 1 | chr1:3631-5899 FORWARD", 1, "AT1G01010.1", "AT1G01010.1", "NAC001", false},
+		{"Araport decoy", "rev_AT1G01010.1 | Symbols: NAC001 | NAC domain containing protein 1 | chr1:3631-5899 FORWARD", 1, "AT1G01010.1", "AT1G01010.1", "NAC001", true},
+		{"Tagged contaminant", "contam_AT1G01010.1 | Symbols: NAC001 | NAC domain containing protein 1 | chr1:3631-5899 FORWARD", 1, "AT1G01010.1", "AT1G01010.1", "NAC001", false},
+		{"MaxQuant contaminant", "contam_CON__AT1G01010.1 | Symbols: NAC001 | NAC domain containing protein 1 | chr1:3631-5899 FORWARD", 1, "AT1G01010.1", "AT1G01010.1", "NAC001", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			r, ok := templates[tt.tmpl].Parse(tt.header, "PEPTIDE", "rev_")
+			if !ok {
+				t.Fatalf("template did not match %s", tt.header)
+			}
+
+			if r.ID != tt.id || r.EntryName != tt.entry || r.GeneNames != tt.gene || r.IsDecoy != tt.decoy {
+				t.Errorf("Parse() = %v, %v, %v, %v", r.ID, r.EntryName, r.GeneNames, r.IsDecoy)
+			}
+		})
+	}
+
+	if _, ok := templates[0].Parse("sp|P00001|A_HUMAN Protein A", "PEPTIDE", "rev_"); ok {
+		t.Errorf("template should not match a UniProt header")
+	}
+}
@@ -0,0 +1,231 @@
+package dat
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultUniProtURL is the UniProt REST API
+const DefaultUniProtURL = "https://rest.uniprot.org"
+
+// uniProtPageSize is the number of entries requested per page, the maximum accepted by UniProt
+const uniProtPageSize = 500
+
+// linkNextRegex captures the next page address from a Link header
+var linkNextRegex = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// UniProtClient downloads FASTA sequences from the UniProt REST API
+type UniProtClient struct {
+	BaseURL  string
+	PageSize int
+	Retries  int
+	Backoff  time.Duration
+	Release  string
+	Client   *http.Client
+}
+
+// NewUniProtClient constructor, an empty base address points the client to UniProt
+func NewUniProtClient(base string) UniProtClient {
+
+	var self UniProtClient
+
+	if len(base) == 0 {
+		base = DefaultUniProtURL
+	}
+
+	self.BaseURL = strings.TrimRight(base, "/")
+	self.PageSize = uniProtPageSize
+	self.Retries = 3
+	self.Backoff = 2 * time.Second
+	self.Client = &http.Client{Timeout: 10 * time.Minute}
+
+	return self
+}
+
+// ProteomeQuery returns the address of the first page of a proteome in FASTA format
+func (c *UniProtClient) ProteomeQuery(id string, iso, rev bool) string {
+
+	query := "proteome:" + id
+	if rev {
+		query += " AND reviewed:true"
+	}
+
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("format", "fasta")
+	params.Set("size", strconv.Itoa(c.PageSize))
+
+	if iso {
+		params.Set("includeIsoform", "true")
+	}
+
+	return fmt.Sprintf("%s/uniprotkb/search?%s", c.BaseURL, params.Encode())
+}
+
+// Download follows the pagination links from the given address and streams every page to the output.
+// It returns the number of FASTA entries written
+func (c *UniProtClient) Download(address string, output io.Writer) (int, error) {
+
+	var entries int
+	var page int
+
+	for len(address) > 0 {
+
+		page++
+
+		next, n, e := c.downloadPage(address, output)
+		if e != nil {
+			return entries, e
+		}
+
+		entries += n
+		address = next
+
+		logrus.Debug("Downloaded page ", page, " with ", n, " entries")
+	}
+
+	return entries, nil
+}
+
+// downloadPage requests a single page, retrying with an exponential backoff on connection errors,
+// interrupted transfers, rate limits and server errors. The page is buffered so a failed attempt
+// never leaves a partial page on the output
+func (c *UniProtClient) downloadPage(address string, output io.Writer) (string, int, error) {
+
+	wait := c.Backoff
+
+	for attempt := 0; ; attempt++ {
+
+		response, e := c.get(address)
+
+		retry := e != nil
+		if e == nil {
+
+			if response.StatusCode == http.StatusOK {
+
+				var page bytes.Buffer
+
+				n, err := copyEntries(response, &page)
+				response.Body.Close()
+
+				if err == nil {
+
+					if release := response.Header.Get("X-UniProt-Release"); len(release) > 0 && len(c.Release) == 0 {
+						c.Release = release
+					}
+
+					_, err = output.Write(page.Bytes())
+					if err != nil {
+						return "", 0, err
+					}
+
+					return nextLink(response.Header.Get("Link")), n, nil
+				}
+
+				retry = true
+				e = err
+
+			} else {
+
+				response.Body.Close()
+
+				retry = response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+				e = fmt.Errorf("UniProt returned %s", response.Status)
+
+				if s, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil && s > 0 {
+					wait = time.Duration(s) * time.Second
+				}
+			}
+		}
+
+		if !retry || attempt >= c.Retries {
+			return "", 0, e
+		}
+
+		logrus.Warn("UniProt request failed, retrying in ", wait)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// get sends a request asking for a compressed response
+func (c *UniProtClient) get(address string) (*http.Response, error) {
+
+	request, e := http.NewRequest("GET", address, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	return c.Client.Do(request)
+}
+
+// copyEntries streams the response body to the output, decompressing it when needed
+func copyEntries(response *http.Response, output io.Writer) (int, error) {
+
+	buffered := bufio.NewReader(response.Body)
+
+	var reader io.Reader = buffered
+
+	magic, _ := buffered.Peek(2)
+	if response.Header.Get("Content-Encoding") == "gzip" || (len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b) {
+
+		gz, e := gzip.NewReader(buffered)
+		if e != nil {
+			return 0, e
+		}
+		defer gz.Close()
+
+		reader = gz
+	}
+
+	var entries int
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+
+	w := bufio.NewWriter(output)
+
+	for scanner.Scan() {
+
+		line := scanner.Text()
+		if strings.HasPrefix(line, ">") {
+			entries++
+		}
+
+		_, e := w.WriteString(line + "\n")
+		if e != nil {
+			return entries, e
+		}
+	}
+
+	if e := scanner.Err(); e != nil {
+		return entries, errors.New("UniProt download was interrupted")
+	}
+
+	return entries, w.Flush()
+}
+
+// nextLink extracts the next page address from a Link header
+func nextLink(header string) string {
+
+	match := linkNextRegex.FindStringSubmatch(header)
+	if len(match) < 2 {
+		return ""
+	}
+
+	return match[1]
+}
@@ -0,0 +1,109 @@
+package dat_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	. "philosopher/lib/dat"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUniProtClient_Download(t *testing.T) {
+
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		requests++
+
+		// the first request fails to exercise the retry
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("X-UniProt-Release", "2022_05")
+
+		var body string
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", fmt.Sprintf("<http://%s/uniprotkb/search?cursor=abc>; rel=\"next\"", r.Host))
+			body = ">sp|P00001|A_TEST Protein A\nMPEPTIDEK\n>sp|P00002|B_TEST Protein B\nAAGLSR\n"
+		} else {
+			body = ">tr|Q00001|C_TEST Protein C\nLLSEDGHTYK\n"
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(body))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	c := NewUniProtClient(server.URL)
+	c.Backoff = time.Millisecond
+
+	query := c.ProteomeQuery("UP000000001", false, false)
+	if !strings.HasPrefix(query, server.URL+"/uniprotkb/search?") {
+		t.Errorf("ProteomeQuery() = %v", query)
+	}
+
+	var buf bytes.Buffer
+	entries, e := c.Download(query, &buf)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if entries != 3 {
+		t.Errorf("Number of entries is incorrect, got %d, want %d", entries, 3)
+	}
+
+	if c.Release != "2022_05" {
+		t.Errorf("Release is incorrect, got %s, want %s", c.Release, "2022_05")
+	}
+
+	if !strings.Contains(buf.String(), "LLSEDGHTYK") {
+		t.Errorf("Second page was not downloaded")
+	}
+}
+
+func TestUniProtClient_DownloadInterrupted(t *testing.T) {
+
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		requests++
+
+		// the first transfer is cut short, the body is smaller than the announced length
+		if requests == 1 {
+			w.Header().Set("Content-Length", "1000")
+			w.Write([]byte(">sp|P00001|A_TEST Protein A\nMPEP"))
+			return
+		}
+
+		w.Write([]byte(">sp|P00001|A_TEST Protein A\nMPEPTIDEK\n"))
+	}))
+	defer server.Close()
+
+	c := NewUniProtClient(server.URL)
+	c.Backoff = time.Millisecond
+
+	var buf bytes.Buffer
+	entries, e := c.Download(c.ProteomeQuery("UP000000001", false, false), &buf)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if requests != 2 {
+		t.Errorf("Number of requests is incorrect, got %d, want %d", requests, 2)
+	}
+
+	want := ">sp|P00001|A_TEST Protein A\nMPEPTIDEK\n"
+	if entries != 1 || buf.String() != want {
+		t.Errorf("Download() = %d entries %q, want 1 entry %q", entries, buf.String(), want)
+	}
+}
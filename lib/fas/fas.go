@@ -3,40 +3,37 @@ package fas
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
+	"philosopher/lib/cla"
 	"philosopher/lib/msg"
 )
 
-// ParseFile a fasta file and returns a map with the header as key and sequence as value
+// ParseFile a fasta file and returns a map with the header as key and sequence as value.
+// Use ReadFile when the entry order or repeated headers matter
 func ParseFile(filename string) map[string]string {
 
-	var fastaHeader string
-	var fastaSeq string
 	var fastaMap = make(map[string]string)
 
-	f, e := os.Open(filename)
+	entries, e := ReadFile(filename)
 	if filename == "" || e != nil {
 		msg.ReadFile(errors.New("cannot open the database file"), "error")
 	}
-	defer f.Close()
 
-	reHeader, _ := regexp.Compile("^>(.*)")
-	scanner := bufio.NewScanner(f)
-
-	for scanner.Scan() {
-		if strings.HasPrefix(scanner.Text(), ">") {
-			header := reHeader.FindStringSubmatch(scanner.Text())
-			fastaHeader = header[1]
-			fastaHeader = strings.Replace(fastaHeader, "\t", " ", -1)
-			fastaMap[fastaHeader] = ""
-		} else {
-			fastaSeq = fastaMap[fastaHeader]
-			fastaSeq += scanner.Text()
-			fastaMap[fastaHeader] = fastaSeq
+	var repeated int
+	for _, i := range entries {
+		if _, ok := fastaMap[i.Header]; ok {
+			repeated++
 		}
+		fastaMap[i.Header] = i.Sequence
+	}
+
+	if repeated > 0 {
+		msg.Custom(fmt.Errorf("%d repeated headers found in %s, only the last entry of each one is kept", repeated, filepath.Base(filename)), "warning")
 	}
 
 	return fastaMap
@@ -112,7 +109,7 @@ func CleanDatabase(db map[string]string, decoytag, contag string) (cleanMap map[
 	cleanMap = make(map[string]string)
 
 	for k, v := range db {
-		if !strings.Contains(k, decoytag) && !strings.Contains(k, contag) {
+		if !cla.Matcher(decoytag).IsDecoy(strings.Split(k, " ")[0]) && !strings.Contains(k, contag) {
 			cleanMap[k] = v
 		}
 	}
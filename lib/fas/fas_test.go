@@ -51,3 +51,23 @@ func TestParseFile(t *testing.T) {
 
 	//tes.ShutDowTestEnv()
 }
+
+func TestCleanDatabase(t *testing.T) {
+
+	db := map[string]string{
+		"sp|P00001|A_HUMAN Protein A":               "MPEPTIDEK",
+		"rev_sp|P00001|A_HUMAN Protein A":           "KEDITPEPM",
+		"sp|P00002|B_HUMAN_REVERSED Protein B":      "KEDITPEPM",
+		"contam_sp|P00761|TRYP_PIG Trypsin":         "FPTDDDDK",
+		"sp|P00003|C_HUMAN Protein rev_ecomplement": "AAGLSR",
+	}
+
+	want := map[string]string{
+		"sp|P00001|A_HUMAN Protein A":               "MPEPTIDEK",
+		"sp|P00003|C_HUMAN Protein rev_ecomplement": "AAGLSR",
+	}
+
+	if got := CleanDatabase(db, "rev_,suffix:_REVERSED", "contam_"); !reflect.DeepEqual(got, want) {
+		t.Errorf("CleanDatabase() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,224 @@
+package fas
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Entry is a FASTA record
+type Entry struct {
+	Header   string
+	Sequence string
+}
+
+// Accession returns the first word of the header
+func (e Entry) Accession() string {
+	return strings.SplitN(e.Header, " ", 2)[0]
+}
+
+// Reader streams FASTA entries in the order they appear in the input
+type Reader struct {
+	scanner *bufio.Scanner
+	closers []io.Closer
+	header  string
+	started bool
+	err     error
+}
+
+// NewReader creates a streaming reader, gzip-compressed input is detected by its magic number
+func NewReader(r io.Reader) (*Reader, error) {
+
+	var self Reader
+
+	buffered := bufio.NewReader(r)
+
+	var input io.Reader = buffered
+
+	magic, _ := buffered.Peek(2)
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+
+		gz, e := gzip.NewReader(buffered)
+		if e != nil {
+			return nil, e
+		}
+
+		self.closers = append(self.closers, gz)
+		input = gz
+	}
+
+	self.scanner = bufio.NewScanner(input)
+	self.scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+
+	return &self, nil
+}
+
+// Open creates a streaming reader for a plain or gzip-compressed file
+func Open(filename string) (*Reader, error) {
+
+	f, e := os.Open(filename)
+	if e != nil {
+		return nil, e
+	}
+
+	r, e := NewReader(f)
+	if e != nil {
+		f.Close()
+		return nil, e
+	}
+
+	r.closers = append(r.closers, f)
+
+	return r, nil
+}
+
+// Next returns the next entry, the second value is false when the input is exhausted
+func (r *Reader) Next() (Entry, bool) {
+
+	var seq strings.Builder
+
+	for r.scanner.Scan() {
+
+		line := strings.TrimRight(r.scanner.Text(), "\r")
+
+		if strings.HasPrefix(line, ">") {
+
+			header := strings.Replace(line[1:], "\t", " ", -1)
+
+			if r.started {
+				entry := Entry{Header: r.header, Sequence: seq.String()}
+				r.header = header
+				return entry, true
+			}
+
+			r.header = header
+			r.started = true
+
+			continue
+		}
+
+		if r.started {
+			seq.WriteString(strings.TrimSpace(line))
+		}
+	}
+
+	r.err = r.scanner.Err()
+
+	if r.started {
+		r.started = false
+		return Entry{Header: r.header, Sequence: seq.String()}, true
+	}
+
+	return Entry{}, false
+}
+
+// Err returns the first read error
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Close releases the underlying files
+func (r *Reader) Close() {
+	for _, i := range r.closers {
+		i.Close()
+	}
+}
+
+// ReadFile reads all entries of a FASTA file keeping the input order
+func ReadFile(filename string) ([]Entry, error) {
+
+	r, e := Open(filename)
+	if e != nil {
+		return nil, e
+	}
+	defer r.Close()
+
+	var entries []Entry
+	for {
+		entry, ok := r.Next()
+		if !ok {
+			break
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, r.Err()
+}
+
+// Writer writes FASTA entries, sequences are wrapped when a line width is given
+type Writer struct {
+	w     *bufio.Writer
+	Width int
+}
+
+// NewWriter creates a FASTA writer that keeps each sequence on a single line
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write adds an entry to the output
+func (w *Writer) Write(e Entry) error {
+
+	if _, err := w.w.WriteString(">" + e.Header + "\n"); err != nil {
+		return err
+	}
+
+	seq := e.Sequence
+	for w.Width > 0 && len(seq) > w.Width {
+		if _, err := w.w.WriteString(seq[:w.Width] + "\n"); err != nil {
+			return err
+		}
+		seq = seq[w.Width:]
+	}
+
+	_, err := w.w.WriteString(seq + "\n")
+
+	return err
+}
+
+// Flush writes any buffered data to the output
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Duplicates lists repeated accessions and groups of entries sharing the same sequence
+type Duplicates struct {
+	Accessions []string
+	Sequences  [][]string
+}
+
+// FindDuplicates reports the accessions used by more than one entry and the entries with identical sequences
+func FindDuplicates(entries []Entry) Duplicates {
+
+	var d Duplicates
+
+	var accessions = make(map[string]int)
+	var sequences = make(map[string][]string)
+	var order []string
+
+	for _, i := range entries {
+
+		accessions[i.Accession()]++
+		if accessions[i.Accession()] == 2 {
+			d.Accessions = append(d.Accessions, i.Accession())
+		}
+
+		if _, ok := sequences[i.Sequence]; !ok {
+			order = append(order, i.Sequence)
+		}
+		sequences[i.Sequence] = append(sequences[i.Sequence], i.Accession())
+	}
+
+	for _, i := range order {
+		if len(sequences[i]) > 1 {
+			d.Sequences = append(d.Sequences, sequences[i])
+		}
+	}
+
+	sort.Strings(d.Accessions)
+
+	return d
+}
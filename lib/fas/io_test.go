@@ -0,0 +1,133 @@
+package fas_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	. "philosopher/lib/fas"
+	"reflect"
+	"testing"
+)
+
+const orderedFasta = ">sp|P00003|C third\nMKV\nLLA\n>sp|P00001|A first\nMPEPTIDE\n>sp|P00002|B second\tdesc\nMKVLLA\n>sp|P00001|A repeated\nGGG\n"
+
+func TestReadFile(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "fas")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	plain := filepath.Join(dir, "db.fas")
+	if e := ioutil.WriteFile(plain, []byte(orderedFasta), 0644); e != nil {
+		t.Fatal(e)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(orderedFasta))
+	gz.Close()
+
+	compressed := filepath.Join(dir, "db.fas.gz")
+	if e := ioutil.WriteFile(compressed, buf.Bytes(), 0644); e != nil {
+		t.Fatal(e)
+	}
+
+	want := []Entry{
+		{Header: "sp|P00003|C third", Sequence: "MKVLLA"},
+		{Header: "sp|P00001|A first", Sequence: "MPEPTIDE"},
+		{Header: "sp|P00002|B second desc", Sequence: "MKVLLA"},
+		{Header: "sp|P00001|A repeated", Sequence: "GGG"},
+	}
+
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"Plain FASTA file", plain},
+		{"Compressed FASTA file", compressed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, e := ReadFile(tt.file)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ReadFile() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+
+	entries := []Entry{
+		{Header: "sp|P00003|C third", Sequence: "MKVLLA"},
+		{Header: "sp|P00001|A first", Sequence: "MPEPTIDE"},
+		{Header: "sp|P00002|B second", Sequence: "MKVLLA"},
+		{Header: "sp|P00001|A repeated", Sequence: "GGG"},
+	}
+
+	got := FindDuplicates(entries)
+
+	if !reflect.DeepEqual(got.Accessions, []string{"sp|P00001|A"}) {
+		t.Errorf("Accessions = %v, want [sp|P00001|A]", got.Accessions)
+	}
+
+	if !reflect.DeepEqual(got.Sequences, [][]string{{"sp|P00003|C", "sp|P00002|B"}}) {
+		t.Errorf("Sequences = %v, want [[sp|P00003|C sp|P00002|B]]", got.Sequences)
+	}
+}
+
+func TestWriter_Write(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{"Single line sequences", 0, ">sp|P00001|A\nMPEPTIDEK\n>sp|P00002|B\nMKV\n"},
+		{"Wrapped sequences", 4, ">sp|P00001|A\nMPEP\nTIDE\nK\n>sp|P00002|B\nMKV\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+
+			w := NewWriter(&buf)
+			w.Width = tt.width
+			w.Write(Entry{Header: "sp|P00001|A", Sequence: "MPEPTIDEK"})
+			w.Write(Entry{Header: "sp|P00002|B", Sequence: "MKV"})
+			w.Flush()
+
+			if buf.String() != tt.want {
+				t.Errorf("Write() = %q, want %q", buf.String(), tt.want)
+			}
+
+			r, e := NewReader(&buf)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			var n int
+			for {
+				if _, ok := r.Next(); !ok {
+					break
+				}
+				n++
+			}
+
+			if n != 2 {
+				t.Errorf("Number of FASTA entries is incorrect, got %d, want %d", n, 2)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package fil
+
+import (
+	"philosopher/lib/cla"
+	"philosopher/lib/dat"
+	"philosopher/lib/rep"
+	"philosopher/lib/uti"
+
+	"github.com/sirupsen/logrus"
+)
+
+// entrapmentCount holds the accepted identifications at one level split by their origin
+type entrapmentCount struct {
+	Targets     int
+	Entrapments int
+	Decoys      int
+}
+
+// fdr is the target-decoy estimate, entrapment hits are accepted targets for the decoy counting
+func (c entrapmentCount) fdr() float64 {
+
+	accepted := c.Targets + c.Entrapments
+	if accepted == 0 {
+		return 0
+	}
+
+	return float64(c.Decoys) / float64(accepted)
+}
+
+// fdp is the combined entrapment estimate of the false discovery proportion, N_E (1 + 1/r) / (N_T + N_E),
+// where r is the size of the entrapment database relative to the target database
+func (c entrapmentCount) fdp(ratio float64) float64 {
+
+	accepted := c.Targets + c.Entrapments
+	if accepted == 0 || ratio == 0 {
+		return 0
+	}
+
+	return float64(c.Entrapments) * (1 + 1/ratio) / float64(accepted)
+}
+
+// isEntrapmentHit checks if an identification maps only to entrapment proteins, peptides shared with a
+// target protein are not counted as false discoveries
+func isEntrapmentHit(protein string, mapped map[string]int, decoyTag string) bool {
+
+	if !dat.IsEntrapment(protein, decoyTag) {
+		return false
+	}
+
+	for k := range mapped {
+		if !cla.IsDecoy(k, decoyTag) && !dat.IsEntrapment(k, decoyTag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// entrapmentValidation reports the entrapment-estimated false discovery proportion next to the target-decoy FDR
+// at the PSM, peptide and protein levels. Nothing is reported when the database has no entrapment sequences
+func entrapmentValidation(e rep.Evidence, ratio float64, decoyTag string) {
+
+	if ratio == 0 {
+		return
+	}
+
+	var psm, pep, pro entrapmentCount
+
+	for _, i := range e.PSM {
+		if i.IsDecoy {
+			psm.Decoys++
+		} else if isEntrapmentHit(i.Protein, i.MappedProteins, decoyTag) {
+			psm.Entrapments++
+		} else {
+			psm.Targets++
+		}
+	}
+
+	for _, i := range e.Peptides {
+		if i.IsDecoy {
+			pep.Decoys++
+		} else if isEntrapmentHit(i.Protein, i.MappedProteins, decoyTag) {
+			pep.Entrapments++
+		} else {
+			pep.Targets++
+		}
+	}
+
+	for _, i := range e.Proteins {
+		if i.IsDecoy {
+			pro.Decoys++
+		} else if dat.IsEntrapment(i.OriginalHeader, decoyTag) {
+			pro.Entrapments++
+		} else {
+			pro.Targets++
+		}
+	}
+
+	logrus.Info("Entrapment database ratio ", uti.ToFixed(ratio, 4))
+
+	for _, i := range []struct {
+		level string
+		count entrapmentCount
+	}{
+		{"PSM", psm},
+		{"peptide", pep},
+		{"protein", pro},
+	} {
+		logrus.WithFields(logrus.Fields{
+			"entrapments": i.count.Entrapments,
+			"fdr":         uti.ToFixed(i.count.fdr(), 4),
+			"fdp":         uti.ToFixed(i.count.fdp(ratio), 4),
+		}).Info("Entrapment validation at the ", i.level, " level")
+	}
+}
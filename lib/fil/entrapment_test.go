@@ -0,0 +1,56 @@
+package fil
+
+import (
+	"testing"
+)
+
+func TestEntrapmentCount(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		count entrapmentCount
+		ratio float64
+		fdr   float64
+		fdp   float64
+	}{
+		{"Equal database sizes", entrapmentCount{Targets: 95, Entrapments: 5, Decoys: 1}, 1, 0.01, 0.1},
+		{"Larger entrapment database", entrapmentCount{Targets: 90, Entrapments: 10, Decoys: 2}, 4, 0.02, 0.125},
+		{"No identifications", entrapmentCount{}, 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := tt.count.fdr(); got != tt.fdr {
+				t.Errorf("fdr() = %v, want %v", got, tt.fdr)
+			}
+
+			if got := tt.count.fdp(tt.ratio); got != tt.fdp {
+				t.Errorf("fdp() = %v, want %v", got, tt.fdp)
+			}
+		})
+	}
+}
+
+func TestIsEntrapmentHit(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		protein string
+		mapped  map[string]int
+		want    bool
+	}{
+		{"Entrapment protein", "entrapment_sp|P1|A", map[string]int{}, true},
+		{"Shared with a decoy", "entrapment_sp|P1|A", map[string]int{"rev_sp|P2|B": 0}, true},
+		{"Shared with a target", "entrapment_sp|P1|A", map[string]int{"sp|P2|B": 0}, false},
+		{"Target protein", "sp|P2|B", map[string]int{"entrapment_sp|P1|A": 0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isEntrapmentHit(tt.protein, tt.mapped, "rev_"); got != tt.want {
+				t.Errorf("isEntrapmentHit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
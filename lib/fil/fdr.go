@@ -3,6 +3,7 @@ package fil
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 
@@ -14,8 +15,14 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// PepXMLFDRFilter processes and calculates the FDR at the PSM, Ion or Peptide level
+// PepXMLFDRFilter processes and calculates the FDR at the PSM, Ion or Peptide level using the probabilities
 func PepXMLFDRFilter(input map[string]id.PepIDList, targetFDR float64, level, decoyTag string) (id.PepIDList, float64) {
+	return PepXMLScoreFDRFilter(input, targetFDR, level, decoyTag, id.DefaultScore())
+}
+
+// PepXMLScoreFDRFilter processes and calculates the FDR at the PSM, Ion, Peptidoform or Peptide level, the identifications
+// are ranked and thresholded on the given score
+func PepXMLScoreFDRFilter(input map[string]id.PepIDList, targetFDR float64, level, decoyTag string, score id.Score) (id.PepIDList, float64) {
 
 	//var msg string
 	var targets float64
@@ -39,10 +46,11 @@ func PepXMLFDRFilter(input map[string]id.PepIDList, targetFDR float64, level, de
 			}
 		}
 
-	} else if strings.EqualFold(level, "Peptide") {
+	} else if strings.EqualFold(level, "Peptide") || strings.EqualFold(level, "Peptidoform") || strings.EqualFold(level, "Protein") {
 
 		// 0 index means the one with highest score
 		for _, i := range input {
+			score.Sort(i)
 			peplist = append(peplist, i[0])
 		}
 
@@ -59,6 +67,7 @@ func PepXMLFDRFilter(input map[string]id.PepIDList, targetFDR float64, level, de
 
 		// 0 index means the one with highest score
 		for _, i := range input {
+			score.Sort(i)
 			peplist = append(peplist, i[0])
 		}
 
@@ -73,15 +82,15 @@ func PepXMLFDRFilter(input map[string]id.PepIDList, targetFDR float64, level, de
 
 	}
 
-	sort.Sort(list)
+	score.Sort(list)
 
 	var scoreMap = make(map[float64]float64)
 	limit := (len(list) - 1)
 
 	for j := limit; j >= 0; j-- {
-		_, ok := scoreMap[list[j].Probability]
+		_, ok := scoreMap[score.Value(list[j])]
 		if !ok {
-			scoreMap[list[j].Probability] = (decoys / targets)
+			scoreMap[score.Value(list[j])] = (decoys / targets)
 		}
 		if cla.IsDecoyPSM(list[j], decoyTag) {
 			decoys--
@@ -95,7 +104,9 @@ func PepXMLFDRFilter(input map[string]id.PepIDList, targetFDR float64, level, de
 		keys = append(keys, k)
 	}
 
-	sort.Sort(sort.Reverse(sort.Float64Slice(keys)))
+	sort.Slice(keys, func(i, j int) bool {
+		return score.Better(keys[i], keys[j])
+	})
 
 	var probList = make(map[float64]uint8)
 	for i := range keys {
@@ -115,7 +126,7 @@ func PepXMLFDRFilter(input map[string]id.PepIDList, targetFDR float64, level, de
 	targets = 0
 
 	for i := range list {
-		_, ok := probList[list[i].Probability]
+		_, ok := probList[score.Value(list[i])]
 		if ok {
 			cleanlist = append(cleanlist, list[i])
 			if cla.IsDecoyPSM(list[i], decoyTag) {
@@ -138,6 +149,18 @@ func PepXMLFDRFilter(input map[string]id.PepIDList, targetFDR float64, level, de
 
 // PickedFDR employs the picked FDR strategy
 func PickedFDR(p id.ProtXML) id.ProtXML {
+	return pickedFDR(p, func(j id.ProteinIdentification) float64 {
+		return j.PeptideIons[0].InitialProbability
+	})
+}
+
+// PickedScoreFDR employs the picked FDR strategy comparing each target and decoy pair by the protein score
+func PickedScoreFDR(p id.ProtXML, score ProteinScore) id.ProtXML {
+	return pickedFDR(p, score.Value)
+}
+
+// pickedFDR keeps the best of each target and decoy pair using the given protein value
+func pickedFDR(p id.ProtXML, value func(id.ProteinIdentification) float64) id.ProtXML {
 
 	// var appMap = make(map[string]int)
 	var targetMap = make(map[string]float64)
@@ -148,17 +171,23 @@ func PickedFDR(p id.ProtXML) id.ProtXML {
 	for _, i := range p.Groups {
 		for _, j := range i.Proteins {
 			if cla.IsDecoyProtein(j, p.DecoyTag) {
-				decoyMap[string(j.ProteinName)] = j.PeptideIons[0].InitialProbability
+				decoyMap[string(j.ProteinName)] = value(j)
 			} else {
-				targetMap[string(j.ProteinName)] = j.PeptideIons[0].InitialProbability
+				targetMap[string(j.ProteinName)] = value(j)
 			}
 		}
 	}
 
+	// pair each decoy with the target it was generated from
+	var m = cla.Matcher(p.DecoyTag)
+	var pairs = make(map[string]string)
+	for k := range decoyMap {
+		pairs[m.Target(k)] = k
+	}
+
 	// check unique targets
 	for k := range targetMap {
-		iKey := fmt.Sprintf("%s%s", p.DecoyTag, k)
-		_, ok := decoyMap[iKey]
+		_, ok := pairs[k]
 		if !ok {
 			recordMap[k] = 1
 		}
@@ -166,8 +195,7 @@ func PickedFDR(p id.ProtXML) id.ProtXML {
 
 	// check unique decoys
 	for k := range decoyMap {
-		iKey := strings.Replace(k, p.DecoyTag, "", -1)
-		_, ok := targetMap[iKey]
+		_, ok := targetMap[m.Target(k)]
 		if !ok {
 			recordMap[k] = 1
 		}
@@ -175,9 +203,9 @@ func PickedFDR(p id.ProtXML) id.ProtXML {
 
 	// check paired observations
 	for k, v := range targetMap {
-		iKey := fmt.Sprintf("%s%s", p.DecoyTag, k)
-		vok, ok := decoyMap[iKey]
+		iKey, ok := pairs[k]
 		if ok {
+			vok := decoyMap[iKey]
 			if vok > v {
 				recordMap[k] = 0
 				recordMap[iKey] = 1
@@ -276,6 +304,7 @@ func RazorFilter(p id.ProtXML) id.ProtXML {
 		sort.Strings(rList)
 
 		var razorPair = make(map[string]string)
+		var razorReason = make(map[string]string)
 
 		// get the best protein candidate for each peptide sequence and make the razor pair
 		for _, k := range rList {
@@ -283,6 +312,7 @@ func RazorFilter(p id.ProtXML) id.ProtXML {
 			for pt, w := range r[k].MappedProteinsW {
 				if w > 0.5 {
 					razorPair[k] = pt
+					razorReason[k] = RazorByWeight
 				}
 			}
 		}
@@ -304,6 +334,7 @@ func RazorFilter(p id.ProtXML) id.ProtXML {
 
 					for pt := range r[k].MappedProteinsGW {
 						razorPair[k] = pt
+						razorReason[k] = RazorBySingleProtein
 					}
 
 				} else if len(r[k].MappedProteinsGW) > 1 {
@@ -323,6 +354,7 @@ func RazorFilter(p id.ProtXML) id.ProtXML {
 
 					if !tie {
 						razorPair[k] = topPT
+						razorReason[k] = RazorByGroupWeight
 
 					} else {
 
@@ -362,6 +394,7 @@ func RazorFilter(p id.ProtXML) id.ProtXML {
 							}
 
 							razorPair[k] = topPT
+							razorReason[k] = RazorByTotalPeptides
 
 						} else {
 
@@ -375,6 +408,7 @@ func RazorFilter(p id.ProtXML) id.ProtXML {
 
 							id := strings.Split(idList[0], "#")
 							razorPair[k] = id[1]
+							razorReason[k] = RazorBySiblingID
 						}
 
 					}
@@ -387,6 +421,8 @@ func RazorFilter(p id.ProtXML) id.ProtXML {
 			if ok {
 				razor := r[k]
 				razor.MappedProtein = pt
+				razor.Reason = razorReason[k]
+				razor.Score = razor.reasonScore()
 				r[k] = razor
 			}
 		}
@@ -429,6 +465,12 @@ func RazorFilter(p id.ProtXML) id.ProtXML {
 
 // ProtXMLFilter filters the protein list under a specific fdr
 func ProtXMLFilter(p id.ProtXML, targetFDR, pepProb, protProb float64, isPicked, isRazor bool, decoyTag string) id.ProtIDList {
+	return ProtXMLScoreFilter(p, targetFDR, pepProb, protProb, isPicked, isRazor, decoyTag, DefaultProteinScore())
+}
+
+// ProtXMLScoreFilter filters the protein list under a specific fdr, the proteins are ranked and
+// thresholded on the given protein score
+func ProtXMLScoreFilter(p id.ProtXML, targetFDR, pepProb, protProb float64, isPicked, isRazor bool, decoyTag string, score ProteinScore) id.ProtIDList {
 
 	//var proteinIDs ProtIDList
 	var list id.ProtIDList
@@ -479,16 +521,23 @@ func ProtXMLFilter(p id.ProtXML, targetFDR, pepProb, protProb float64, isPicked,
 		}
 	}
 
-	sort.Sort(&list)
+	var values = make(map[string]float64)
+	for _, i := range list {
+		values[i.ProteinName] = score.Value(i)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return values[list[i].ProteinName] > values[list[j].ProteinName]
+	})
 
 	// from botttom to top, classify every protein block with a given fdr score
 	// the score is only calculates to the first (last) protein in each block
 	// proteins with the same score, get the same fdr value.
 	var scoreMap = make(map[float64]float64)
 	for j := (len(list) - 1); j >= 0; j-- {
-		_, ok := scoreMap[list[j].TopPepProb]
+		_, ok := scoreMap[values[list[j].ProteinName]]
 		if !ok {
-			scoreMap[list[j].TopPepProb] = (decoys / targets)
+			scoreMap[values[list[j].ProteinName]] = (decoys / targets)
 		}
 
 		if cla.IsDecoyProtein(list[j], p.DecoyTag) {
@@ -505,7 +554,7 @@ func ProtXMLFilter(p id.ProtXML, targetFDR, pepProb, protProb float64, isPicked,
 
 	sort.Sort(sort.Reverse(sort.Float64Slice(keys)))
 
-	var curProb = 10.0
+	var curProb = math.Inf(1)
 	var curScore = 0.0
 	var probArray []float64
 	var probList = make(map[float64]uint8)
@@ -533,7 +582,7 @@ func ProtXMLFilter(p id.ProtXML, targetFDR, pepProb, protProb float64, isPicked,
 
 	}
 
-	if curProb == 10 {
+	if math.IsInf(curProb, 1) {
 		msg.Custom(errors.New("the protein FDR filter didn't reach the desired threshold, try a higher threshold using the --prot parameter"), "error")
 	}
 
@@ -568,7 +617,7 @@ func ProtXMLFilter(p id.ProtXML, targetFDR, pepProb, protProb float64, isPicked,
 
 	var cleanlist id.ProtIDList
 	for i := range list {
-		_, ok := probList[list[i].TopPepProb]
+		_, ok := probList[values[list[i].ProteinName]]
 		if ok {
 			cleanlist = append(cleanlist, list[i])
 			if cla.IsDecoyProtein(list[i], p.DecoyTag) {
@@ -591,7 +640,7 @@ func ProtXMLFilter(p id.ProtXML, targetFDR, pepProb, protProb float64, isPicked,
 
 // sequentialFDRControl estimates FDR levels by applying a second filter where all
 // proteins from the protein filtered list are matched against filtered PSMs
-func sequentialFDRControl(pep id.PepIDList, pro id.ProtIDList, psm, peptide, ion float64, decoyTag string) {
+func sequentialFDRControl(pep id.PepIDList, pro id.ProtIDList, psm, peptide, ion float64, decoyTag string, score id.Score) {
 
 	extPep := extractPSMfromPepXML("sequential", pep, pro)
 
@@ -608,20 +657,20 @@ func sequentialFDRControl(pep id.PepIDList, pro id.ProtIDList, psm, peptide, ion
 		"ions":     len(uniqIons),
 	}).Info("Applying sequential FDR estimation")
 
-	filteredPSM, _ := PepXMLFDRFilter(uniqPsms, psm, "PSM", decoyTag)
+	filteredPSM, _ := PepXMLScoreFDRFilter(uniqPsms, psm, "PSM", decoyTag, score)
 	filteredPSM.Serialize("psm")
 
-	filteredPeptides, _ := PepXMLFDRFilter(uniqPeps, peptide, "Peptide", decoyTag)
+	filteredPeptides, _ := PepXMLScoreFDRFilter(uniqPeps, peptide, "Peptide", decoyTag, score)
 	filteredPeptides.Serialize("pep")
 
-	filteredIons, _ := PepXMLFDRFilter(uniqIons, ion, "Ion", decoyTag)
+	filteredIons, _ := PepXMLScoreFDRFilter(uniqIons, ion, "Ion", decoyTag, score)
 	filteredIons.Serialize("ion")
 
 }
 
 // twoDFDRFilter estimates FDR levels by applying a second filter by regenerating
 // a protein list with decoys from protXML and pepXML.
-func twoDFDRFilter(pep id.PepIDList, pro id.ProtIDList, psm, peptide, ion float64, decoyTag string) {
+func twoDFDRFilter(pep id.PepIDList, pro id.ProtIDList, psm, peptide, ion float64, decoyTag string, score id.Score) {
 
 	// filter protein list at given FDR level and regenerate protein list by adding pairing decoys
 	//logrus.Info("Creating mirror image from filtered protein list")
@@ -651,9 +700,9 @@ func twoDFDRFilter(pep id.PepIDList, pro id.ProtIDList, psm, peptide, ion float6
 		"ions":     len(uniqIons),
 	}).Info("Second filtering results")
 
-	filteredPSM, _ := PepXMLFDRFilter(uniqPsms, psm, "PSM", decoyTag)
-	filteredPeptides, _ := PepXMLFDRFilter(uniqPeps, peptide, "Peptide", decoyTag)
-	filteredIons, _ := PepXMLFDRFilter(uniqIons, ion, "Ion", decoyTag)
+	filteredPSM, _ := PepXMLScoreFDRFilter(uniqPsms, psm, "PSM", decoyTag, score)
+	filteredPeptides, _ := PepXMLScoreFDRFilter(uniqPeps, peptide, "Peptide", decoyTag, score)
+	filteredIons, _ := PepXMLScoreFDRFilter(uniqIons, ion, "Ion", decoyTag, score)
 
 	filteredPSM.Serialize("psm")
 	filteredPeptides.Serialize("pep")
@@ -714,16 +763,22 @@ func mirrorProteinList(p id.ProtIDList, decoyTag string) id.ProtIDList {
 		refMap[i.ProteinName] = i
 	}
 
+	// pair the decoys of the original list with their targets
+	var m = cla.Matcher(decoyTag)
+	var pairs = make(map[string]string)
+	for k := range decoys {
+		pairs[m.Target(k)] = k
+	}
+
 	// add decoys correspondent to the given targets.
 	// first check if the opposite list doesn't have an entry already.
 	// if not, search for the mirror entry on the original list, if found
-	// move it to the mirror list, otherwise add fake entry.
+	// move it to the mirror list, otherwise add fake entry. Decoy tags made
+	// only of regular expressions can not name the fake entries, so they are skipped.
 	for _, k := range list {
-		decoy := decoyTag + k.ProteinName
-		v, ok := refMap[decoy]
-		if ok {
-			list = append(list, v)
-		} else {
+		if v, ok := pairs[k.ProteinName]; ok {
+			list = append(list, refMap[v])
+		} else if decoy := m.Decoy(k.ProteinName); len(decoy) > 0 {
 			var pt id.ProteinIdentification
 			pt.ProteinName = decoy
 			list = append(list, pt)
@@ -1,5 +1,34 @@
 package fil
 
+import (
+	"fmt"
+	"philosopher/lib/id"
+	"testing"
+)
+
+func TestPepXMLScoreFDRFilter(t *testing.T) {
+
+	var psms id.PepIDList
+	for n, i := range []float64{0.0001, 0.001, 0.002, 0.004, 0.005, 0.006, 0.007, 0.008} {
+		psms = append(psms, id.PeptideIdentification{Spectrum: fmt.Sprint("target.", n), Peptide: fmt.Sprint("PEPTIDE", n), Protein: "sp|P1|A", Expectation: i})
+	}
+
+	psms = append(psms, id.PeptideIdentification{Spectrum: "decoy.1", Peptide: "DECOYA", Protein: "rev_sp|P1|A", Expectation: 0.003})
+	psms = append(psms, id.PeptideIdentification{Spectrum: "decoy.2", Peptide: "DECOYB", Protein: "rev_sp|P1|A", Expectation: 0.1})
+
+	score, _ := id.NewScore(id.ExpectationScore, "")
+
+	got, threshold := PepXMLScoreFDRFilter(GetUniquePSMs(psms), 0.1, "PSM", "rev_", score)
+
+	if len(got) != 3 {
+		t.Errorf("PepXMLScoreFDRFilter() = %d PSMs, want %d", len(got), 3)
+	}
+
+	if threshold != 0.002 {
+		t.Errorf("PepXMLScoreFDRFilter() threshold = %v, want %v", threshold, 0.002)
+	}
+}
+
 // func TestPepXMLFDRFilter(t *testing.T) {
 
 // 	tes.SetupTestEnv()
@@ -4,15 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 
 	"philosopher/lib/cla"
+	"philosopher/lib/dat"
 	"philosopher/lib/id"
 	"philosopher/lib/inf"
 	"philosopher/lib/met"
 	"philosopher/lib/mod"
+	"philosopher/lib/msg"
 	"philosopher/lib/rep"
 	"philosopher/lib/sys"
 
@@ -33,13 +34,13 @@ func Run(f met.Data) met.Data {
 
 		if _, err := os.Stat(f.Filter.RazorBin); err == nil {
 
-			rdest := fmt.Sprintf("%s%s.meta%srazor.bin", f.Home, string(filepath.Separator), string(filepath.Separator))
-			sys.CopyFile(f.Filter.RazorBin, rdest)
+			rm, e := ImportRazorMap(f.Filter.RazorBin)
+			if e != nil {
+				msg.Custom(fmt.Errorf("cannot import the razor assignment: %s", e), "fatal")
+			}
 
-			var rm RazorMap = make(map[string]RazorCandidate)
-			rm.Restore(false)
+			rm.Serialize()
 			logrus.Info("Fetching razor assignment from: ", f.Filter.RazorBin, ": ", len(rm), " razor groups imported.")
-			_ = rm
 
 		} else if errors.Is(err, os.ErrNotExist) {
 
@@ -55,6 +56,15 @@ func Run(f met.Data) met.Data {
 		f.Filter.Tag = f.Database.Tag
 	}
 
+	score, e1 := id.NewScore(f.Filter.Score, f.Filter.Direction)
+	if e1 != nil {
+		msg.Custom(e1, "fatal")
+	}
+
+	if score.Name != id.ProbabilityScore {
+		logrus.Info("Ranking identifications by ", score.Name)
+	}
+
 	logrus.Info("Processing peptide identification files")
 
 	// if no method is selected, force the 2D to be default
@@ -62,15 +72,44 @@ func Run(f met.Data) met.Data {
 		f.Filter.TwoD = true
 	}
 
-	pepid, searchEngine := id.ReadPepXMLInput(f.Filter.Pex, f.Filter.Tag, f.Temp, f.Filter.Model)
+	pepid, searchEngine := id.ReadPepXMLInput(f.Filter.Pex, f.Filter.Tag, f.Temp, f.Filter.Model, cla.Matcher(f.Filter.Tag))
 
 	f.SearchEngine = searchEngine
 
-	psmT, pepT, ionT := processPeptideIdentifications(pepid, f.Filter.Tag, f.Filter.Mods, f.Filter.PsmFDR, f.Filter.PepFDR, f.Filter.IonFDR)
+	psmT, pepT, ionT := processPeptideIdentifications(pepid, f.Filter.Tag, f.Filter.Mods, f.Filter.PsmFDR, f.Filter.PepFDR, f.Filter.IonFDR, score)
 	_ = psmT
 	_ = pepT
 	_ = ionT
 
+	if f.Filter.FormFDR > 0 {
+		processPeptidoformIdentifications(pepid, f.Filter.Tag, f.Filter.FormFDR, score)
+	}
+
+	// the protein scoring models only count the peptides accepted by the peptide FDR
+	var filteredPeptides id.PepIDList
+	if len(f.Filter.ProtScore) > 0 && f.Filter.ProtScore != TopPeptideModel {
+		filteredPeptides.Restore("pep")
+	}
+
+	proScore, e2 := NewProteinScore(f.Filter.ProtScore, filteredPeptides)
+	if e2 != nil {
+		msg.Custom(e2, "fatal")
+	}
+	filteredPeptides = nil
+
+	// without probabilities the proteins are ranked by their best PSM on the same score as the PSMs
+	if score.Name != id.ProbabilityScore && (len(f.Filter.Pox) > 0 || f.Filter.Inference) {
+
+		var filteredPSM id.PepIDList
+		filteredPSM.Restore("psm")
+
+		proScore, e2 = proScore.WithPSMScore(score, filteredPSM)
+		if e2 != nil {
+			msg.Custom(e2, "fatal")
+		}
+		filteredPSM = nil
+	}
+
 	if _, err := os.Stat(sys.ProBin()); err == nil {
 
 		pro.Restore()
@@ -79,7 +118,7 @@ func Run(f met.Data) met.Data {
 
 		protXML := ReadProtXMLInput(f.Filter.Pox, f.Filter.Tag, f.Filter.Weight)
 
-		ProcessProteinIdentifications(protXML, f.Filter.PtFDR, f.Filter.PepFDR, f.Filter.ProtProb, f.Filter.Picked, f.Filter.Razor, false, f.Filter.Tag)
+		ProcessProteinIdentifications(protXML, f.Filter.PtFDR, f.Filter.PepFDR, f.Filter.ProtProb, f.Filter.Picked, f.Filter.Razor, false, f.Filter.Tag, proScore)
 		pro.Restore()
 
 	} else {
@@ -96,7 +135,7 @@ func Run(f met.Data) met.Data {
 			pepid.Serialize("pep")
 			pepid.Serialize("ion")
 
-			processProteinInferenceIdentifications(pepid, razorMap, coverMap, f.Filter.PtFDR, f.Filter.PepFDR, f.Filter.ProtProb, f.Filter.Picked, f.Filter.Tag)
+			processProteinInferenceIdentifications(pepid, razorMap, coverMap, f.Filter.PtFDR, f.Filter.PepFDR, f.Filter.ProtProb, f.Filter.Picked, f.Filter.Tag, proScore)
 		}
 	}
 
@@ -107,14 +146,14 @@ func Run(f met.Data) met.Data {
 		// sequential analysis
 		// filtered psm list and filtered prot list
 		pep.Restore("psm")
-		sequentialFDRControl(pep, pro, f.Filter.PsmFDR, f.Filter.PepFDR, f.Filter.IonFDR, f.Filter.Tag)
+		sequentialFDRControl(pep, pro, f.Filter.PsmFDR, f.Filter.PepFDR, f.Filter.IonFDR, f.Filter.Tag, score)
 		pep = nil
 
 	} else if f.Filter.TwoD {
 
 		// two-dimensional analysis
 		// complete pep list and filtered mirror-image prot list
-		twoDFDRFilter(pepxml.PeptideIdentification, pro, f.Filter.PsmFDR, f.Filter.PepFDR, f.Filter.IonFDR, f.Filter.Tag)
+		twoDFDRFilter(pepxml.PeptideIdentification, pro, f.Filter.PsmFDR, f.Filter.PepFDR, f.Filter.IonFDR, f.Filter.Tag, score)
 
 	}
 
@@ -149,12 +188,22 @@ func Run(f met.Data) met.Data {
 	// restoring for the modifications
 	e.Mods = pepxml.Modifications
 	e.AssembleSearchParameters(pepxml.SearchParameters)
+
+	// the QC report draws the FDR curve from every PSM, before the filter
+	scores := rep.NewQCScores(pepxml.PeptideIdentification, score, f.Filter.Tag)
+	rep.SerializeQCScores(&scores)
+	scores = nil
+
 	pepxml = id.PepXML{}
 	os.RemoveAll(sys.PepxmlBin())
 
+	// the annotated database is restored once and shared by the reports, the uniqueness, the entrapment and the genes
+	var dtb dat.Base
+	dtb.Restore()
+
 	var psm id.PepIDList
 	psm.Restore("psm")
-	e.AssemblePSMReport(psm, f.Filter.Tag)
+	e.AssemblePSMReport(psm, dtb.Records, f.Filter.Tag)
 	psm = nil
 
 	var ion id.PepIDList
@@ -175,7 +224,7 @@ func Run(f met.Data) met.Data {
 	pept = nil
 
 	logrus.Info("Assigning protein identifications to layers")
-	e.UpdateLayerswithDatabase(f.Filter.Tag)
+	e.UpdateLayerswithDatabase(dtb.Records, f.Filter.Tag)
 
 	// evaluate modifications in data set
 	if f.Filter.Mapmods {
@@ -191,7 +240,7 @@ func Run(f met.Data) met.Data {
 		for i := range e.PSM {
 
 			for j := range e.PSM[i].MappedProteins {
-				if strings.Contains(j, f.Filter.Tag) {
+				if cla.IsDecoy(j, f.Filter.Tag) {
 					delete(e.PSM[i].MappedProteins, j)
 				}
 			}
@@ -222,7 +271,7 @@ func Run(f met.Data) met.Data {
 				e.PSM[i].MappedGenes = make(map[string]int)
 			}
 
-			if strings.Contains(e.PSM[i].Protein, f.Filter.Tag) {
+			if cla.IsDecoy(e.PSM[i].Protein, f.Filter.Tag) {
 				e.PSM[i].IsDecoy = true
 			}
 		}
@@ -235,7 +284,7 @@ func Run(f met.Data) met.Data {
 		logrus.Info("Processing protein inference")
 		pro.Restore()
 
-		e.AssembleProteinReport(pro, f.Filter.Weight, f.Filter.Tag)
+		e.AssembleProteinReport(pro, dtb.Records, f.Filter.Weight, f.Filter.Tag)
 		pro = nil
 
 		// Pushes the new ion status from the protein inferece to the other layers, the gene and protein ID
@@ -246,14 +295,35 @@ func Run(f met.Data) met.Data {
 
 		e = e.SyncPSMToProteins(f.Filter.Tag)
 
-		e.UpdateNumberOfEnzymaticTermini()
+		// the native inference groups the proteins with the parsimony principle
+		if f.Filter.Inference && len(f.Filter.Pox) == 0 {
+			logrus.Info("Grouping proteins")
+			e.UpdateProteinGroups()
+		}
+
+		e.UpdateNumberOfEnzymaticTermini(f.Filter.Tag)
+	}
+
+	if len(f.Filter.Enzyme) > 0 {
+		logrus.Info("Recomputing enzymatic termini and missed cleavages")
+		e.UpdateEnzymaticFeatures(f.Filter.Enzyme)
 	}
 
 	e = e.SyncPSMToPeptides(f.Filter.Tag)
 
 	e = e.SyncPSMToPeptideIons(f.Filter.Tag)
 
-	var countPSM, countPep, countIon, coutProtein int
+	logrus.Info("Classifying peptide uniqueness")
+	e.UpdateUniqueness(dtb.Records, f.Filter.Tag)
+
+	if f.Filter.FormFDR > 0 {
+		var forms id.PepIDList
+		forms.Restore("peptidoform")
+		e.AssemblePeptidoformReport(forms, f.Filter.Tag)
+		forms = nil
+	}
+
+	var countPSM, countPep, countIon, countForm, coutProtein int
 	for _, i := range e.PSM {
 		if !i.IsDecoy {
 			countPSM++
@@ -272,18 +342,53 @@ func Run(f met.Data) met.Data {
 		}
 	}
 
+	for _, i := range e.Peptidoforms {
+		if !i.IsDecoy {
+			countForm++
+		}
+	}
+
 	for _, i := range e.Proteins {
 		if !i.IsDecoy {
 			coutProtein++
 		}
 	}
 
-	logrus.WithFields(logrus.Fields{
+	fields := logrus.Fields{
 		"psms":     countPSM,
 		"peptides": countPep,
 		"ions":     countIon,
 		"proteins": coutProtein,
-	}).Info("Total report numbers after FDR filtering, and post-processing")
+	}
+
+	if f.Filter.FormFDR > 0 {
+		fields["peptidoforms"] = countForm
+	}
+
+	logrus.WithFields(fields).Info("Total report numbers after FDR filtering, and post-processing")
+
+	entrapmentValidation(e, dtb.EntrapmentRatio(), f.Filter.Tag)
+
+	if len(f.Filter.RazorOut) > 0 {
+
+		var rm RazorMap = make(map[string]RazorCandidate)
+		rm.Restore(true)
+
+		if len(rm) == 0 {
+			msg.Custom(errors.New("there is no razor assignment to export, run the filter with the razor option"), "warning")
+		} else if e := rm.Export(f.Filter.RazorOut); e != nil {
+			msg.WriteFile(e, "error")
+		} else {
+			logrus.Info("Razor assignment exported to ", f.Filter.RazorOut)
+		}
+	}
+
+	if f.Filter.Gene {
+		logrus.Info("Processing gene inference")
+		e.Genes = geneInference(e.PSM, dtb.Records, f.Filter.Tag, f.Filter.PtFDR)
+	}
+
+	dtb = dat.Base{}
 
 	logrus.Info("Saving")
 	e.SerializeGranular()
@@ -292,7 +397,7 @@ func Run(f met.Data) met.Data {
 }
 
 // processPeptideIdentifications reads and process pepXML
-func processPeptideIdentifications(p id.PepIDList, decoyTag, mods string, psm, peptide, ion float64) (float64, float64, float64) {
+func processPeptideIdentifications(p id.PepIDList, decoyTag, mods string, psm, peptide, ion float64, score id.Score) (float64, float64, float64) {
 
 	// report charge profile
 	var t, d int
@@ -343,24 +448,40 @@ func processPeptideIdentifications(p id.PepIDList, decoyTag, mods string, psm, p
 		"ions":     len(uniqIons),
 	}).Info("Database search results")
 
-	filteredPSM, psmThreshold := PepXMLFDRFilter(uniqPsms, psm, "PSM", decoyTag)
+	filteredPSM, psmThreshold := PepXMLScoreFDRFilter(uniqPsms, psm, "PSM", decoyTag, score)
 	filteredPSM.Serialize("psm")
 
-	filteredPeptides, peptideThreshold := PepXMLFDRFilter(uniqPeps, peptide, "Peptide", decoyTag)
+	filteredPeptides, peptideThreshold := PepXMLScoreFDRFilter(uniqPeps, peptide, "Peptide", decoyTag, score)
 	filteredPeptides.Serialize("pep")
 
-	filteredIons, ionThreshold := PepXMLFDRFilter(uniqIons, ion, "Ion", decoyTag)
+	filteredIons, ionThreshold := PepXMLScoreFDRFilter(uniqIons, ion, "Ion", decoyTag, score)
 	filteredIons.Serialize("ion")
 
 	// sug-group FDR filtering
 	if len(mods) > 0 {
-		ptmBasedPSMFiltering(uniqPsms, psm, decoyTag, mods)
+		ptmBasedPSMFiltering(uniqPsms, psm, decoyTag, mods, score)
 	}
 
 	return psmThreshold, peptideThreshold, ionThreshold
 }
 
-func ptmBasedPSMFiltering(uniqPsms map[string]id.PepIDList, targetFDR float64, decoyTag, mods string) {
+// processPeptidoformIdentifications applies the FDR on the modified sequences, each modified form of a
+// peptide is counted as a target or decoy of its own
+func processPeptidoformIdentifications(p id.PepIDList, decoyTag string, peptidoform float64, score id.Score) float64 {
+
+	uniqForms := GetUniquePeptidoforms(p)
+
+	logrus.WithFields(logrus.Fields{
+		"peptidoforms": len(uniqForms),
+	}).Info("Database search peptidoforms")
+
+	filteredForms, formThreshold := PepXMLScoreFDRFilter(uniqForms, peptidoform, "Peptidoform", decoyTag, score)
+	filteredForms.Serialize("peptidoform")
+
+	return formThreshold
+}
+
+func ptmBasedPSMFiltering(uniqPsms map[string]id.PepIDList, targetFDR float64, decoyTag, mods string, score id.Score) {
 
 	// unmodified = no ptms
 	// defined = only the ptms defined, nothing else
@@ -425,13 +546,13 @@ func ptmBasedPSMFiltering(uniqPsms map[string]id.PepIDList, targetFDR float64, d
 	}
 
 	logrus.Info("Filtering unmodified PSMs")
-	filteredUnmodPSM, _ := PepXMLFDRFilter(unModPSMs, targetFDR, "PSM", decoyTag)
+	filteredUnmodPSM, _ := PepXMLScoreFDRFilter(unModPSMs, targetFDR, "PSM", decoyTag, score)
 
 	logrus.Info("Filtering defined modified PSMs")
-	filteredDefinedPSM, _ := PepXMLFDRFilter(definedModPSMs, targetFDR, "PSM", decoyTag)
+	filteredDefinedPSM, _ := PepXMLScoreFDRFilter(definedModPSMs, targetFDR, "PSM", decoyTag, score)
 
 	logrus.Info("Filtering all modified PSMs")
-	filteredAllPSM, _ := PepXMLFDRFilter(restModPSMs, targetFDR, "PSM", decoyTag)
+	filteredAllPSM, _ := PepXMLScoreFDRFilter(restModPSMs, targetFDR, "PSM", decoyTag, score)
 
 	var combinedFiltered id.PepIDList
 
@@ -450,7 +571,7 @@ func chargeProfile(p id.PepIDList, charge uint8, decoyTag string) (t, d int) {
 
 	for _, i := range p {
 		if i.AssumedCharge == charge {
-			if strings.HasPrefix(i.Protein, decoyTag) {
+			if cla.IsDecoy(i.Protein, decoyTag) {
 				d++
 			} else {
 				t++
@@ -499,6 +620,23 @@ func ExtractIonsFromPSMs(p id.PepIDList) map[string]id.PepIDList {
 	return uniqMap
 }
 
+// GetUniquePeptidoforms groups the identifications by modified sequence
+func GetUniquePeptidoforms(p id.PepIDList) map[string]id.PepIDList {
+
+	uniqMap := make(map[string]id.PepIDList)
+
+	for _, i := range p {
+		uniqMap[i.Peptidoform()] = append(uniqMap[i.Peptidoform()], i)
+	}
+
+	// organize id list by score
+	for _, v := range uniqMap {
+		sort.Sort(v)
+	}
+
+	return uniqMap
+}
+
 // GetUniquePeptides selects only unique pepetide for the given data structure
 func GetUniquePeptides(p id.PepIDList) map[string]id.PepIDList {
 
@@ -527,7 +665,7 @@ func ReadProtXMLInput(xmlFile, decoyTag string, weight float64) id.ProtXML {
 
 	protXML.MarkUniquePeptides(weight)
 
-	protXML.PromoteProteinIDs()
+	protXML.PromoteProteinIDs(cla.Matcher(decoyTag))
 
 	//protXML.Serialize()
 
@@ -536,7 +674,7 @@ func ReadProtXMLInput(xmlFile, decoyTag string, weight float64) id.ProtXML {
 
 // ProcessProteinIdentifications checks if pickedFDR ar razor options should be applied to given data set, if they do,
 // the inputed protXML data is processed before filtered.
-func ProcessProteinIdentifications(p id.ProtXML, ptFDR, pepProb, protProb float64, isPicked, isRazor, isCombined bool, decoyTag string) string {
+func ProcessProteinIdentifications(p id.ProtXML, ptFDR, pepProb, protProb float64, isPicked, isRazor, isCombined bool, decoyTag string, score ProteinScore) string {
 
 	var pid id.ProtIDList
 
@@ -548,7 +686,7 @@ func ProcessProteinIdentifications(p id.ProtXML, ptFDR, pepProb, protProb float6
 	}).Info("Protein inference results")
 
 	// applies pickedFDR algorithm
-	if isPicked {
+	if isPicked && score.Model == TopPeptideModel {
 		p = PickedFDR(p)
 	}
 
@@ -557,8 +695,13 @@ func ProcessProteinIdentifications(p id.ProtXML, ptFDR, pepProb, protProb float6
 		p = RazorFilter(p)
 	}
 
+	// the scoring models pick the proteins after the razor assignment, some of them only count razor peptides
+	if isPicked && score.Model != TopPeptideModel {
+		p = PickedScoreFDR(p, score)
+	}
+
 	// run the FDR filter for proteins
-	pid = ProtXMLFilter(p, ptFDR, pepProb, protProb, isPicked, isRazor, decoyTag)
+	pid = ProtXMLScoreFilter(p, ptFDR, pepProb, protProb, isPicked, isRazor, decoyTag, score)
 
 	// save results on meta folder
 	if isCombined {
@@ -573,7 +716,7 @@ func ProcessProteinIdentifications(p id.ProtXML, ptFDR, pepProb, protProb float6
 
 // processProteinInferenceIdentifications checks if pickedFDR ar razor options should be applied to given data set, if they do,
 // the inputed Philosopher inference data is processed before filtered.
-func processProteinInferenceIdentifications(psm id.PepIDList, razorMap map[string]string, coverMap map[string]float64, ptFDR, pepProb, protProb float64, isPicked bool, decoyTag string) {
+func processProteinInferenceIdentifications(psm id.PepIDList, razorMap map[string]string, coverMap map[string]float64, ptFDR, pepProb, protProb float64, isPicked bool, decoyTag string, score ProteinScore) {
 
 	var t int
 	var d int
@@ -609,7 +752,7 @@ func processProteinInferenceIdentifications(psm id.PepIDList, razorMap map[strin
 	}
 
 	for i := range proteinList {
-		if strings.HasPrefix(i, decoyTag) {
+		if cla.IsDecoy(i, decoyTag) {
 			d++
 		} else {
 			t++
@@ -771,7 +914,7 @@ func processProteinInferenceIdentifications(psm id.PepIDList, razorMap map[strin
 	}).Info("Protein inference results")
 
 	// run the FDR filter for proteins
-	pid := ProtXMLFilter(proXML, ptFDR, pepProb, protProb, false, true, decoyTag)
+	pid := ProtXMLScoreFilter(proXML, ptFDR, pepProb, protProb, false, true, decoyTag, score)
 
 	// save results on meta folder
 	pid.Serialize()
@@ -1,6 +1,7 @@
 package fil
 
 import (
+	"philosopher/lib/cla"
 	"philosopher/lib/id"
 	"philosopher/lib/sys"
 	"philosopher/lib/tes"
@@ -39,7 +40,7 @@ func Test_readPepXMLInput(t *testing.T) {
 
 		t.Run(tt.name, func(t *testing.T) {
 
-			got, got1 := id.ReadPepXMLInput(tt.args.xmlFile, tt.args.decoyTag, tt.args.temp, tt.args.models)
+			got, got1 := id.ReadPepXMLInput(tt.args.xmlFile, tt.args.decoyTag, tt.args.temp, tt.args.models, cla.Matcher(tt.args.decoyTag))
 			pepIDList = got
 
 			if !reflect.DeepEqual(len(got), tt.want) {
@@ -125,7 +126,7 @@ func Test_readPepXMLInput(t *testing.T) {
 	for _, tt := range test2 {
 
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1, got2 := processPeptideIdentifications(pepIDList, tt.args.decoyTag, "", tt.args.psm, tt.args.peptide, tt.args.ion)
+			got, got1, got2 := processPeptideIdentifications(pepIDList, tt.args.decoyTag, "", tt.args.psm, tt.args.peptide, tt.args.ion, id.DefaultScore())
 			if got != tt.want {
 				t.Errorf("processPeptideIdentifications(psm) got = %v, want %v", got, tt.want)
 			}
@@ -345,7 +346,52 @@ func Test_readProtXMLInput(t *testing.T) {
 	}
 	for _, tt := range test3 {
 		t.Run(tt.name, func(t *testing.T) {
-			ProcessProteinIdentifications(proXML, tt.args.ptFDR, tt.args.pepProb, tt.args.protProb, tt.args.isPicked, tt.args.isRazor, false, tt.args.decoyTag)
+			ProcessProteinIdentifications(proXML, tt.args.ptFDR, tt.args.pepProb, tt.args.protProb, tt.args.isPicked, tt.args.isRazor, false, tt.args.decoyTag, DefaultProteinScore())
 		})
 	}
 }
+
+func TestGetUniquePeptidoforms(t *testing.T) {
+
+	p := id.PepIDList{
+		{Spectrum: "a.1", Peptide: "PEPTMIDE", Probability: 0.90},
+		{Spectrum: "a.2", Peptide: "PEPTMIDE", ModifiedPeptide: "PEPTM[147]IDE", Probability: 0.99},
+		{Spectrum: "a.3", Peptide: "PEPTMIDE", ModifiedPeptide: "PEPTM[147]IDE", Probability: 0.80},
+		{Spectrum: "a.4", Peptide: "DECOYPEP", Protein: "rev_sp|P2|B", Probability: 0.95},
+	}
+
+	forms := GetUniquePeptidoforms(p)
+
+	tests := []struct {
+		form string
+		want []string
+	}{
+		{"PEPTMIDE", []string{"a.1"}},
+		{"PEPTM[147]IDE", []string{"a.2", "a.3"}},
+		{"DECOYPEP", []string{"a.4"}},
+	}
+
+	if len(forms) != len(tests) {
+		t.Fatalf("GetUniquePeptidoforms() = %d peptidoforms, want %d", len(forms), len(tests))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.form, func(t *testing.T) {
+
+			var got []string
+			for _, i := range forms[tt.form] {
+				got = append(got, i.Spectrum)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetUniquePeptidoforms()[%s] = %v, want %v", tt.form, got, tt.want)
+			}
+		})
+	}
+
+	// the decoy scores between the two target forms, so only the best one passes
+	filtered, _ := PepXMLScoreFDRFilter(forms, 0.01, "Peptidoform", "rev_", id.DefaultScore())
+	if len(filtered) != 1 || filtered[0].Peptidoform() != "PEPTM[147]IDE" {
+		t.Errorf("PepXMLScoreFDRFilter() = %v, want the PEPTM[147]IDE peptidoform", filtered)
+	}
+}
@@ -0,0 +1,234 @@
+package fil
+
+import (
+	"fmt"
+	"sort"
+
+	"philosopher/lib/cla"
+	"philosopher/lib/dat"
+	"philosopher/lib/rep"
+
+	"github.com/sirupsen/logrus"
+)
+
+// geneKey identifies a gene, decoy genes share the name of the target gene they are paired to
+type geneKey struct {
+	name  string
+	decoy bool
+}
+
+// geneInference collapses the proteins from the PSMs into genes using the annotated database. Shared
+// peptides are assigned to the gene with the most peptides, and the genes are filtered with a picked
+// gene-level FDR ranked by the top probability of their razor peptides
+func geneInference(psm rep.PSMEvidenceList, records []dat.Record, decoyTag string, targetFDR float64) rep.GeneEvidenceList {
+
+	var annotation = make(map[string]dat.Record)
+	for _, i := range records {
+		annotation[i.PartHeader] = i
+	}
+
+	// proteins without a gene annotation are kept as their own gene
+	geneOf := func(protein string) geneKey {
+
+		key := geneKey{decoy: cla.IsDecoy(protein, decoyTag)}
+
+		name := protein
+		if key.decoy {
+			name = cla.TargetName(protein, decoyTag)
+		}
+
+		if r, ok := annotation[name]; ok && len(r.GeneNames) > 0 {
+			key.name = r.GeneNames
+		} else if r, ok := annotation[protein]; ok && len(r.GeneNames) > 0 {
+			key.name = r.GeneNames
+		} else {
+			key.name = name
+		}
+
+		return key
+	}
+
+	var genes = make(map[geneKey]*rep.GeneEvidence)
+	var proteins = make(map[geneKey]map[string]uint8)
+	var peptides = make(map[string]map[geneKey]uint8)
+
+	for _, i := range psm {
+
+		if _, ok := peptides[i.Peptide]; !ok {
+			peptides[i.Peptide] = make(map[geneKey]uint8)
+		}
+
+		var mapped = []string{i.Protein}
+		for j := range i.MappedProteins {
+			mapped = append(mapped, j)
+		}
+
+		for _, j := range mapped {
+
+			key := geneOf(j)
+
+			if _, ok := genes[key]; !ok {
+				genes[key] = &rep.GeneEvidence{
+					GeneName:       key.name,
+					IsDecoy:        key.decoy,
+					TotalPeptides:  make(map[string]uint8),
+					UniquePeptides: make(map[string]uint8),
+					RazorPeptides:  make(map[string]uint8),
+				}
+				proteins[key] = make(map[string]uint8)
+			}
+
+			if _, ok := proteins[key][j]; !ok {
+				proteins[key][j] = 0
+				genes[key].Proteins = append(genes[key].Proteins, j)
+				if r, ok := annotation[j]; ok && len(r.ID) > 0 {
+					genes[key].ProteinIDs = append(genes[key].ProteinIDs, r.ID)
+				}
+			}
+
+			genes[key].TotalPeptides[i.Peptide] = 0
+			peptides[i.Peptide][key] = 0
+		}
+	}
+
+	// the razor gene of a peptide is the one with the most peptides, ties go to the first name and to targets
+	var razor = make(map[string]geneKey)
+	for k, v := range peptides {
+
+		var best geneKey
+		var found bool
+		for j := range v {
+			if !found || razorGeneLess(j, best, genes) {
+				best = j
+				found = true
+			}
+		}
+
+		razor[k] = best
+	}
+
+	for _, i := range psm {
+
+		best := razor[i.Peptide]
+		unique := len(peptides[i.Peptide]) == 1
+
+		for key := range peptides[i.Peptide] {
+
+			g := genes[key]
+
+			g.TotalSpC++
+			g.TotalIntensity += i.Intensity
+
+			if unique {
+				g.UniquePeptides[i.Peptide] = 0
+				g.UniqueSpC++
+				g.UniqueIntensity += i.Intensity
+			}
+
+			if key == best {
+				g.RazorPeptides[i.Peptide] = 0
+				g.RazorSpC++
+				g.RazorIntensity += i.Intensity
+				if i.Probability > g.TopPepProb {
+					g.TopPepProb = i.Probability
+				}
+			}
+		}
+	}
+
+	// picked FDR, only the best scoring gene of each target and decoy pair is kept
+	var picked = make(map[string]*rep.GeneEvidence)
+	for _, g := range genes {
+
+		if len(g.RazorPeptides) == 0 {
+			continue
+		}
+
+		sort.Strings(g.Proteins)
+		sort.Strings(g.ProteinIDs)
+
+		v, ok := picked[g.GeneName]
+		if !ok || g.TopPepProb > v.TopPepProb || (g.TopPepProb == v.TopPepProb && !g.IsDecoy) {
+			picked[g.GeneName] = g
+		}
+	}
+
+	var list rep.GeneEvidenceList
+	for _, g := range picked {
+		list = append(list, *g)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].TopPepProb != list[j].TopPepProb {
+			return list[i].TopPepProb > list[j].TopPepProb
+		}
+		return list[i].GeneName < list[j].GeneName
+	})
+
+	// the threshold is the lowest score where the decoy to target ratio is still below the target FDR
+	var targets, decoys, accepted int
+	var calcFDR, minProb float64
+	for i := range list {
+
+		if list[i].IsDecoy {
+			decoys++
+		} else {
+			targets++
+		}
+
+		if i < len(list)-1 && list[i+1].TopPepProb == list[i].TopPepProb {
+			continue
+		}
+
+		fdr := float64(decoys)
+		if targets > 0 {
+			fdr = float64(decoys) / float64(targets)
+		}
+
+		if fdr <= targetFDR {
+			accepted = i + 1
+			calcFDR = fdr
+			minProb = list[i].TopPepProb
+		}
+	}
+
+	list = list[:accepted]
+
+	targets, decoys = 0, 0
+	for i := range list {
+		if list[i].IsDecoy {
+			decoys++
+			if name := cla.DecoyName(list[i].GeneName, decoyTag); len(name) > 0 {
+				list[i].GeneName = name
+			}
+		} else {
+			targets++
+		}
+	}
+
+	sort.Sort(list)
+
+	msg := fmt.Sprintf("Converged to %.2f %% FDR with %d Genes", (calcFDR * 100), targets)
+	logrus.WithFields(logrus.Fields{
+		"decoy":     decoys,
+		"total":     (targets + decoys),
+		"threshold": minProb,
+	}).Info(msg)
+
+	return list
+}
+
+// razorGeneLess tells if a gene takes the razor peptides over another one
+func razorGeneLess(a, b geneKey, genes map[geneKey]*rep.GeneEvidence) bool {
+
+	na, nb := len(genes[a].TotalPeptides), len(genes[b].TotalPeptides)
+	if na != nb {
+		return na > nb
+	}
+
+	if a.name != b.name {
+		return a.name < b.name
+	}
+
+	return !a.decoy && b.decoy
+}
@@ -0,0 +1,63 @@
+package fil
+
+import (
+	"philosopher/lib/dat"
+	"philosopher/lib/rep"
+	"reflect"
+	"testing"
+)
+
+func TestGeneInference(t *testing.T) {
+
+	records := []dat.Record{
+		{ID: "P1", PartHeader: "sp|P1|A", GeneNames: "GA"},
+		{ID: "P2", PartHeader: "sp|P2|B", GeneNames: "GA"},
+		{ID: "P3", PartHeader: "sp|P3|C", GeneNames: "GC"},
+		{ID: "P4", PartHeader: "sp|P4|D"},
+	}
+
+	psm := rep.PSMEvidenceList{
+		{Peptide: "PEPTIDEA", Protein: "sp|P1|A", Probability: 0.99, Intensity: 10},
+		{Peptide: "PEPTIDEB", Protein: "sp|P2|B", Probability: 0.98, Intensity: 20},
+		{Peptide: "PEPTIDEC", Protein: "sp|P1|A", MappedProteins: map[string]int{"sp|P3|C": 0}, Probability: 0.97, Intensity: 30},
+		{Peptide: "PEPTIDED", Protein: "sp|P3|C", Probability: 0.96, Intensity: 40},
+		{Peptide: "PEPTIDEE", Protein: "sp|P4|D", Probability: 0.95, Intensity: 50},
+		{Peptide: "DECOYD", Protein: "rev_sp|P4|D", Probability: 0.50},
+		{Peptide: "DECOYZ", Protein: "rev_sp|P9|Z", Probability: 0.90},
+	}
+
+	genes := geneInference(psm, records, "rev_", 0.01)
+
+	type gene struct {
+		name     string
+		proteins []string
+		unique   int
+		razor    int
+		spc      int
+		razorSpc int
+		prob     float64
+		razorInt float64
+	}
+
+	tests := []gene{
+		{"GA", []string{"sp|P1|A", "sp|P2|B"}, 2, 3, 3, 3, 0.99, 60},
+		{"GC", []string{"sp|P3|C"}, 1, 1, 2, 1, 0.96, 40},
+		{"sp|P4|D", []string{"sp|P4|D"}, 1, 1, 1, 1, 0.95, 50},
+	}
+
+	if len(genes) != len(tests) {
+		t.Fatalf("geneInference() = %d genes, want %d", len(genes), len(tests))
+	}
+
+	for n, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			g := genes[n]
+			got := gene{g.GeneName, g.Proteins, len(g.UniquePeptides), len(g.RazorPeptides), g.TotalSpC, g.RazorSpC, g.TopPepProb, g.RazorIntensity}
+
+			if !reflect.DeepEqual(got, tt) {
+				t.Errorf("gene = %v, want %v", got, tt)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package fil
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"philosopher/lib/cla"
+	"philosopher/lib/id"
+	"philosopher/lib/met"
+	"philosopher/lib/msg"
+	"philosopher/lib/rep"
+	"philosopher/lib/sys"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmihailenco/msgpack"
+)
+
+// GlobalFDR holds the peptides and proteins accepted by the experiment-wide FDR control
+type GlobalFDR struct {
+	PeptideThreshold float64
+	ProteinThreshold float64
+	Peptides         map[string]uint8
+	Proteins         map[string]uint8
+}
+
+// GlobalFilter pools the identifications from every data set, estimates the peptide and protein FDR once for the
+// whole experiment and writes the accepted set back to each data set. The local filters still apply, so the global
+// pass removes the peptides and proteins that do not pass the experiment-wide FDR
+func GlobalFilter(f met.Filter, temp, dir string, data []string) {
+
+	score, e := id.NewScore(f.Score, f.Direction)
+	if e != nil {
+		msg.Custom(e, "fatal")
+	}
+
+	logrus.Info("Pooling identifications from ", len(data), " data sets")
+
+	var pooled id.PepIDList
+	var inferred = make(map[string]map[string]uint8)
+	for _, i := range data {
+
+		dsAbs, _ := filepath.Abs(i)
+		os.Chdir(dsAbs)
+
+		pepid, _ := id.ReadPepXMLInput(f.Pex, f.Tag, temp, false, cla.Matcher(f.Tag))
+		pooled = append(pooled, pepid...)
+		os.RemoveAll(sys.PepxmlBin())
+
+		// the proteins come from the inference of each data set, with their unique and razor peptides
+		var proteins rep.ProteinEvidenceList
+		rep.RestoreProtein(&proteins)
+		addInferredProteins(inferred, proteins)
+
+		os.Chdir(dir)
+	}
+
+	if len(pooled) == 0 {
+		msg.NoPSMFound(errors.New("no identifications to pool for the global FDR"), "fatal")
+	}
+
+	g := globalFDR(pooled, inferred, f.PepFDR, f.PtFDR, f.Tag, score)
+
+	for _, i := range data {
+
+		dsAbs, _ := filepath.Abs(i)
+		os.Chdir(dsAbs)
+
+		var e rep.Evidence
+		e.RestoreGranular()
+
+		e = g.apply(e)
+
+		logrus.WithFields(logrus.Fields{
+			"psms":     len(e.PSM),
+			"peptides": len(e.Peptides),
+			"proteins": len(e.Proteins),
+		}).Info("Global FDR applied on ", i)
+
+		e.SerializeGranular()
+		g.Serialize()
+
+		os.Chdir(dir)
+	}
+}
+
+// addInferredProteins collects the peptides assigned to each inferred protein, the unique and razor peptides
+// when the razor assignment ran and every mapped peptide otherwise
+func addInferredProteins(inferred map[string]map[string]uint8, proteins rep.ProteinEvidenceList) {
+
+	for _, i := range proteins {
+
+		peptides := i.URazorPeptides
+		if len(peptides) == 0 {
+			peptides = i.TotalPeptides
+		}
+
+		if _, ok := inferred[i.PartHeader]; !ok {
+			inferred[i.PartHeader] = make(map[string]uint8)
+		}
+
+		for j := range peptides {
+			inferred[i.PartHeader][j] = 0
+		}
+	}
+}
+
+// globalFDR estimates the peptide and protein FDR over the pooled identifications. Proteins are the ones inferred
+// on the data sets and are ranked by the best PSM of their assigned peptides, the protein name decides if it is
+// a decoy. Without inferred proteins, the PSMs are grouped by the protein of their first hit
+func globalFDR(pooled id.PepIDList, inferred map[string]map[string]uint8, pepFDR, proFDR float64, decoyTag string, score id.Score) GlobalFDR {
+
+	var g GlobalFDR
+	g.Peptides = make(map[string]uint8)
+	g.Proteins = make(map[string]uint8)
+
+	logrus.Info("Estimating the global peptide FDR")
+	peptides, pepThreshold := PepXMLScoreFDRFilter(GetUniquePeptides(pooled), pepFDR, "Peptide", decoyTag, score)
+	for _, i := range peptides {
+		g.Peptides[i.Peptide] = 0
+	}
+
+	var proteins = make(map[string]id.PepIDList)
+
+	if len(inferred) == 0 {
+
+		logrus.Warn("No inferred proteins found, the global protein FDR uses the first protein of each PSM")
+
+		for _, i := range pooled {
+			i.AlternativeProteins = nil
+			proteins[i.Protein] = append(proteins[i.Protein], i)
+		}
+
+	} else {
+
+		var psms = make(map[string]id.PepIDList)
+		for _, i := range pooled {
+			psms[i.Peptide] = append(psms[i.Peptide], i)
+		}
+
+		for k, v := range inferred {
+			for j := range v {
+				for _, i := range psms[j] {
+					i.Protein = k
+					i.AlternativeProteins = nil
+					proteins[k] = append(proteins[k], i)
+				}
+			}
+		}
+	}
+
+	logrus.Info("Estimating the global protein FDR")
+	accepted, proThreshold := PepXMLScoreFDRFilter(proteins, proFDR, "Protein", decoyTag, score)
+	for _, i := range accepted {
+		g.Proteins[i.Protein] = 0
+	}
+
+	g.PeptideThreshold = pepThreshold
+	g.ProteinThreshold = proThreshold
+
+	return g
+}
+
+// apply removes from a data set the evidences outside the global set
+func (g GlobalFDR) apply(e rep.Evidence) rep.Evidence {
+
+	var psm rep.PSMEvidenceList
+	for _, i := range e.PSM {
+		if _, ok := g.Peptides[i.Peptide]; ok {
+			psm = append(psm, i)
+		}
+	}
+	e.PSM = psm
+
+	var ions rep.IonEvidenceList
+	for _, i := range e.Ions {
+		if _, ok := g.Peptides[i.Sequence]; ok {
+			ions = append(ions, i)
+		}
+	}
+	e.Ions = ions
+
+	var peptides rep.PeptideEvidenceList
+	for _, i := range e.Peptides {
+		if _, ok := g.Peptides[i.Sequence]; ok {
+			peptides = append(peptides, i)
+		}
+	}
+	e.Peptides = peptides
+
+	var proteins rep.ProteinEvidenceList
+	for _, i := range e.Proteins {
+		if _, ok := g.Proteins[i.PartHeader]; ok {
+			proteins = append(proteins, i)
+		}
+	}
+	e.Proteins = proteins
+
+	return e
+}
+
+// Serialize saves the global set on the workspace
+func (g *GlobalFDR) Serialize() {
+
+	b, e := msgpack.Marshal(&g)
+	if e != nil {
+		msg.MarshalFile(e, "fatal")
+	}
+
+	e = ioutil.WriteFile(sys.GlobalBin(), b, sys.FilePermission())
+	if e != nil {
+		msg.WriteFile(e, "fatal")
+	}
+}
+
+// RestoreWithPath reads the global set from a workspace, the set is empty when
+// the data set was not filtered in the global mode
+func (g *GlobalFDR) RestoreWithPath(p string) {
+
+	path := fmt.Sprintf("%s%s%s", p, string(filepath.Separator), sys.GlobalBin())
+
+	b, e := ioutil.ReadFile(path)
+	if e != nil {
+		return
+	}
+
+	e = msgpack.Unmarshal(b, &g)
+	if e != nil {
+		msg.DecodeMsgPck(e, "warning")
+	}
+}
+
+// IsPeptideAccepted checks if a peptide sequence passed the global FDR
+func (g GlobalFDR) IsPeptideAccepted(sequence string) bool {
+	_, ok := g.Peptides[sequence]
+	return ok
+}
+
+// IsProteinAccepted checks if a protein passed the global FDR
+func (g GlobalFDR) IsProteinAccepted(protein string) bool {
+	_, ok := g.Proteins[protein]
+	return ok
+}
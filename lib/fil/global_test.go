@@ -0,0 +1,82 @@
+package fil
+
+import (
+	"philosopher/lib/id"
+	"philosopher/lib/rep"
+	"reflect"
+	"testing"
+)
+
+func TestGlobalFDR(t *testing.T) {
+
+	// the same peptides identified in two data sets
+	pooled := id.PepIDList{
+		{Spectrum: "a.1", Peptide: "PEPTIDEA", Protein: "sp|P1|A", Probability: 0.99},
+		{Spectrum: "a.2", Peptide: "PEPTIDEB", Protein: "sp|P1|A", Probability: 0.98},
+		{Spectrum: "a.3", Peptide: "PEPTIDEE", Protein: "sp|P5|E", Probability: 0.50},
+		{Spectrum: "b.1", Peptide: "PEPTIDEA", Protein: "sp|P1|A", Probability: 0.60},
+		{Spectrum: "b.2", Peptide: "PEPTIDEC", Protein: "sp|P2|C", Probability: 0.97},
+		{Spectrum: "b.3", Peptide: "PEPTIDED", Protein: "sp|P3|D", Probability: 0.96},
+		{Spectrum: "b.4", Peptide: "DECOYX", Protein: "rev_sp|P4|X", Probability: 0.95, AlternativeProteins: map[string]int{"sp|P4|X": 0}},
+	}
+
+	g := globalFDR(pooled, nil, 0.01, 0.01, "rev_", id.DefaultScore())
+
+	wantPeptides := map[string]uint8{"PEPTIDEA": 0, "PEPTIDEB": 0, "PEPTIDEC": 0, "PEPTIDED": 0}
+	if !reflect.DeepEqual(g.Peptides, wantPeptides) {
+		t.Errorf("Peptides = %v, want %v", g.Peptides, wantPeptides)
+	}
+
+	wantProteins := map[string]uint8{"sp|P1|A": 0, "sp|P2|C": 0, "sp|P3|D": 0}
+	if !reflect.DeepEqual(g.Proteins, wantProteins) {
+		t.Errorf("Proteins = %v, want %v", g.Proteins, wantProteins)
+	}
+
+	e := rep.Evidence{
+		PSM:      rep.PSMEvidenceList{{Peptide: "PEPTIDEA"}, {Peptide: "PEPTIDEE"}},
+		Peptides: rep.PeptideEvidenceList{{Sequence: "PEPTIDEA"}, {Sequence: "PEPTIDEE"}},
+		Proteins: rep.ProteinEvidenceList{{PartHeader: "sp|P1|A"}, {PartHeader: "sp|P5|E"}},
+	}
+
+	e = g.apply(e)
+
+	if len(e.PSM) != 1 || e.PSM[0].Peptide != "PEPTIDEA" {
+		t.Errorf("PSMs after the global FDR = %v, want only PEPTIDEA", e.PSM)
+	}
+
+	if len(e.Peptides) != 1 || e.Peptides[0].Sequence != "PEPTIDEA" {
+		t.Errorf("Peptides after the global FDR = %v, want only PEPTIDEA", e.Peptides)
+	}
+
+	if len(e.Proteins) != 1 || e.Proteins[0].PartHeader != "sp|P1|A" {
+		t.Errorf("Proteins after the global FDR = %v, want only sp|P1|A", e.Proteins)
+	}
+}
+
+func TestGlobalFDR_InferredProteins(t *testing.T) {
+
+	pooled := id.PepIDList{
+		{Spectrum: "a.1", Peptide: "PEPTIDEA", Protein: "sp|P1|A", Probability: 0.99},
+		{Spectrum: "a.2", Peptide: "PEPTIDEB", Protein: "sp|P1|A", Probability: 0.98, AlternativeProteins: map[string]int{"sp|P6|F": 0}},
+		{Spectrum: "b.1", Peptide: "PEPTIDEC", Protein: "sp|P2|C", Probability: 0.97},
+		{Spectrum: "b.2", Peptide: "DECOYX", Protein: "rev_sp|P4|X", Probability: 0.40},
+	}
+
+	// the razor assignment placed PEPTIDEB on a protein that is not the first hit of its PSM
+	var inferred = make(map[string]map[string]uint8)
+	addInferredProteins(inferred, rep.ProteinEvidenceList{
+		{PartHeader: "sp|P1|A", URazorPeptides: map[string]int{"PEPTIDEA": 1}, TotalPeptides: map[string]int{"PEPTIDEA": 1, "PEPTIDEB": 1}},
+		{PartHeader: "sp|P6|F", URazorPeptides: map[string]int{"PEPTIDEB": 1}},
+	})
+	addInferredProteins(inferred, rep.ProteinEvidenceList{
+		{PartHeader: "sp|P2|C", TotalPeptides: map[string]int{"PEPTIDEC": 1}},
+		{PartHeader: "rev_sp|P4|X", TotalPeptides: map[string]int{"DECOYX": 1}},
+	})
+
+	g := globalFDR(pooled, inferred, 0.01, 0.01, "rev_", id.DefaultScore())
+
+	want := map[string]uint8{"sp|P1|A": 0, "sp|P2|C": 0, "sp|P6|F": 0}
+	if !reflect.DeepEqual(g.Proteins, want) {
+		t.Errorf("Proteins = %v, want %v", g.Proteins, want)
+	}
+}
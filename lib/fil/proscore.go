@@ -0,0 +1,175 @@
+package fil
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"philosopher/lib/id"
+)
+
+// protein scoring models
+const (
+	TopPeptideModel = "top"
+	BestPEPModel    = "bestpep"
+	PEPProductModel = "pepproduct"
+	LogOddsModel    = "logodds"
+	MaxQuantModel   = "maxquant"
+)
+
+// minPEP bounds the posterior error probabilities so that peptides with probability 1 keep a finite score
+const minPEP = 1e-10
+
+// ProteinScore selects the model used to rank the proteins for the protein FDR. The top model uses the top
+// peptide probability from the protein inference, the other models are computed from the filtered peptides,
+// and all of them are higher for better proteins
+type ProteinScore struct {
+	Model    string
+	peptides map[string]float64
+	psms     map[string]float64
+}
+
+// NewProteinScore creates a protein scoring model, the peptides are the list accepted by the peptide
+// FDR, and when the list is empty the peptide probabilities from the protein inference are used
+func NewProteinScore(model string, peptides id.PepIDList) (ProteinScore, error) {
+
+	var self ProteinScore
+
+	model = strings.ToLower(model)
+	if len(model) == 0 {
+		model = TopPeptideModel
+	}
+
+	switch model {
+	case TopPeptideModel, BestPEPModel, PEPProductModel, LogOddsModel, MaxQuantModel:
+		self.Model = model
+	default:
+		return self, fmt.Errorf("unknown protein score %s, use top, bestpep, pepproduct, logodds or maxquant", model)
+	}
+
+	if len(peptides) > 0 {
+		self.peptides = make(map[string]float64)
+		for _, i := range peptides {
+			if i.Probability > self.peptides[i.Peptide] {
+				self.peptides[i.Peptide] = i.Probability
+			}
+		}
+	}
+
+	return self, nil
+}
+
+// DefaultProteinScore ranks the proteins by their top peptide probability
+func DefaultProteinScore() ProteinScore {
+	return ProteinScore{Model: TopPeptideModel}
+}
+
+// WithPSMScore ranks the proteins by the best PSM value of a score other than the probability, the PSMs
+// are the list accepted by the PSM FDR. Only the top model can be ranked this way, the other models
+// need the peptide probabilities
+func (s ProteinScore) WithPSMScore(score id.Score, psms id.PepIDList) (ProteinScore, error) {
+
+	if s.Model != TopPeptideModel {
+		return s, fmt.Errorf("the %s protein score needs the peptide probabilities, use the top model to rank the proteins by %s", s.Model, score.Name)
+	}
+
+	s.psms = make(map[string]float64)
+
+	for _, i := range psms {
+
+		// the values are stored so that higher is better, like the probability based models
+		v := score.Value(i)
+		if !score.HigherBetter {
+			v = -v
+		}
+
+		proteins := []string{i.Protein}
+		for j := range i.AlternativeProteins {
+			proteins = append(proteins, j)
+		}
+
+		for _, j := range proteins {
+			if best, ok := s.psms[j]; !ok || v > best {
+				s.psms[j] = v
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// Value returns the score of a protein
+func (s ProteinScore) Value(p id.ProteinIdentification) float64 {
+
+	var score float64
+
+	if s.psms != nil {
+		if v, ok := s.psms[p.ProteinName]; ok {
+			return v
+		}
+		return math.Inf(-1)
+	}
+
+	switch s.Model {
+	case BestPEPModel:
+		// the lowest PEP, reported as 1 - PEP
+		for _, i := range s.probabilities(p, false) {
+			if i > score {
+				score = i
+			}
+		}
+	case PEPProductModel:
+		// the product of the PEPs of every peptide, reported as -log10
+		for _, i := range s.probabilities(p, false) {
+			score -= math.Log10(pep(i))
+		}
+	case LogOddsModel:
+		// the sum of the log-odds of the unique and razor peptides
+		for _, i := range s.probabilities(p, true) {
+			score += math.Log10((1 - pep(i)) / pep(i))
+		}
+	case MaxQuantModel:
+		// the product of the best PEP of each unique and razor peptide sequence, reported as -log10
+		for _, i := range s.probabilities(p, true) {
+			score -= math.Log10(pep(i))
+		}
+	default:
+		score = p.TopPepProb
+	}
+
+	return score
+}
+
+// probabilities collects the best probability of each peptide sequence of a protein, peptides rejected
+// by the peptide FDR are left out
+func (s ProteinScore) probabilities(p id.ProteinIdentification, razorOnly bool) map[string]float64 {
+
+	var probs = make(map[string]float64)
+
+	for _, i := range p.PeptideIons {
+
+		if razorOnly && !i.IsUnique && i.Razor != 1 {
+			continue
+		}
+
+		prob := i.InitialProbability
+		if s.peptides != nil {
+			v, ok := s.peptides[i.PeptideSequence]
+			if !ok {
+				continue
+			}
+			prob = v
+		}
+
+		if prob > probs[i.PeptideSequence] {
+			probs[i.PeptideSequence] = prob
+		}
+	}
+
+	return probs
+}
+
+// pep converts a probability into a bounded posterior error probability
+func pep(prob float64) float64 {
+	return math.Max(1-prob, minPEP)
+}
@@ -0,0 +1,99 @@
+package fil
+
+import (
+	"math"
+	"philosopher/lib/id"
+	"testing"
+)
+
+func TestProteinScore_Value(t *testing.T) {
+
+	protein := id.ProteinIdentification{
+		ProteinName: "sp|P1|A",
+		TopPepProb:  0.99,
+		PeptideIons: []id.PeptideIonIdentification{
+			{PeptideSequence: "PEPTIDEA", InitialProbability: 0.99, IsUnique: true},
+			{PeptideSequence: "PEPTIDEA", InitialProbability: 0.80, IsUnique: true},
+			{PeptideSequence: "PEPTIDEB", InitialProbability: 0.90, Razor: 1},
+			{PeptideSequence: "PEPTIDEC", InitialProbability: 0.50},
+		},
+	}
+
+	filtered := id.PepIDList{
+		{Peptide: "PEPTIDEA", Probability: 0.99},
+		{Peptide: "PEPTIDEB", Probability: 0.90},
+	}
+
+	tests := []struct {
+		model    string
+		peptides id.PepIDList
+		want     float64
+	}{
+		{TopPeptideModel, nil, 0.99},
+		{BestPEPModel, filtered, 0.99},
+		{PEPProductModel, filtered, 3},
+		{PEPProductModel, nil, 3 + math.Log10(2)},
+		{LogOddsModel, filtered, math.Log10(99) + math.Log10(9)},
+		{MaxQuantModel, nil, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+
+			s, e := NewProteinScore(tt.model, tt.peptides)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if got := s.Value(protein); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, e := NewProteinScore("ibaq", nil); e == nil {
+		t.Error("NewProteinScore() expected an error for an unknown model")
+	}
+}
+
+func TestProteinScore_WithPSMScore(t *testing.T) {
+
+	psms := id.PepIDList{
+		{Peptide: "PEPTIDEA", Protein: "sp|P1|A", Expectation: 0.001},
+		{Peptide: "PEPTIDEB", Protein: "sp|P1|A", Expectation: 0.05, AlternativeProteins: map[string]int{"sp|P2|B": 0}},
+		{Peptide: "DECOYX", Protein: "rev_sp|P3|X", Expectation: 0.2},
+	}
+
+	score, e := id.NewScore(id.ExpectationScore, "")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	s, e := DefaultProteinScore().WithPSMScore(score, psms)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	tests := []struct {
+		protein string
+		want    float64
+	}{
+		{"sp|P1|A", -0.001},
+		{"sp|P2|B", -0.05},
+		{"rev_sp|P3|X", -0.2},
+		{"sp|P4|D", math.Inf(-1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protein, func(t *testing.T) {
+			// the proteins are ranked without their peptide probabilities
+			if got := s.Value(id.ProteinIdentification{ProteinName: tt.protein, TopPepProb: 0}); got != tt.want {
+				t.Errorf("Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, e := (ProteinScore{Model: LogOddsModel}).WithPSMScore(score, psms); e == nil {
+		t.Error("WithPSMScore() expected an error for a probability based model")
+	}
+}
@@ -1,13 +1,39 @@
 package fil
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
 	"philosopher/lib/msg"
 	"philosopher/lib/sys"
 
 	"github.com/vmihailenco/msgpack"
 )
 
+// reasons for a razor assignment, in the order the rules are tried
+const (
+	RazorByWeight        = "weight"
+	RazorBySingleProtein = "single protein"
+	RazorByGroupWeight   = "group weight"
+	RazorByTotalPeptides = "total peptides"
+	RazorBySiblingID     = "group sibling"
+)
+
+// RazorFormat identifies the razor assignment files, the version is increased when the columns change
+const (
+	RazorFormat        = "philosopher razor assignment"
+	RazorFormatVersion = 1
+)
+
 // RazorCandidate is a peptide sequence to be evaluated as a razor
 type RazorCandidate struct {
 	Sequence          string
@@ -16,11 +42,29 @@ type RazorCandidate struct {
 	MappedProteinsTNP map[string]int
 	MappedproteinsSID map[string]string
 	MappedProtein     string
+	Reason            string
+	Score             float64
 }
 
 // a Map fo Razor candidates
 type RazorMap map[string]RazorCandidate
 
+// RazorAssignment is a peptide to protein pair from a razor assignment file
+type RazorAssignment struct {
+	Peptide    string   `json:"peptide"`
+	Protein    string   `json:"protein"`
+	Reason     string   `json:"reason"`
+	Score      float64  `json:"score"`
+	Candidates []string `json:"candidates"`
+}
+
+// razorDocument is the layout of the JSON razor assignment files
+type razorDocument struct {
+	Format      string            `json:"format"`
+	Version     int               `json:"version"`
+	Assignments []RazorAssignment `json:"assignments"`
+}
+
 // Serialize converts the razor structure to a gob file
 func (p *RazorMap) Serialize() {
 
@@ -50,3 +94,216 @@ func (p *RazorMap) Restore(silent bool) {
 	}
 
 }
+
+// reasonScore returns the value that decided the razor assignment
+func (c RazorCandidate) reasonScore() float64 {
+
+	switch c.Reason {
+	case RazorByWeight:
+		return c.MappedProteinsW[c.MappedProtein]
+	case RazorBySingleProtein, RazorByGroupWeight:
+		return c.MappedProteinsGW[c.MappedProtein]
+	case RazorByTotalPeptides, RazorBySiblingID:
+		return float64(c.MappedProteinsTNP[c.MappedProtein])
+	}
+
+	return 0
+}
+
+// Assignments lists the assigned peptides sorted by sequence
+func (p RazorMap) Assignments() []RazorAssignment {
+
+	var list []RazorAssignment
+	for k, v := range p {
+
+		if len(v.MappedProtein) == 0 {
+			continue
+		}
+
+		var candidates []string
+		for i := range v.MappedProteinsW {
+			candidates = append(candidates, i)
+		}
+		sort.Strings(candidates)
+
+		list = append(list, RazorAssignment{
+			Peptide:    k,
+			Protein:    v.MappedProtein,
+			Reason:     v.Reason,
+			Score:      v.Score,
+			Candidates: candidates,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Peptide < list[j].Peptide })
+
+	return list
+}
+
+// Export writes the razor assignment to a JSON file when the name ends with .json, and to a TSV file otherwise
+func (p RazorMap) Export(output string) error {
+
+	file, e := os.Create(output)
+	if e != nil {
+		return e
+	}
+	defer file.Close()
+
+	if strings.ToLower(filepath.Ext(output)) == ".json" {
+
+		enc := json.NewEncoder(file)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(razorDocument{Format: RazorFormat, Version: RazorFormatVersion, Assignments: p.Assignments()})
+	}
+
+	_, e = io.WriteString(file, fmt.Sprintf("# %s version %d\nPeptide\tProtein\tReason\tScore\tCandidates\n", RazorFormat, RazorFormatVersion))
+	if e != nil {
+		return e
+	}
+
+	for _, i := range p.Assignments() {
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%.4f\t%s\n",
+			i.Peptide,
+			i.Protein,
+			i.Reason,
+			i.Score,
+			strings.Join(i.Candidates, ", "),
+		)
+
+		_, e = io.WriteString(file, line)
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// ImportRazorMap reads a razor assignment from a TSV or JSON file, razor.bin files from other workspaces are
+// read as they are
+func ImportRazorMap(input string) (RazorMap, error) {
+
+	var r RazorMap = make(map[string]RazorCandidate)
+
+	switch strings.ToLower(filepath.Ext(input)) {
+	case ".bin":
+
+		b, e := ioutil.ReadFile(input)
+		if e != nil {
+			return r, e
+		}
+
+		e = msgpack.Unmarshal(b, &r)
+		return r, e
+
+	case ".json":
+
+		b, e := ioutil.ReadFile(input)
+		if e != nil {
+			return r, e
+		}
+
+		var doc razorDocument
+		e = json.Unmarshal(b, &doc)
+		if e != nil {
+			return r, e
+		}
+
+		if doc.Format != RazorFormat {
+			return r, fmt.Errorf("%s is not a razor assignment file", input)
+		}
+
+		e = checkRazorVersion(doc.Version)
+		if e != nil {
+			return r, e
+		}
+
+		for _, i := range doc.Assignments {
+			r.add(i)
+		}
+
+		return r, nil
+	}
+
+	file, e := os.Open(input)
+	if e != nil {
+		return r, e
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), fmt.Sprintf("# %s version ", RazorFormat)) {
+		return r, fmt.Errorf("%s is not a razor assignment file", input)
+	}
+
+	version, e := strconv.Atoi(strings.TrimPrefix(scanner.Text(), fmt.Sprintf("# %s version ", RazorFormat)))
+	if e != nil {
+		return r, fmt.Errorf("cannot read the razor assignment version: %s", e)
+	}
+
+	e = checkRazorVersion(version)
+	if e != nil {
+		return r, e
+	}
+
+	// column names
+	scanner.Scan()
+
+	for scanner.Scan() {
+
+		parts := strings.Split(scanner.Text(), "\t")
+		if len(parts) < 2 {
+			continue
+		}
+
+		a := RazorAssignment{Peptide: parts[0], Protein: parts[1]}
+
+		if len(parts) > 2 {
+			a.Reason = parts[2]
+		}
+
+		if len(parts) > 3 {
+			a.Score, _ = strconv.ParseFloat(parts[3], 64)
+		}
+
+		if len(parts) > 4 && len(parts[4]) > 0 {
+			a.Candidates = strings.Split(parts[4], ", ")
+		}
+
+		r.add(a)
+	}
+
+	return r, scanner.Err()
+}
+
+// add inserts an imported assignment, the candidate proteins have no weights
+func (p RazorMap) add(a RazorAssignment) {
+
+	c := RazorCandidate{
+		Sequence:        a.Peptide,
+		MappedProtein:   a.Protein,
+		Reason:          a.Reason,
+		Score:           a.Score,
+		MappedProteinsW: make(map[string]float64),
+	}
+
+	c.MappedProteinsW[a.Protein] = -1
+	for _, i := range a.Candidates {
+		c.MappedProteinsW[i] = -1
+	}
+
+	p[a.Peptide] = c
+}
+
+// checkRazorVersion rejects files written by a newer version of the format
+func checkRazorVersion(version int) error {
+
+	if version < 1 || version > RazorFormatVersion {
+		return errors.New("unsupported razor assignment version " + strconv.Itoa(version))
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package fil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRazorMap_Export(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "razor")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	var r RazorMap = map[string]RazorCandidate{
+		"PEPTIDEA": {
+			Sequence:        "PEPTIDEA",
+			MappedProteinsW: map[string]float64{"sp|P1|A": 0.9, "sp|P2|B": 0.1},
+			MappedProtein:   "sp|P1|A",
+			Reason:          RazorByWeight,
+			Score:           0.9,
+		},
+		"PEPTIDEB": {
+			Sequence:        "PEPTIDEB",
+			MappedProteinsW: map[string]float64{"sp|P2|B": 0.5, "sp|P3|C": 0.5},
+			MappedProtein:   "sp|P3|C",
+			Reason:          RazorByTotalPeptides,
+			Score:           4,
+		},
+		"PEPTIDEC": {
+			Sequence:        "PEPTIDEC",
+			MappedProteinsW: map[string]float64{"sp|P1|A": -1},
+		},
+	}
+
+	want := []RazorAssignment{
+		{"PEPTIDEA", "sp|P1|A", RazorByWeight, 0.9, []string{"sp|P1|A", "sp|P2|B"}},
+		{"PEPTIDEB", "sp|P3|C", RazorByTotalPeptides, 4, []string{"sp|P2|B", "sp|P3|C"}},
+	}
+
+	for _, name := range []string{"razor.tsv", "razor.json"} {
+		t.Run(name, func(t *testing.T) {
+
+			output := filepath.Join(dir, name)
+
+			if e := r.Export(output); e != nil {
+				t.Fatal(e)
+			}
+
+			got, e := ImportRazorMap(output)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if !reflect.DeepEqual(got.Assignments(), want) {
+				t.Errorf("ImportRazorMap() = %v, want %v", got.Assignments(), want)
+			}
+		})
+	}
+}
+
+func TestImportRazorMap_Version(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "razor")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"newer.tsv", "# philosopher razor assignment version 2\nPeptide\tProtein\n"},
+		{"missing.tsv", "Peptide\tProtein\nPEPTIDEA\tsp|P1|A\n"},
+		{"newer.json", `{"format": "philosopher razor assignment", "version": 2}`},
+		{"other.json", `{"format": "other", "version": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			input := filepath.Join(dir, tt.name)
+			if e := ioutil.WriteFile(input, []byte(tt.content), 0644); e != nil {
+				t.Fatal(e)
+			}
+
+			if _, e := ImportRazorMap(input); e == nil {
+				t.Error("ImportRazorMap() expected an error")
+			}
+		})
+	}
+}
@@ -110,6 +110,21 @@ func (p PepIDList) Swap(i, j int) {
 	p[i], p[j] = p[j], p[i]
 }
 
+// Peptidoform returns the modified sequence, unmodified peptides are identified by their stripped sequence
+func (p PeptideIdentification) Peptidoform() string {
+
+	if len(p.ModifiedPeptide) > 0 {
+		return p.ModifiedPeptide
+	}
+
+	return p.Peptide
+}
+
+// DecoyMatcher recognizes decoy protein names, it is implemented by the classification matcher
+type DecoyMatcher interface {
+	IsDecoy(name string) bool
+}
+
 // Read is the main function for parsing pepxml data
 func (p *PepXML) Read(f string) {
 
@@ -240,7 +255,7 @@ func (p *PepXML) Read(f string) {
 }
 
 // ReadPepXMLInput reads one or more fies and organize the data into PSM list
-func ReadPepXMLInput(xmlFile, decoyTag, temp string, models bool) (PepIDList, string) {
+func ReadPepXMLInput(xmlFile, decoyTag, temp string, models bool, m DecoyMatcher) (PepIDList, string) {
 
 	var files = make(map[string]uint8)
 	var pepIdent PepIDList
@@ -317,7 +332,7 @@ func ReadPepXMLInput(xmlFile, decoyTag, temp string, models bool) (PepIDList, st
 	pepXML.Modifications.Index = modsIndex
 
 	// promoting Spectra that matches to both decoys and targets to TRUE hits
-	pepXML.PromoteProteinIDs()
+	pepXML.PromoteProteinIDs(m)
 
 	// serialize all pep files
 	sort.Sort(pepXML.PeptideIdentification)
@@ -381,6 +396,8 @@ func processSpectrumQuery(sq spc.SpectrumQuery, mods mod.Modifications, decoyTag
 
 					if k.Name == "massd" {
 						psm.IsoMassD, _ = strconv.Atoi(k.Value)
+					} else if k.Name == "fval" {
+						psm.DiscriminantValue, _ = strconv.ParseFloat(k.Value, 64)
 					}
 				}
 			}
@@ -572,7 +589,7 @@ func (p *PeptideIdentification) mapModsFromPepXML(m spc.ModificationInfo, mods m
 
 // PromoteProteinIDs changes the identification in cases where the reference protein is a decoy and
 // the alternative proteins contains target proteins.
-func (p *PepXML) PromoteProteinIDs() {
+func (p *PepXML) PromoteProteinIDs(m DecoyMatcher) {
 
 	for i := range p.PeptideIdentification {
 
@@ -582,7 +599,7 @@ func (p *PepXML) PromoteProteinIDs() {
 		var list = make(map[string]int)
 		var isUniProt bool
 
-		if strings.Contains(p.PeptideIdentification[i].Protein, p.DecoyTag) {
+		if m.IsDecoy(p.PeptideIdentification[i].Protein) {
 
 			current = p.PeptideIdentification[i].Protein
 
@@ -592,7 +609,7 @@ func (p *PepXML) PromoteProteinIDs() {
 					isUniProt = true
 				}
 
-				if !strings.HasPrefix(j, p.DecoyTag) {
+				if !m.IsDecoy(j) {
 					list[j]++
 				}
 			}
@@ -809,6 +826,8 @@ func (p *PepIDList) Serialize(level string) {
 		dest = sys.PepBin()
 	} else if level == "ion" {
 		dest = sys.IonBin()
+	} else if level == "peptidoform" {
+		dest = sys.PeptidoformBin()
 	} else {
 		msg.Custom(errors.New("cannot determine binary data class"), "fatal")
 	}
@@ -836,6 +855,8 @@ func (p *PepIDList) Restore(level string) {
 		dest = sys.PepBin()
 	} else if level == "ion" {
 		dest = sys.IonBin()
+	} else if level == "peptidoform" {
+		dest = sys.PeptidoformBin()
 	} else {
 		msg.Custom(errors.New("cannot determine binary data class"), "fatal")
 	}
@@ -210,7 +210,7 @@ func (p *ProtXML) Read(f string) {
 
 // PromoteProteinIDs promotes protein identifications where the reference protein
 // is indistinguishable to other target proteins.
-func (p *ProtXML) PromoteProteinIDs() {
+func (p *ProtXML) PromoteProteinIDs(m DecoyMatcher) {
 
 	for i := range p.Groups {
 		for j := range p.Groups[i].Proteins {
@@ -218,9 +218,9 @@ func (p *ProtXML) PromoteProteinIDs() {
 			var list []string
 			var ref string
 
-			if strings.HasPrefix(string(p.Groups[i].Proteins[j].ProteinName), p.DecoyTag) {
+			if m.IsDecoy(string(p.Groups[i].Proteins[j].ProteinName)) {
 				for k := range p.Groups[i].Proteins[j].IndistinguishableProtein {
-					if !strings.HasPrefix(string(p.Groups[i].Proteins[j].IndistinguishableProtein[k]), p.DecoyTag) {
+					if !m.IsDecoy(string(p.Groups[i].Proteins[j].IndistinguishableProtein[k])) {
 						list = append(list, string(p.Groups[i].Proteins[j].IndistinguishableProtein[k]))
 					}
 				}
@@ -43,7 +43,7 @@ func TestProtXML_MarkUniquePeptides(t *testing.T) {
 
 			p.MarkUniquePeptides(tt.args.w)
 
-			p.PromoteProteinIDs()
+			p.PromoteProteinIDs(prefixMatcher(p.DecoyTag))
 
 			for _, i := range p.Groups {
 				for _, j := range i.Proteins {
@@ -78,3 +78,10 @@ func TestProtXML_MarkUniquePeptides(t *testing.T) {
 	}
 
 }
+
+// prefixMatcher recognizes decoys tagged with a plain prefix
+type prefixMatcher string
+
+func (m prefixMatcher) IsDecoy(name string) bool {
+	return strings.HasPrefix(name, string(m))
+}
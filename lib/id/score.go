@@ -0,0 +1,110 @@
+package id
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// scores available for the FDR filtering
+const (
+	ProbabilityScore  = "probability"
+	ExpectationScore  = "expectation"
+	HyperscoreScore   = "hyperscore"
+	XcorrScore        = "xcorr"
+	DiscriminantScore = "discriminant"
+)
+
+// score directions
+const (
+	HigherIsBetter = "higher"
+	LowerIsBetter  = "lower"
+)
+
+// scoreDirections holds the default direction of each score
+var scoreDirections = map[string]string{
+	ProbabilityScore:  HigherIsBetter,
+	ExpectationScore:  LowerIsBetter,
+	HyperscoreScore:   HigherIsBetter,
+	XcorrScore:        HigherIsBetter,
+	DiscriminantScore: HigherIsBetter,
+}
+
+// Score selects the PSM score used to rank identifications and the direction in which it improves
+type Score struct {
+	Name         string
+	HigherBetter bool
+}
+
+// NewScore creates a score selection, an empty name selects the probability and an empty direction
+// uses the usual direction of the score
+func NewScore(name, direction string) (Score, error) {
+
+	var self Score
+
+	name = strings.ToLower(name)
+	if len(name) == 0 {
+		name = ProbabilityScore
+	}
+
+	def, ok := scoreDirections[name]
+	if !ok {
+		return self, fmt.Errorf("unknown score %s, use probability, expectation, hyperscore, xcorr or discriminant", name)
+	}
+
+	if len(direction) == 0 {
+		direction = def
+	}
+
+	switch strings.ToLower(direction) {
+	case HigherIsBetter:
+		self.HigherBetter = true
+	case LowerIsBetter:
+		self.HigherBetter = false
+	default:
+		return self, fmt.Errorf("unknown score direction %s, use higher or lower", direction)
+	}
+
+	self.Name = name
+
+	return self, nil
+}
+
+// DefaultScore ranks the identifications by their probability
+func DefaultScore() Score {
+	return Score{Name: ProbabilityScore, HigherBetter: true}
+}
+
+// Value returns the selected score of a PSM
+func (s Score) Value(p PeptideIdentification) float64 {
+
+	switch s.Name {
+	case ExpectationScore:
+		return p.Expectation
+	case HyperscoreScore:
+		return p.Hyperscore
+	case XcorrScore:
+		return p.Xcorr
+	case DiscriminantScore:
+		return p.DiscriminantValue
+	}
+
+	return p.Probability
+}
+
+// Better checks if the first score value is better than the second one
+func (s Score) Better(a, b float64) bool {
+
+	if s.HigherBetter {
+		return a > b
+	}
+
+	return a < b
+}
+
+// Sort orders the list from the best to the worst score
+func (s Score) Sort(p PepIDList) {
+	sort.SliceStable(p, func(i, j int) bool {
+		return s.Better(s.Value(p[i]), s.Value(p[j]))
+	})
+}
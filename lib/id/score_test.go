@@ -0,0 +1,70 @@
+package id
+
+import (
+	"testing"
+)
+
+func TestNewScore(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		score     string
+		direction string
+		want      Score
+		wantErr   bool
+	}{
+		{"Default score", "", "", Score{Name: ProbabilityScore, HigherBetter: true}, false},
+		{"Expectation default direction", "Expectation", "", Score{Name: ExpectationScore, HigherBetter: false}, false},
+		{"Explicit direction", "hyperscore", "lower", Score{Name: HyperscoreScore, HigherBetter: false}, false},
+		{"Unknown score", "mascot", "", Score{}, true},
+		{"Unknown direction", "xcorr", "up", Score{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, e := NewScore(tt.score, tt.direction)
+			if (e != nil) != tt.wantErr {
+				t.Fatalf("NewScore() error = %v, wantErr %v", e, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("NewScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScore_Sort(t *testing.T) {
+
+	list := PepIDList{
+		{Spectrum: "a", Expectation: 0.01, Hyperscore: 20},
+		{Spectrum: "b", Expectation: 0.0001, Hyperscore: 35},
+		{Spectrum: "c", Expectation: 0.1, Hyperscore: 12},
+	}
+
+	tests := []struct {
+		score string
+		want  string
+	}{
+		{ExpectationScore, "bac"},
+		{HyperscoreScore, "bac"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.score, func(t *testing.T) {
+
+			s, _ := NewScore(tt.score, "")
+			s.Sort(list)
+
+			var got string
+			for _, i := range list {
+				got += i.Spectrum
+			}
+
+			if got != tt.want {
+				t.Errorf("Sort() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package inf
+
+import (
+	"sort"
+	"strings"
+)
+
+// protein group membership labels
+const (
+	LeadingProtein           = "leading"
+	IndistinguishableProtein = "indistinguishable"
+	SubsetProtein            = "subset"
+	SubsumableProtein        = "subsumable"
+)
+
+// GroupMember is a protein assigned to a group and the peptides mapped to it
+type GroupMember struct {
+	Protein  string
+	Label    string
+	Peptides []string
+}
+
+// ProteinGroup is a set of proteins explained by the same parsimonious protein. Components are the connected
+// parts of the peptide-protein graph, a component holds one or more groups
+type ProteinGroup struct {
+	Number    int
+	Component int
+	Leading   string
+	Peptides  []string
+	Members   []GroupMember
+}
+
+// ProteinGroupList is a list of protein groups
+type ProteinGroupList []ProteinGroup
+
+// proteinSet is a set of proteins with exactly the same peptides
+type proteinSet struct {
+	proteins  []string
+	peptides  []string
+	component int
+	group     int
+}
+
+// GroupProteins builds the bipartite graph between peptides and the proteins they map to, splits it into
+// connected components and applies the parsimony principle on each component. The minimal set of proteins
+// explaining all peptides lead the groups, proteins with the same peptides are indistinguishable, proteins
+// whose peptides are contained in a single group are subsets, and proteins whose peptides are spread over
+// several groups are subsumable and join the group sharing most of their peptides
+func GroupProteins(mapping map[string][]string) ProteinGroupList {
+
+	var proteinPeptides = make(map[string]map[string]uint8)
+	for pep, pros := range mapping {
+		for _, pro := range pros {
+			if _, ok := proteinPeptides[pro]; !ok {
+				proteinPeptides[pro] = make(map[string]uint8)
+			}
+			proteinPeptides[pro][pep] = 0
+		}
+	}
+
+	// collapse the proteins with the same peptides
+	var setIndex = make(map[string]int)
+	var sets []*proteinSet
+
+	var proteins []string
+	for k := range proteinPeptides {
+		proteins = append(proteins, k)
+	}
+	sort.Strings(proteins)
+
+	for _, i := range proteins {
+
+		var peptides []string
+		for k := range proteinPeptides[i] {
+			peptides = append(peptides, k)
+		}
+		sort.Strings(peptides)
+
+		key := strings.Join(peptides, ",")
+		idx, ok := setIndex[key]
+		if !ok {
+			idx = len(sets)
+			setIndex[key] = idx
+			sets = append(sets, &proteinSet{peptides: peptides, group: -1})
+		}
+		sets[idx].proteins = append(sets[idx].proteins, i)
+	}
+
+	// larger sets are preferred by the parsimony and lead the group numbering
+	sort.SliceStable(sets, func(i, j int) bool {
+		if len(sets[i].peptides) != len(sets[j].peptides) {
+			return len(sets[i].peptides) > len(sets[j].peptides)
+		}
+		return sets[i].proteins[0] < sets[j].proteins[0]
+	})
+
+	components := connectedComponents(sets)
+
+	var groups ProteinGroupList
+	for c, comp := range components {
+		for _, i := range comp {
+			sets[i].component = c + 1
+		}
+		groups = append(groups, parsimony(sets, comp)...)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Peptides) != len(groups[j].Peptides) {
+			return len(groups[i].Peptides) > len(groups[j].Peptides)
+		}
+		return groups[i].Leading < groups[j].Leading
+	})
+
+	// number the groups and renumber the components following the group order
+	var componentNumber = make(map[int]int)
+	for i := range groups {
+		groups[i].Number = i + 1
+
+		n, ok := componentNumber[groups[i].Component]
+		if !ok {
+			n = len(componentNumber) + 1
+			componentNumber[groups[i].Component] = n
+		}
+		groups[i].Component = n
+	}
+
+	return groups
+}
+
+// connectedComponents splits the protein sets into the connected components of the peptide graph
+func connectedComponents(sets []*proteinSet) [][]int {
+
+	var parent = make([]int, len(sets))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	var peptideSet = make(map[string]int)
+	for i, s := range sets {
+		for _, p := range s.peptides {
+			if j, ok := peptideSet[p]; ok {
+				a, b := find(i), find(j)
+				if a < b {
+					parent[b] = a
+				} else if b < a {
+					parent[a] = b
+				}
+			} else {
+				peptideSet[p] = i
+			}
+		}
+	}
+
+	var index = make(map[int]int)
+	var components [][]int
+	for i := range sets {
+		root := find(i)
+		c, ok := index[root]
+		if !ok {
+			c = len(components)
+			index[root] = c
+			components = append(components, nil)
+		}
+		components[c] = append(components[c], i)
+	}
+
+	return components
+}
+
+// parsimony selects the minimal number of protein sets explaining the peptides of a component, using a greedy
+// set cover, and assigns the remaining sets to the selected ones
+func parsimony(sets []*proteinSet, comp []int) ProteinGroupList {
+
+	var uncovered = make(map[string]uint8)
+	for _, i := range comp {
+		for _, p := range sets[i].peptides {
+			uncovered[p] = 0
+		}
+	}
+
+	var groups ProteinGroupList
+	var selected []int
+
+	for len(uncovered) > 0 {
+
+		best, count := -1, 0
+		for _, i := range comp {
+			if sets[i].group >= 0 {
+				continue
+			}
+
+			var n int
+			for _, p := range sets[i].peptides {
+				if _, ok := uncovered[p]; ok {
+					n++
+				}
+			}
+
+			// sets are sorted, so ties keep the larger set
+			if n > count {
+				best, count = i, n
+			}
+		}
+
+		for _, p := range sets[best].peptides {
+			delete(uncovered, p)
+		}
+
+		sets[best].group = len(groups)
+		selected = append(selected, best)
+
+		var g ProteinGroup
+		g.Component = sets[best].component
+		g.Leading = sets[best].proteins[0]
+		g.Peptides = sets[best].peptides
+
+		for n, i := range sets[best].proteins {
+			label := IndistinguishableProtein
+			if n == 0 {
+				label = LeadingProtein
+			}
+			g.Members = append(g.Members, GroupMember{Protein: i, Label: label, Peptides: sets[best].peptides})
+		}
+
+		groups = append(groups, g)
+	}
+
+	var subsets, subsumables = make([][]GroupMember, len(groups)), make([][]GroupMember, len(groups))
+
+	for _, i := range comp {
+		if sets[i].group >= 0 {
+			continue
+		}
+
+		var container, shared, target = -1, 0, 0
+		for _, j := range selected {
+
+			n := sharedPeptides(sets[i].peptides, sets[j].peptides)
+
+			if n == len(sets[i].peptides) && container < 0 {
+				container = sets[j].group
+			}
+
+			if n > shared {
+				shared, target = n, sets[j].group
+			}
+		}
+
+		for _, p := range sets[i].proteins {
+			if container >= 0 {
+				subsets[container] = append(subsets[container], GroupMember{Protein: p, Label: SubsetProtein, Peptides: sets[i].peptides})
+			} else {
+				subsumables[target] = append(subsumables[target], GroupMember{Protein: p, Label: SubsumableProtein, Peptides: sets[i].peptides})
+			}
+		}
+	}
+
+	for i := range groups {
+		sortMembers(subsets[i])
+		sortMembers(subsumables[i])
+		groups[i].Members = append(groups[i].Members, subsets[i]...)
+		groups[i].Members = append(groups[i].Members, subsumables[i]...)
+	}
+
+	return groups
+}
+
+// sharedPeptides counts the peptides present on both sorted lists
+func sharedPeptides(a, b []string) int {
+
+	var n, i, j int
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			n++
+			i++
+			j++
+		} else if a[i] < b[j] {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return n
+}
+
+// sortMembers orders the members by protein name
+func sortMembers(m []GroupMember) {
+	sort.Slice(m, func(i, j int) bool {
+		return m[i].Protein < m[j].Protein
+	})
+}
@@ -0,0 +1,59 @@
+package inf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupProteins(t *testing.T) {
+
+	mapping := map[string][]string{
+		"PEPA": {"P1", "P2", "P3"},
+		"PEPB": {"P1", "P2", "P3", "P6"},
+		"PEPC": {"P1", "P2", "P4"},
+		"PEPD": {"P4", "P5", "P6"},
+		"PEPE": {"P5"},
+		"PEPX": {"P7"},
+	}
+
+	type member struct {
+		protein string
+		label   string
+	}
+
+	tests := []struct {
+		leading   string
+		component int
+		members   []member
+	}{
+		{"P1", 1, []member{{"P1", LeadingProtein}, {"P2", IndistinguishableProtein}, {"P3", SubsetProtein}, {"P4", SubsumableProtein}, {"P6", SubsumableProtein}}},
+		{"P5", 1, []member{{"P5", LeadingProtein}}},
+		{"P7", 2, []member{{"P7", LeadingProtein}}},
+	}
+
+	groups := GroupProteins(mapping)
+
+	if len(groups) != len(tests) {
+		t.Fatalf("GroupProteins() = %d groups, want %d", len(groups), len(tests))
+	}
+
+	for n, tt := range tests {
+		t.Run(tt.leading, func(t *testing.T) {
+
+			g := groups[n]
+
+			if g.Number != n+1 || g.Leading != tt.leading || g.Component != tt.component {
+				t.Errorf("group = %d %s %d, want %d %s %d", g.Number, g.Leading, g.Component, n+1, tt.leading, tt.component)
+			}
+
+			var got []member
+			for _, i := range g.Members {
+				got = append(got, member{i.Protein, i.Label})
+			}
+
+			if !reflect.DeepEqual(got, tt.members) {
+				t.Errorf("Members = %v, want %v", got, tt.members)
+			}
+		})
+	}
+}
@@ -2,10 +2,9 @@ package inf
 
 import (
 	"fmt"
-	"regexp"
 	"sort"
-	"strings"
 
+	"philosopher/lib/bio"
 	"philosopher/lib/dat"
 	"philosopher/lib/id"
 	"philosopher/lib/uti"
@@ -225,31 +224,15 @@ func calculateProteinCoverage(proteinPepSeqMap map[string][]string, db dat.Base)
 	var coverage = make(map[string]float64)
 	var protSeq = make(map[string]string)
 
+	// records follow the FASTA order, the first entry of a repeated accession is the one used
 	for _, i := range db.Records {
-		protSeq[i.PartHeader] = i.Sequence
+		if _, ok := protSeq[i.PartHeader]; !ok {
+			protSeq[i.PartHeader] = i.Sequence
+		}
 	}
 
 	for k, v := range proteinPepSeqMap {
-
-		var peptides = make(map[string]uint8)
-		for _, i := range v {
-			peptides[i]++
-		}
-
-		CoveredSequence := protSeq[k]
-		for pep := range peptides {
-
-			re := regexp.MustCompile(pep)
-			loc := re.FindStringIndex(protSeq[k])
-
-			if len(loc) > 0 {
-				CoveredSequence = strings.ReplaceAll(protSeq[k], protSeq[k][loc[0]:loc[1]], strings.Repeat("X", len(pep)))
-			}
-		}
-
-		cov := regexp.MustCompile("X")
-		matches := cov.FindAllStringIndex(CoveredSequence, -1)
-		coverage[k] = uti.Round(float64(len(matches))/float64(len(CoveredSequence))*100, 5, 2)
+		coverage[k] = uti.Round(bio.Coverage(protSeq[k], v), 5, 2)
 	}
 
 	return coverage
@@ -49,6 +49,7 @@ type Data struct {
 	BioQuant       BioQuant
 	Abacus         Abacus
 	Report         Report
+	Library        Library
 	TMTIntegrator  TMTIntegrator
 	Index          Index
 	Pipeline       Pipeline
@@ -71,18 +72,37 @@ type Idconvert struct {
 
 // Database options and parameters
 type Database struct {
-	ID        string `yaml:"id"`
-	Annot     string `yaml:"protein_database"`
-	Enz       string `yaml:"enzyme"`
-	Tag       string `yaml:"decoy_tag"`
-	Add       string `yaml:"add"`
-	Custom    string `yaml:"custom"`
-	TimeStamp string `yaml:"timestamp"`
-	Crap      bool   `yaml:"contam"`
-	CrapTag   bool   `yaml:"contaminant_tag"`
-	Rev       bool   `yaml:"reviewed"`
-	Iso       bool   `yaml:"isoform"`
-	NoD       bool   `yaml:"nodecoys"`
+	ID           string   `yaml:"id"`
+	Annot        string   `yaml:"protein_database"`
+	Enz          string   `yaml:"enzyme"`
+	Tag          string   `yaml:"decoy_tag"`
+	Add          string   `yaml:"add"`
+	Variant      string   `yaml:"variant"`
+	Novel        string   `yaml:"novel"`
+	Entrapment   string   `yaml:"entrapment"`
+	Template     string   `yaml:"header_template"`
+	Templates    []string `yaml:"header_templates"`
+	Contaminants []string `yaml:"contaminant_accessions"`
+	Custom       string   `yaml:"custom"`
+	Local        string   `yaml:"local"`
+	URL          string   `yaml:"uniprot_url"`
+	Release      string   `yaml:"uniprot_release"`
+	TimeStamp    string   `yaml:"timestamp"`
+	DecoyMethod  string   `yaml:"decoy_method"`
+	DecoySeed    int64    `yaml:"decoy_seed"`
+	Specificity  string   `yaml:"specificity"`
+	Missed       int      `yaml:"missed_cleavages"`
+	MinLen       int      `yaml:"min_length"`
+	MaxLen       int      `yaml:"max_length"`
+	Digest       bool     `yaml:"digest"`
+	DryRun       bool     `yaml:"dry_run"`
+	Peptides     bool     `yaml:"peptides"`
+	ContamLib    string   `yaml:"contam_library"`
+	Crap         bool     `yaml:"contam"`
+	CrapTag      bool     `yaml:"contaminant_tag"`
+	Rev          bool     `yaml:"reviewed"`
+	Iso          bool     `yaml:"isoform"`
+	NoD          bool     `yaml:"nodecoys"`
 }
 
 // Comet options and parameters
@@ -347,9 +367,15 @@ type Filter struct {
 	Tag       string  `yaml:"tag"`
 	Mods      string  `yaml:"mods"`
 	RazorBin  string  `yaml:"razorbin"`
+	RazorOut  string  `yaml:"razorOut"`
+	Enzyme    string  `yaml:"enzyme"`
+	Score     string  `yaml:"score"`
+	Direction string  `yaml:"scoreDirection"`
+	ProtScore string  `yaml:"proteinScore"`
 	PsmFDR    float64 `yaml:"psmFDR"`
 	PepFDR    float64 `yaml:"peptideFDR"`
 	IonFDR    float64 `yaml:"ionFDR"`
+	FormFDR   float64 `yaml:"peptidoformFDR"`
 	PtFDR     float64 `yaml:"proteinFDR"`
 	ProtProb  float64 `yaml:"proteinProbability"`
 	PepProb   float64 `yaml:"peptideProbability"`
@@ -360,6 +386,8 @@ type Filter struct {
 	Seq       bool    `yaml:"sequential"`
 	TwoD      bool    `yaml:"two-dimensional"`
 	Mapmods   bool    `yaml:"mapMods"`
+	Global    bool    `yaml:"global"`
+	Gene      bool    `yaml:"gene"`
 	Inference bool
 }
 
@@ -373,6 +401,8 @@ type Quantify struct {
 	Plex       string  `yaml:"plex"`
 	ChanNorm   string  `yaml:"chanNorm"`
 	Annot      string  `yaml:"annotation"`
+	Mod        string  `yaml:"modification"`
+	UniqueBy   string  `yaml:"uniqueness"`
 	Level      int     `yaml:"level"`
 	RTWin      float64 `yaml:"retentionTimeWindow"`
 	PTWin      float64 `yaml:"peakTimeWindow"`
@@ -392,16 +422,19 @@ type Quantify struct {
 // Abacus options ad parameters
 type Abacus struct {
 	Tag      string  `yaml:"tag"`
+	UniqueBy string  `yaml:"uniqueness"`
 	ProtProb float64 `yaml:"proteinProbability"`
 	PepProb  float64 `yaml:"peptideProbability"`
 	Peptide  bool    `yaml:"peptide"`
 	Protein  bool    `yaml:"protein"`
+	Gene     bool    `yaml:"gene"`
 	Razor    bool    `yaml:"razor"`
 	Picked   bool    `yaml:"picked"`
 	Labels   bool    `yaml:"labels"`
 	Unique   bool    `yaml:"uniqueOnly"`
 	Reprint  bool    `yaml:"reprint"`
 	Full     bool    `yaml:"full"`
+	Sites    bool    `yaml:"sites"`
 }
 
 // BioQuant options and parameters
@@ -410,12 +443,24 @@ type BioQuant struct {
 	Level float64 `yaml:"level"`
 }
 
+// Library options and parameters
+type Library struct {
+	Dir     string  `yaml:"dir"`
+	IRT     string  `yaml:"irt"`
+	Tol     float64 `yaml:"tolerance"`
+	TopN    int     `yaml:"topN"`
+	MinFrag int     `yaml:"minFragments"`
+}
+
 // Report options and parameters
 type Report struct {
 	Decoys  bool `yaml:"withDecoys"`
 	MSstats bool `yaml:"msstats"`
 	MZID    bool `yaml:"mzID"`
 	IonMob  bool `yaml:"ionmobility"`
+	Sites   bool `yaml:"sites"`
+	QC      bool `yaml:"qc"`
+	CovMap  bool `yaml:"coverage"`
 }
 
 // TMTIntegrator options and parameters
@@ -505,8 +505,14 @@ func CombinedProteinList(meta met.Data, p Directives, dir string, data []string)
 	p.Abacus.Picked = p.Filter.Picked
 	p.Abacus.Razor = p.Filter.Razor
 
+	// the combined file has no filtered peptide list, so the scores use the inference probabilities
+	proScore, e1 := fil.NewProteinScore(p.Filter.ProtScore, nil)
+	if e1 != nil {
+		msg.Custom(e1, "fatal")
+	}
+
 	protXML := fil.ReadProtXMLInput("combined.prot.xml", p.DatabaseSearch.DecoyTag, p.Filter.Weight)
-	proBin := fil.ProcessProteinIdentifications(protXML, p.Filter.PtFDR, p.Filter.PepFDR, p.Filter.ProtProb, p.Abacus.Picked, p.Abacus.Razor, true, p.DatabaseSearch.DecoyTag)
+	proBin := fil.ProcessProteinIdentifications(protXML, p.Filter.PtFDR, p.Filter.PepFDR, p.Filter.ProtProb, p.Abacus.Picked, p.Abacus.Razor, true, p.DatabaseSearch.DecoyTag, proScore)
 
 	for _, i := range data {
 		dest := fmt.Sprintf("%s%s.meta%spro.bin", i, string(filepath.Separator), string(filepath.Separator))
@@ -592,8 +598,9 @@ func LabelQuant(meta met.Data, p Directives, dir string, data []string) met.Data
 		meta.Quantify.Brand = p.LabelQuant.Brand
 		meta.Quantify.Pex = fmt.Sprintf("%s%sinteract.pep.xml", dsAbs, string(filepath.Separator))
 		meta.Quantify.Tag = "rev_"
+		meta.Quantify.Mod = qua.MapmodsModification(meta.Quantify.Mod, p.Filter.Mapmods)
 
-		meta.Quantify = qua.RunIsobaricLabelQuantification(meta.Quantify, meta.Filter.Mapmods)
+		meta.Quantify = qua.RunIsobaricLabelQuantification(meta.Quantify)
 
 		meta.Serialize()
 
@@ -689,6 +696,11 @@ func Filter(meta met.Data, p Directives, dir string, data []string) met.Data {
 		os.Chdir(dir)
 	}
 
+	if p.Steps.FDRFiltering == "yes" && p.Filter.Global {
+		logrus.Info("Executing the global FDR filter")
+		fil.GlobalFilter(meta.Filter, meta.Temp, dir, data)
+	}
+
 	return meta
 }
 
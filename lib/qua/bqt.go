@@ -14,6 +14,7 @@ import (
 
 	"philosopher/lib/msg"
 
+	"philosopher/lib/cla"
 	"philosopher/lib/dat"
 	"philosopher/lib/ext/cdhit"
 	"philosopher/lib/rep"
@@ -62,7 +63,7 @@ func execute(level float64) (string, string) {
 }
 
 // ParseClusterFile ...
-func parseClusterFile(cls, database string) List {
+func parseClusterFile(cls, database, decoyTag string) List {
 
 	var list List
 	var clustermap = make(map[int][]string)
@@ -105,7 +106,7 @@ func parseClusterFile(cls, database string) List {
 
 		} else {
 
-			if strings.Contains(scanner.Text(), "*") && !strings.Contains(scanner.Text(), "rev_") {
+			if strings.Contains(scanner.Text(), "*") && !isDecoyMember(scanner.Text(), decoyTag) {
 				centroid := strings.Split(scanner.Text(), "|")
 				//centroid := reseq.FindStringSubmatch(scanner.Text())
 				if len(centroid) < 2 {
@@ -142,6 +143,19 @@ func parseClusterFile(cls, database string) List {
 	return list
 }
 
+// isDecoyMember checks the sequence name of a cluster member line, like 0	120aa, >sp|P00001|A_HUMAN... *
+func isDecoyMember(line, decoyTag string) bool {
+
+	i := strings.Index(line, ">")
+	if i == -1 {
+		return false
+	}
+
+	name := strings.SplitN(line[i+1:], "...", 2)[0]
+
+	return cla.Matcher(decoyTag).IsDecoy(name)
+}
+
 // MapProtXML2Clusters ...
 func mapProtXML2Clusters(clusters List) List {
 
@@ -476,7 +476,7 @@ func correctUnlabelledSpectra(evi rep.Evidence) rep.Evidence {
 }
 
 // rollUpPeptides gathers PSM info and filters them before summing the instensities to the peptide level
-func rollUpPeptides(evi rep.Evidence, spectrumMap map[string]iso.Labels, phosphoSpectrumMap map[string]iso.Labels) rep.Evidence {
+func rollUpPeptides(evi rep.Evidence, spectrumMap map[string]iso.Labels, modSpectrumMap map[string]iso.Labels) rep.Evidence {
 
 	for j := range evi.Peptides {
 		for k := range evi.Peptides[j].Spectra {
@@ -580,98 +580,98 @@ func rollUpPeptides(evi rep.Evidence, spectrumMap map[string]iso.Labels, phospho
 				evi.Peptides[j].Labels.Channel18.Intensity += i.Channel18.Intensity
 			}
 
-			i, ok = phosphoSpectrumMap[k]
+			i, ok = modSpectrumMap[k]
 			if ok {
 
-				evi.Peptides[j].PhosphoLabels.Channel1.Name = i.Channel1.Name
-				evi.Peptides[j].PhosphoLabels.Channel1.CustomName = i.Channel1.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel1.Mz = i.Channel1.Mz
-				evi.Peptides[j].PhosphoLabels.Channel1.Intensity += i.Channel1.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel2.Name = i.Channel2.Name
-				evi.Peptides[j].PhosphoLabels.Channel2.CustomName = i.Channel2.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel2.Mz = i.Channel2.Mz
-				evi.Peptides[j].PhosphoLabels.Channel2.Intensity += i.Channel2.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel3.Name = i.Channel3.Name
-				evi.Peptides[j].PhosphoLabels.Channel3.CustomName = i.Channel3.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel3.Mz = i.Channel3.Mz
-				evi.Peptides[j].PhosphoLabels.Channel3.Intensity += i.Channel3.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel4.Name = i.Channel4.Name
-				evi.Peptides[j].PhosphoLabels.Channel4.CustomName = i.Channel4.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel4.Mz = i.Channel4.Mz
-				evi.Peptides[j].PhosphoLabels.Channel4.Intensity += i.Channel4.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel5.Name = i.Channel5.Name
-				evi.Peptides[j].PhosphoLabels.Channel5.CustomName = i.Channel5.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel5.Mz = i.Channel5.Mz
-				evi.Peptides[j].PhosphoLabels.Channel5.Intensity += i.Channel5.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel6.Name = i.Channel6.Name
-				evi.Peptides[j].PhosphoLabels.Channel6.CustomName = i.Channel6.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel6.Mz = i.Channel6.Mz
-				evi.Peptides[j].PhosphoLabels.Channel6.Intensity += i.Channel6.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel7.Name = i.Channel7.Name
-				evi.Peptides[j].PhosphoLabels.Channel7.CustomName = i.Channel7.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel7.Mz = i.Channel7.Mz
-				evi.Peptides[j].PhosphoLabels.Channel7.Intensity += i.Channel7.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel8.Name = i.Channel8.Name
-				evi.Peptides[j].PhosphoLabels.Channel8.CustomName = i.Channel8.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel8.Mz = i.Channel8.Mz
-				evi.Peptides[j].PhosphoLabels.Channel8.Intensity += i.Channel8.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel9.Name = i.Channel9.Name
-				evi.Peptides[j].PhosphoLabels.Channel9.CustomName = i.Channel9.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel9.Mz = i.Channel9.Mz
-				evi.Peptides[j].PhosphoLabels.Channel9.Intensity += i.Channel9.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel10.Name = i.Channel10.Name
-				evi.Peptides[j].PhosphoLabels.Channel10.CustomName = i.Channel10.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel10.Mz = i.Channel10.Mz
-				evi.Peptides[j].PhosphoLabels.Channel10.Intensity += i.Channel10.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel11.Name = i.Channel11.Name
-				evi.Peptides[j].PhosphoLabels.Channel11.CustomName = i.Channel11.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel11.Mz = i.Channel11.Mz
-				evi.Peptides[j].PhosphoLabels.Channel11.Intensity += i.Channel11.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel12.Name = i.Channel12.Name
-				evi.Peptides[j].PhosphoLabels.Channel12.CustomName = i.Channel12.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel12.Mz = i.Channel12.Mz
-				evi.Peptides[j].PhosphoLabels.Channel12.Intensity += i.Channel12.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel13.Name = i.Channel13.Name
-				evi.Peptides[j].PhosphoLabels.Channel13.CustomName = i.Channel13.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel13.Mz = i.Channel13.Mz
-				evi.Peptides[j].PhosphoLabels.Channel13.Intensity += i.Channel13.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel14.Name = i.Channel14.Name
-				evi.Peptides[j].PhosphoLabels.Channel14.CustomName = i.Channel14.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel14.Mz = i.Channel14.Mz
-				evi.Peptides[j].PhosphoLabels.Channel14.Intensity += i.Channel14.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel15.Name = i.Channel15.Name
-				evi.Peptides[j].PhosphoLabels.Channel15.CustomName = i.Channel15.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel15.Mz = i.Channel15.Mz
-				evi.Peptides[j].PhosphoLabels.Channel15.Intensity += i.Channel15.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel16.Name = i.Channel16.Name
-				evi.Peptides[j].PhosphoLabels.Channel16.CustomName = i.Channel16.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel16.Mz = i.Channel16.Mz
-				evi.Peptides[j].PhosphoLabels.Channel16.Intensity += i.Channel16.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel17.Name = i.Channel17.Name
-				evi.Peptides[j].PhosphoLabels.Channel17.CustomName = i.Channel17.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel17.Mz = i.Channel17.Mz
-				evi.Peptides[j].PhosphoLabels.Channel17.Intensity += i.Channel17.Intensity
-
-				evi.Peptides[j].PhosphoLabels.Channel18.Name = i.Channel18.Name
-				evi.Peptides[j].PhosphoLabels.Channel18.CustomName = i.Channel18.CustomName
-				evi.Peptides[j].PhosphoLabels.Channel18.Mz = i.Channel18.Mz
-				evi.Peptides[j].PhosphoLabels.Channel18.Intensity += i.Channel18.Intensity
+				evi.Peptides[j].ModLabels.Channel1.Name = i.Channel1.Name
+				evi.Peptides[j].ModLabels.Channel1.CustomName = i.Channel1.CustomName
+				evi.Peptides[j].ModLabels.Channel1.Mz = i.Channel1.Mz
+				evi.Peptides[j].ModLabels.Channel1.Intensity += i.Channel1.Intensity
+
+				evi.Peptides[j].ModLabels.Channel2.Name = i.Channel2.Name
+				evi.Peptides[j].ModLabels.Channel2.CustomName = i.Channel2.CustomName
+				evi.Peptides[j].ModLabels.Channel2.Mz = i.Channel2.Mz
+				evi.Peptides[j].ModLabels.Channel2.Intensity += i.Channel2.Intensity
+
+				evi.Peptides[j].ModLabels.Channel3.Name = i.Channel3.Name
+				evi.Peptides[j].ModLabels.Channel3.CustomName = i.Channel3.CustomName
+				evi.Peptides[j].ModLabels.Channel3.Mz = i.Channel3.Mz
+				evi.Peptides[j].ModLabels.Channel3.Intensity += i.Channel3.Intensity
+
+				evi.Peptides[j].ModLabels.Channel4.Name = i.Channel4.Name
+				evi.Peptides[j].ModLabels.Channel4.CustomName = i.Channel4.CustomName
+				evi.Peptides[j].ModLabels.Channel4.Mz = i.Channel4.Mz
+				evi.Peptides[j].ModLabels.Channel4.Intensity += i.Channel4.Intensity
+
+				evi.Peptides[j].ModLabels.Channel5.Name = i.Channel5.Name
+				evi.Peptides[j].ModLabels.Channel5.CustomName = i.Channel5.CustomName
+				evi.Peptides[j].ModLabels.Channel5.Mz = i.Channel5.Mz
+				evi.Peptides[j].ModLabels.Channel5.Intensity += i.Channel5.Intensity
+
+				evi.Peptides[j].ModLabels.Channel6.Name = i.Channel6.Name
+				evi.Peptides[j].ModLabels.Channel6.CustomName = i.Channel6.CustomName
+				evi.Peptides[j].ModLabels.Channel6.Mz = i.Channel6.Mz
+				evi.Peptides[j].ModLabels.Channel6.Intensity += i.Channel6.Intensity
+
+				evi.Peptides[j].ModLabels.Channel7.Name = i.Channel7.Name
+				evi.Peptides[j].ModLabels.Channel7.CustomName = i.Channel7.CustomName
+				evi.Peptides[j].ModLabels.Channel7.Mz = i.Channel7.Mz
+				evi.Peptides[j].ModLabels.Channel7.Intensity += i.Channel7.Intensity
+
+				evi.Peptides[j].ModLabels.Channel8.Name = i.Channel8.Name
+				evi.Peptides[j].ModLabels.Channel8.CustomName = i.Channel8.CustomName
+				evi.Peptides[j].ModLabels.Channel8.Mz = i.Channel8.Mz
+				evi.Peptides[j].ModLabels.Channel8.Intensity += i.Channel8.Intensity
+
+				evi.Peptides[j].ModLabels.Channel9.Name = i.Channel9.Name
+				evi.Peptides[j].ModLabels.Channel9.CustomName = i.Channel9.CustomName
+				evi.Peptides[j].ModLabels.Channel9.Mz = i.Channel9.Mz
+				evi.Peptides[j].ModLabels.Channel9.Intensity += i.Channel9.Intensity
+
+				evi.Peptides[j].ModLabels.Channel10.Name = i.Channel10.Name
+				evi.Peptides[j].ModLabels.Channel10.CustomName = i.Channel10.CustomName
+				evi.Peptides[j].ModLabels.Channel10.Mz = i.Channel10.Mz
+				evi.Peptides[j].ModLabels.Channel10.Intensity += i.Channel10.Intensity
+
+				evi.Peptides[j].ModLabels.Channel11.Name = i.Channel11.Name
+				evi.Peptides[j].ModLabels.Channel11.CustomName = i.Channel11.CustomName
+				evi.Peptides[j].ModLabels.Channel11.Mz = i.Channel11.Mz
+				evi.Peptides[j].ModLabels.Channel11.Intensity += i.Channel11.Intensity
+
+				evi.Peptides[j].ModLabels.Channel12.Name = i.Channel12.Name
+				evi.Peptides[j].ModLabels.Channel12.CustomName = i.Channel12.CustomName
+				evi.Peptides[j].ModLabels.Channel12.Mz = i.Channel12.Mz
+				evi.Peptides[j].ModLabels.Channel12.Intensity += i.Channel12.Intensity
+
+				evi.Peptides[j].ModLabels.Channel13.Name = i.Channel13.Name
+				evi.Peptides[j].ModLabels.Channel13.CustomName = i.Channel13.CustomName
+				evi.Peptides[j].ModLabels.Channel13.Mz = i.Channel13.Mz
+				evi.Peptides[j].ModLabels.Channel13.Intensity += i.Channel13.Intensity
+
+				evi.Peptides[j].ModLabels.Channel14.Name = i.Channel14.Name
+				evi.Peptides[j].ModLabels.Channel14.CustomName = i.Channel14.CustomName
+				evi.Peptides[j].ModLabels.Channel14.Mz = i.Channel14.Mz
+				evi.Peptides[j].ModLabels.Channel14.Intensity += i.Channel14.Intensity
+
+				evi.Peptides[j].ModLabels.Channel15.Name = i.Channel15.Name
+				evi.Peptides[j].ModLabels.Channel15.CustomName = i.Channel15.CustomName
+				evi.Peptides[j].ModLabels.Channel15.Mz = i.Channel15.Mz
+				evi.Peptides[j].ModLabels.Channel15.Intensity += i.Channel15.Intensity
+
+				evi.Peptides[j].ModLabels.Channel16.Name = i.Channel16.Name
+				evi.Peptides[j].ModLabels.Channel16.CustomName = i.Channel16.CustomName
+				evi.Peptides[j].ModLabels.Channel16.Mz = i.Channel16.Mz
+				evi.Peptides[j].ModLabels.Channel16.Intensity += i.Channel16.Intensity
+
+				evi.Peptides[j].ModLabels.Channel17.Name = i.Channel17.Name
+				evi.Peptides[j].ModLabels.Channel17.CustomName = i.Channel17.CustomName
+				evi.Peptides[j].ModLabels.Channel17.Mz = i.Channel17.Mz
+				evi.Peptides[j].ModLabels.Channel17.Intensity += i.Channel17.Intensity
+
+				evi.Peptides[j].ModLabels.Channel18.Name = i.Channel18.Name
+				evi.Peptides[j].ModLabels.Channel18.CustomName = i.Channel18.CustomName
+				evi.Peptides[j].ModLabels.Channel18.Mz = i.Channel18.Mz
+				evi.Peptides[j].ModLabels.Channel18.Intensity += i.Channel18.Intensity
 			}
 
 		}
@@ -681,7 +681,7 @@ func rollUpPeptides(evi rep.Evidence, spectrumMap map[string]iso.Labels, phospho
 }
 
 // rollUpPeptideIons gathers PSM info and filters them before summing the instensities to the peptide ION level
-func rollUpPeptideIons(evi rep.Evidence, spectrumMap map[string]iso.Labels, phosphoSpectrumMap map[string]iso.Labels) rep.Evidence {
+func rollUpPeptideIons(evi rep.Evidence, spectrumMap map[string]iso.Labels, modSpectrumMap map[string]iso.Labels) rep.Evidence {
 
 	for j := range evi.Ions {
 		for k := range evi.Ions[j].Spectra {
@@ -780,98 +780,98 @@ func rollUpPeptideIons(evi rep.Evidence, spectrumMap map[string]iso.Labels, phos
 				evi.Ions[j].Labels.Channel18.Intensity += i.Channel18.Intensity
 			}
 
-			i, ok = phosphoSpectrumMap[k]
+			i, ok = modSpectrumMap[k]
 			if ok {
 
-				evi.Ions[j].PhosphoLabels.Channel1.Name = i.Channel1.Name
-				evi.Ions[j].PhosphoLabels.Channel1.CustomName = i.Channel1.CustomName
-				evi.Ions[j].PhosphoLabels.Channel1.Mz = i.Channel1.Mz
-				evi.Ions[j].PhosphoLabels.Channel1.Intensity += i.Channel1.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel2.Name = i.Channel2.Name
-				evi.Ions[j].PhosphoLabels.Channel2.CustomName = i.Channel2.CustomName
-				evi.Ions[j].PhosphoLabels.Channel2.Mz = i.Channel2.Mz
-				evi.Ions[j].PhosphoLabels.Channel2.Intensity += i.Channel2.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel3.Name = i.Channel3.Name
-				evi.Ions[j].PhosphoLabels.Channel3.CustomName = i.Channel3.CustomName
-				evi.Ions[j].PhosphoLabels.Channel3.Mz = i.Channel3.Mz
-				evi.Ions[j].PhosphoLabels.Channel3.Intensity += i.Channel3.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel4.Name = i.Channel4.Name
-				evi.Ions[j].PhosphoLabels.Channel4.CustomName = i.Channel4.CustomName
-				evi.Ions[j].PhosphoLabels.Channel4.Mz = i.Channel4.Mz
-				evi.Ions[j].PhosphoLabels.Channel4.Intensity += i.Channel4.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel5.Name = i.Channel5.Name
-				evi.Ions[j].PhosphoLabels.Channel5.CustomName = i.Channel5.CustomName
-				evi.Ions[j].PhosphoLabels.Channel5.Mz = i.Channel5.Mz
-				evi.Ions[j].PhosphoLabels.Channel5.Intensity += i.Channel5.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel6.Name = i.Channel6.Name
-				evi.Ions[j].PhosphoLabels.Channel6.CustomName = i.Channel6.CustomName
-				evi.Ions[j].PhosphoLabels.Channel6.Mz = i.Channel6.Mz
-				evi.Ions[j].PhosphoLabels.Channel6.Intensity += i.Channel6.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel7.Name = i.Channel7.Name
-				evi.Ions[j].PhosphoLabels.Channel7.CustomName = i.Channel7.CustomName
-				evi.Ions[j].PhosphoLabels.Channel7.Mz = i.Channel7.Mz
-				evi.Ions[j].PhosphoLabels.Channel7.Intensity += i.Channel7.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel8.Name = i.Channel8.Name
-				evi.Ions[j].PhosphoLabels.Channel8.CustomName = i.Channel8.CustomName
-				evi.Ions[j].PhosphoLabels.Channel8.Mz = i.Channel8.Mz
-				evi.Ions[j].PhosphoLabels.Channel8.Intensity += i.Channel8.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel9.Name = i.Channel9.Name
-				evi.Ions[j].PhosphoLabels.Channel9.CustomName = i.Channel9.CustomName
-				evi.Ions[j].PhosphoLabels.Channel9.Mz = i.Channel9.Mz
-				evi.Ions[j].PhosphoLabels.Channel9.Intensity += i.Channel9.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel10.Name = i.Channel10.Name
-				evi.Ions[j].PhosphoLabels.Channel10.CustomName = i.Channel10.CustomName
-				evi.Ions[j].PhosphoLabels.Channel10.Mz = i.Channel10.Mz
-				evi.Ions[j].PhosphoLabels.Channel10.Intensity += i.Channel10.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel11.Name = i.Channel11.Name
-				evi.Ions[j].PhosphoLabels.Channel11.CustomName = i.Channel11.CustomName
-				evi.Ions[j].PhosphoLabels.Channel11.Mz = i.Channel11.Mz
-				evi.Ions[j].PhosphoLabels.Channel11.Intensity += i.Channel11.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel12.Name = i.Channel12.Name
-				evi.Ions[j].PhosphoLabels.Channel12.CustomName = i.Channel12.CustomName
-				evi.Ions[j].PhosphoLabels.Channel12.Mz = i.Channel12.Mz
-				evi.Ions[j].PhosphoLabels.Channel12.Intensity += i.Channel12.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel13.Name = i.Channel13.Name
-				evi.Ions[j].PhosphoLabels.Channel13.CustomName = i.Channel13.CustomName
-				evi.Ions[j].PhosphoLabels.Channel13.Mz = i.Channel13.Mz
-				evi.Ions[j].PhosphoLabels.Channel13.Intensity += i.Channel13.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel14.Name = i.Channel14.Name
-				evi.Ions[j].PhosphoLabels.Channel14.CustomName = i.Channel14.CustomName
-				evi.Ions[j].PhosphoLabels.Channel14.Mz = i.Channel14.Mz
-				evi.Ions[j].PhosphoLabels.Channel14.Intensity += i.Channel14.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel15.Name = i.Channel15.Name
-				evi.Ions[j].PhosphoLabels.Channel15.CustomName = i.Channel15.CustomName
-				evi.Ions[j].PhosphoLabels.Channel15.Mz = i.Channel15.Mz
-				evi.Ions[j].PhosphoLabels.Channel15.Intensity += i.Channel15.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel16.Name = i.Channel16.Name
-				evi.Ions[j].PhosphoLabels.Channel16.CustomName = i.Channel16.CustomName
-				evi.Ions[j].PhosphoLabels.Channel16.Mz = i.Channel16.Mz
-				evi.Ions[j].PhosphoLabels.Channel16.Intensity += i.Channel16.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel17.Name = i.Channel17.Name
-				evi.Ions[j].PhosphoLabels.Channel17.CustomName = i.Channel17.CustomName
-				evi.Ions[j].PhosphoLabels.Channel17.Mz = i.Channel17.Mz
-				evi.Ions[j].PhosphoLabels.Channel17.Intensity += i.Channel17.Intensity
-
-				evi.Ions[j].PhosphoLabels.Channel18.Name = i.Channel18.Name
-				evi.Ions[j].PhosphoLabels.Channel18.CustomName = i.Channel18.CustomName
-				evi.Ions[j].PhosphoLabels.Channel18.Mz = i.Channel18.Mz
-				evi.Ions[j].PhosphoLabels.Channel18.Intensity += i.Channel18.Intensity
+				evi.Ions[j].ModLabels.Channel1.Name = i.Channel1.Name
+				evi.Ions[j].ModLabels.Channel1.CustomName = i.Channel1.CustomName
+				evi.Ions[j].ModLabels.Channel1.Mz = i.Channel1.Mz
+				evi.Ions[j].ModLabels.Channel1.Intensity += i.Channel1.Intensity
+
+				evi.Ions[j].ModLabels.Channel2.Name = i.Channel2.Name
+				evi.Ions[j].ModLabels.Channel2.CustomName = i.Channel2.CustomName
+				evi.Ions[j].ModLabels.Channel2.Mz = i.Channel2.Mz
+				evi.Ions[j].ModLabels.Channel2.Intensity += i.Channel2.Intensity
+
+				evi.Ions[j].ModLabels.Channel3.Name = i.Channel3.Name
+				evi.Ions[j].ModLabels.Channel3.CustomName = i.Channel3.CustomName
+				evi.Ions[j].ModLabels.Channel3.Mz = i.Channel3.Mz
+				evi.Ions[j].ModLabels.Channel3.Intensity += i.Channel3.Intensity
+
+				evi.Ions[j].ModLabels.Channel4.Name = i.Channel4.Name
+				evi.Ions[j].ModLabels.Channel4.CustomName = i.Channel4.CustomName
+				evi.Ions[j].ModLabels.Channel4.Mz = i.Channel4.Mz
+				evi.Ions[j].ModLabels.Channel4.Intensity += i.Channel4.Intensity
+
+				evi.Ions[j].ModLabels.Channel5.Name = i.Channel5.Name
+				evi.Ions[j].ModLabels.Channel5.CustomName = i.Channel5.CustomName
+				evi.Ions[j].ModLabels.Channel5.Mz = i.Channel5.Mz
+				evi.Ions[j].ModLabels.Channel5.Intensity += i.Channel5.Intensity
+
+				evi.Ions[j].ModLabels.Channel6.Name = i.Channel6.Name
+				evi.Ions[j].ModLabels.Channel6.CustomName = i.Channel6.CustomName
+				evi.Ions[j].ModLabels.Channel6.Mz = i.Channel6.Mz
+				evi.Ions[j].ModLabels.Channel6.Intensity += i.Channel6.Intensity
+
+				evi.Ions[j].ModLabels.Channel7.Name = i.Channel7.Name
+				evi.Ions[j].ModLabels.Channel7.CustomName = i.Channel7.CustomName
+				evi.Ions[j].ModLabels.Channel7.Mz = i.Channel7.Mz
+				evi.Ions[j].ModLabels.Channel7.Intensity += i.Channel7.Intensity
+
+				evi.Ions[j].ModLabels.Channel8.Name = i.Channel8.Name
+				evi.Ions[j].ModLabels.Channel8.CustomName = i.Channel8.CustomName
+				evi.Ions[j].ModLabels.Channel8.Mz = i.Channel8.Mz
+				evi.Ions[j].ModLabels.Channel8.Intensity += i.Channel8.Intensity
+
+				evi.Ions[j].ModLabels.Channel9.Name = i.Channel9.Name
+				evi.Ions[j].ModLabels.Channel9.CustomName = i.Channel9.CustomName
+				evi.Ions[j].ModLabels.Channel9.Mz = i.Channel9.Mz
+				evi.Ions[j].ModLabels.Channel9.Intensity += i.Channel9.Intensity
+
+				evi.Ions[j].ModLabels.Channel10.Name = i.Channel10.Name
+				evi.Ions[j].ModLabels.Channel10.CustomName = i.Channel10.CustomName
+				evi.Ions[j].ModLabels.Channel10.Mz = i.Channel10.Mz
+				evi.Ions[j].ModLabels.Channel10.Intensity += i.Channel10.Intensity
+
+				evi.Ions[j].ModLabels.Channel11.Name = i.Channel11.Name
+				evi.Ions[j].ModLabels.Channel11.CustomName = i.Channel11.CustomName
+				evi.Ions[j].ModLabels.Channel11.Mz = i.Channel11.Mz
+				evi.Ions[j].ModLabels.Channel11.Intensity += i.Channel11.Intensity
+
+				evi.Ions[j].ModLabels.Channel12.Name = i.Channel12.Name
+				evi.Ions[j].ModLabels.Channel12.CustomName = i.Channel12.CustomName
+				evi.Ions[j].ModLabels.Channel12.Mz = i.Channel12.Mz
+				evi.Ions[j].ModLabels.Channel12.Intensity += i.Channel12.Intensity
+
+				evi.Ions[j].ModLabels.Channel13.Name = i.Channel13.Name
+				evi.Ions[j].ModLabels.Channel13.CustomName = i.Channel13.CustomName
+				evi.Ions[j].ModLabels.Channel13.Mz = i.Channel13.Mz
+				evi.Ions[j].ModLabels.Channel13.Intensity += i.Channel13.Intensity
+
+				evi.Ions[j].ModLabels.Channel14.Name = i.Channel14.Name
+				evi.Ions[j].ModLabels.Channel14.CustomName = i.Channel14.CustomName
+				evi.Ions[j].ModLabels.Channel14.Mz = i.Channel14.Mz
+				evi.Ions[j].ModLabels.Channel14.Intensity += i.Channel14.Intensity
+
+				evi.Ions[j].ModLabels.Channel15.Name = i.Channel15.Name
+				evi.Ions[j].ModLabels.Channel15.CustomName = i.Channel15.CustomName
+				evi.Ions[j].ModLabels.Channel15.Mz = i.Channel15.Mz
+				evi.Ions[j].ModLabels.Channel15.Intensity += i.Channel15.Intensity
+
+				evi.Ions[j].ModLabels.Channel16.Name = i.Channel16.Name
+				evi.Ions[j].ModLabels.Channel16.CustomName = i.Channel16.CustomName
+				evi.Ions[j].ModLabels.Channel16.Mz = i.Channel16.Mz
+				evi.Ions[j].ModLabels.Channel16.Intensity += i.Channel16.Intensity
+
+				evi.Ions[j].ModLabels.Channel17.Name = i.Channel17.Name
+				evi.Ions[j].ModLabels.Channel17.CustomName = i.Channel17.CustomName
+				evi.Ions[j].ModLabels.Channel17.Mz = i.Channel17.Mz
+				evi.Ions[j].ModLabels.Channel17.Intensity += i.Channel17.Intensity
+
+				evi.Ions[j].ModLabels.Channel18.Name = i.Channel18.Name
+				evi.Ions[j].ModLabels.Channel18.CustomName = i.Channel18.CustomName
+				evi.Ions[j].ModLabels.Channel18.Mz = i.Channel18.Mz
+				evi.Ions[j].ModLabels.Channel18.Intensity += i.Channel18.Intensity
 			}
 
 		}
@@ -880,8 +880,25 @@ func rollUpPeptideIons(evi rep.Evidence, spectrumMap map[string]iso.Labels, phos
 	return evi
 }
 
+// uniqueIonSelector decides which ions are rolled up as unique, the uniqueness from the protein inference
+// is kept unless a set of uniqueness categories is given
+func uniqueIonSelector(evi rep.Evidence, categories map[string]bool) func(rep.IonEvidence) bool {
+
+	if len(categories) == 0 {
+		return func(i rep.IonEvidence) bool { return i.IsUnique }
+	}
+
+	// the ions stored on the proteins are copies made before the uniqueness classification
+	var ions = make(map[string]string)
+	for _, i := range evi.Ions {
+		ions[i.IonForm] = i.Uniqueness
+	}
+
+	return func(i rep.IonEvidence) bool { return categories[ions[i.IonForm]] }
+}
+
 // rollUpProteins gathers PSM info and filters them before summing the instensities to the peptide ION level
-func rollUpProteins(evi rep.Evidence, spectrumMap map[string]iso.Labels, phosphoSpectrumMap map[string]iso.Labels) rep.Evidence {
+func rollUpProteins(evi rep.Evidence, spectrumMap map[string]iso.Labels, modSpectrumMap map[string]iso.Labels, isUnique func(rep.IonEvidence) bool) rep.Evidence {
 
 	for j := range evi.Proteins {
 		for _, k := range evi.Proteins[j].TotalPeptideIons {
@@ -980,7 +997,7 @@ func rollUpProteins(evi rep.Evidence, spectrumMap map[string]iso.Labels, phospho
 					evi.Proteins[j].TotalLabels.Channel18.Intensity += i.Channel18.Intensity
 
 					//if k.IsNondegenerateEvidence {
-					if k.IsUnique {
+					if isUnique(k) {
 						evi.Proteins[j].UniqueLabels.Channel1.Name = i.Channel1.Name
 						evi.Proteins[j].UniqueLabels.Channel1.CustomName = i.Channel1.CustomName
 						evi.Proteins[j].UniqueLabels.Channel1.Mz = i.Channel1.Mz
@@ -1165,281 +1182,281 @@ func rollUpProteins(evi rep.Evidence, spectrumMap map[string]iso.Labels, phospho
 					}
 				}
 
-				i, ok = phosphoSpectrumMap[l]
+				i, ok = modSpectrumMap[l]
 				if ok {
-					evi.Proteins[j].PhosphoTotalLabels.Channel1.Name = i.Channel1.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel1.CustomName = i.Channel1.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel1.Mz = i.Channel1.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel1.Intensity += i.Channel1.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel2.Name = i.Channel2.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel2.CustomName = i.Channel2.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel2.Mz = i.Channel2.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel2.Intensity += i.Channel2.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel3.Name = i.Channel3.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel3.CustomName = i.Channel3.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel3.Mz = i.Channel3.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel3.Intensity += i.Channel3.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel4.Name = i.Channel4.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel4.CustomName = i.Channel4.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel4.Mz = i.Channel4.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel4.Intensity += i.Channel4.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel5.Name = i.Channel5.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel5.CustomName = i.Channel5.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel5.Mz = i.Channel5.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel5.Intensity += i.Channel5.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel6.Name = i.Channel6.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel6.CustomName = i.Channel6.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel6.Mz = i.Channel6.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel6.Intensity += i.Channel6.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel7.Name = i.Channel7.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel7.CustomName = i.Channel7.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel7.Mz = i.Channel7.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel7.Intensity += i.Channel7.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel8.Name = i.Channel8.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel8.CustomName = i.Channel8.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel8.Mz = i.Channel8.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel8.Intensity += i.Channel8.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel9.Name = i.Channel9.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel9.CustomName = i.Channel9.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel9.Mz = i.Channel9.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel9.Intensity += i.Channel9.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel10.Name = i.Channel10.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel10.CustomName = i.Channel10.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel10.Mz = i.Channel10.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel10.Intensity += i.Channel10.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel11.Name = i.Channel11.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel11.CustomName = i.Channel11.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel11.Mz = i.Channel11.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel11.Intensity += i.Channel11.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel12.Name = i.Channel12.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel12.CustomName = i.Channel12.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel12.Mz = i.Channel12.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel12.Intensity += i.Channel12.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel13.Name = i.Channel13.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel13.CustomName = i.Channel13.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel13.Mz = i.Channel13.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel13.Intensity += i.Channel13.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel14.Name = i.Channel14.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel14.CustomName = i.Channel14.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel14.Mz = i.Channel14.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel14.Intensity += i.Channel14.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel15.Name = i.Channel15.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel15.CustomName = i.Channel15.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel15.Mz = i.Channel15.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel15.Intensity += i.Channel15.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel16.Name = i.Channel16.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel16.CustomName = i.Channel16.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel16.Mz = i.Channel16.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel16.Intensity += i.Channel16.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel17.Name = i.Channel17.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel17.CustomName = i.Channel17.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel17.Mz = i.Channel17.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel17.Intensity += i.Channel17.Intensity
-
-					evi.Proteins[j].PhosphoTotalLabels.Channel18.Name = i.Channel18.Name
-					evi.Proteins[j].PhosphoTotalLabels.Channel18.CustomName = i.Channel18.CustomName
-					evi.Proteins[j].PhosphoTotalLabels.Channel18.Mz = i.Channel18.Mz
-					evi.Proteins[j].PhosphoTotalLabels.Channel18.Intensity += i.Channel18.Intensity
+					evi.Proteins[j].ModTotalLabels.Channel1.Name = i.Channel1.Name
+					evi.Proteins[j].ModTotalLabels.Channel1.CustomName = i.Channel1.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel1.Mz = i.Channel1.Mz
+					evi.Proteins[j].ModTotalLabels.Channel1.Intensity += i.Channel1.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel2.Name = i.Channel2.Name
+					evi.Proteins[j].ModTotalLabels.Channel2.CustomName = i.Channel2.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel2.Mz = i.Channel2.Mz
+					evi.Proteins[j].ModTotalLabels.Channel2.Intensity += i.Channel2.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel3.Name = i.Channel3.Name
+					evi.Proteins[j].ModTotalLabels.Channel3.CustomName = i.Channel3.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel3.Mz = i.Channel3.Mz
+					evi.Proteins[j].ModTotalLabels.Channel3.Intensity += i.Channel3.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel4.Name = i.Channel4.Name
+					evi.Proteins[j].ModTotalLabels.Channel4.CustomName = i.Channel4.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel4.Mz = i.Channel4.Mz
+					evi.Proteins[j].ModTotalLabels.Channel4.Intensity += i.Channel4.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel5.Name = i.Channel5.Name
+					evi.Proteins[j].ModTotalLabels.Channel5.CustomName = i.Channel5.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel5.Mz = i.Channel5.Mz
+					evi.Proteins[j].ModTotalLabels.Channel5.Intensity += i.Channel5.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel6.Name = i.Channel6.Name
+					evi.Proteins[j].ModTotalLabels.Channel6.CustomName = i.Channel6.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel6.Mz = i.Channel6.Mz
+					evi.Proteins[j].ModTotalLabels.Channel6.Intensity += i.Channel6.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel7.Name = i.Channel7.Name
+					evi.Proteins[j].ModTotalLabels.Channel7.CustomName = i.Channel7.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel7.Mz = i.Channel7.Mz
+					evi.Proteins[j].ModTotalLabels.Channel7.Intensity += i.Channel7.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel8.Name = i.Channel8.Name
+					evi.Proteins[j].ModTotalLabels.Channel8.CustomName = i.Channel8.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel8.Mz = i.Channel8.Mz
+					evi.Proteins[j].ModTotalLabels.Channel8.Intensity += i.Channel8.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel9.Name = i.Channel9.Name
+					evi.Proteins[j].ModTotalLabels.Channel9.CustomName = i.Channel9.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel9.Mz = i.Channel9.Mz
+					evi.Proteins[j].ModTotalLabels.Channel9.Intensity += i.Channel9.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel10.Name = i.Channel10.Name
+					evi.Proteins[j].ModTotalLabels.Channel10.CustomName = i.Channel10.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel10.Mz = i.Channel10.Mz
+					evi.Proteins[j].ModTotalLabels.Channel10.Intensity += i.Channel10.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel11.Name = i.Channel11.Name
+					evi.Proteins[j].ModTotalLabels.Channel11.CustomName = i.Channel11.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel11.Mz = i.Channel11.Mz
+					evi.Proteins[j].ModTotalLabels.Channel11.Intensity += i.Channel11.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel12.Name = i.Channel12.Name
+					evi.Proteins[j].ModTotalLabels.Channel12.CustomName = i.Channel12.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel12.Mz = i.Channel12.Mz
+					evi.Proteins[j].ModTotalLabels.Channel12.Intensity += i.Channel12.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel13.Name = i.Channel13.Name
+					evi.Proteins[j].ModTotalLabels.Channel13.CustomName = i.Channel13.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel13.Mz = i.Channel13.Mz
+					evi.Proteins[j].ModTotalLabels.Channel13.Intensity += i.Channel13.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel14.Name = i.Channel14.Name
+					evi.Proteins[j].ModTotalLabels.Channel14.CustomName = i.Channel14.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel14.Mz = i.Channel14.Mz
+					evi.Proteins[j].ModTotalLabels.Channel14.Intensity += i.Channel14.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel15.Name = i.Channel15.Name
+					evi.Proteins[j].ModTotalLabels.Channel15.CustomName = i.Channel15.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel15.Mz = i.Channel15.Mz
+					evi.Proteins[j].ModTotalLabels.Channel15.Intensity += i.Channel15.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel16.Name = i.Channel16.Name
+					evi.Proteins[j].ModTotalLabels.Channel16.CustomName = i.Channel16.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel16.Mz = i.Channel16.Mz
+					evi.Proteins[j].ModTotalLabels.Channel16.Intensity += i.Channel16.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel17.Name = i.Channel17.Name
+					evi.Proteins[j].ModTotalLabels.Channel17.CustomName = i.Channel17.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel17.Mz = i.Channel17.Mz
+					evi.Proteins[j].ModTotalLabels.Channel17.Intensity += i.Channel17.Intensity
+
+					evi.Proteins[j].ModTotalLabels.Channel18.Name = i.Channel18.Name
+					evi.Proteins[j].ModTotalLabels.Channel18.CustomName = i.Channel18.CustomName
+					evi.Proteins[j].ModTotalLabels.Channel18.Mz = i.Channel18.Mz
+					evi.Proteins[j].ModTotalLabels.Channel18.Intensity += i.Channel18.Intensity
 
 					//if k.IsNondegenerateEvidence {
-					if k.IsUnique {
-						evi.Proteins[j].PhosphoUniqueLabels.Channel1.Name = i.Channel1.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel1.CustomName = i.Channel1.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel1.Mz = i.Channel1.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel1.Intensity += i.Channel1.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel2.Name = i.Channel2.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel2.CustomName = i.Channel2.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel2.Mz = i.Channel2.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel2.Intensity += i.Channel2.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel3.Name = i.Channel3.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel3.CustomName = i.Channel3.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel3.Mz = i.Channel3.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel3.Intensity += i.Channel3.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel4.Name = i.Channel4.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel4.CustomName = i.Channel4.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel4.Mz = i.Channel4.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel4.Intensity += i.Channel4.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel5.Name = i.Channel5.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel5.CustomName = i.Channel5.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel5.Mz = i.Channel5.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel5.Intensity += i.Channel5.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel6.Name = i.Channel6.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel6.CustomName = i.Channel6.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel6.Mz = i.Channel6.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel6.Intensity += i.Channel6.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel7.Name = i.Channel7.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel7.CustomName = i.Channel7.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel7.Mz = i.Channel7.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel7.Intensity += i.Channel7.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel8.Name = i.Channel8.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel8.CustomName = i.Channel8.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel8.Mz = i.Channel8.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel8.Intensity += i.Channel8.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel9.Name = i.Channel9.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel9.CustomName = i.Channel9.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel9.Mz = i.Channel9.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel9.Intensity += i.Channel9.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel10.Name = i.Channel10.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel10.CustomName = i.Channel10.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel10.Mz = i.Channel10.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel10.Intensity += i.Channel10.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel11.Name = i.Channel11.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel11.CustomName = i.Channel11.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel11.Mz = i.Channel11.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel11.Intensity += i.Channel11.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel12.Name = i.Channel12.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel12.CustomName = i.Channel12.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel12.Mz = i.Channel12.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel12.Intensity += i.Channel12.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel13.Name = i.Channel13.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel13.CustomName = i.Channel13.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel13.Mz = i.Channel13.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel13.Intensity += i.Channel13.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel14.Name = i.Channel14.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel14.CustomName = i.Channel14.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel14.Mz = i.Channel14.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel14.Intensity += i.Channel14.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel15.Name = i.Channel15.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel15.CustomName = i.Channel15.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel15.Mz = i.Channel15.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel15.Intensity += i.Channel15.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel16.Name = i.Channel16.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel16.CustomName = i.Channel16.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel16.Mz = i.Channel16.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel16.Intensity += i.Channel16.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel17.Name = i.Channel17.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel17.CustomName = i.Channel17.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel17.Mz = i.Channel17.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel17.Intensity += i.Channel17.Intensity
-
-						evi.Proteins[j].PhosphoUniqueLabels.Channel18.Name = i.Channel18.Name
-						evi.Proteins[j].PhosphoUniqueLabels.Channel18.CustomName = i.Channel18.CustomName
-						evi.Proteins[j].PhosphoUniqueLabels.Channel18.Mz = i.Channel18.Mz
-						evi.Proteins[j].PhosphoUniqueLabels.Channel18.Intensity += i.Channel18.Intensity
+					if isUnique(k) {
+						evi.Proteins[j].ModUniqueLabels.Channel1.Name = i.Channel1.Name
+						evi.Proteins[j].ModUniqueLabels.Channel1.CustomName = i.Channel1.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel1.Mz = i.Channel1.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel1.Intensity += i.Channel1.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel2.Name = i.Channel2.Name
+						evi.Proteins[j].ModUniqueLabels.Channel2.CustomName = i.Channel2.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel2.Mz = i.Channel2.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel2.Intensity += i.Channel2.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel3.Name = i.Channel3.Name
+						evi.Proteins[j].ModUniqueLabels.Channel3.CustomName = i.Channel3.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel3.Mz = i.Channel3.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel3.Intensity += i.Channel3.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel4.Name = i.Channel4.Name
+						evi.Proteins[j].ModUniqueLabels.Channel4.CustomName = i.Channel4.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel4.Mz = i.Channel4.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel4.Intensity += i.Channel4.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel5.Name = i.Channel5.Name
+						evi.Proteins[j].ModUniqueLabels.Channel5.CustomName = i.Channel5.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel5.Mz = i.Channel5.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel5.Intensity += i.Channel5.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel6.Name = i.Channel6.Name
+						evi.Proteins[j].ModUniqueLabels.Channel6.CustomName = i.Channel6.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel6.Mz = i.Channel6.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel6.Intensity += i.Channel6.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel7.Name = i.Channel7.Name
+						evi.Proteins[j].ModUniqueLabels.Channel7.CustomName = i.Channel7.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel7.Mz = i.Channel7.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel7.Intensity += i.Channel7.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel8.Name = i.Channel8.Name
+						evi.Proteins[j].ModUniqueLabels.Channel8.CustomName = i.Channel8.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel8.Mz = i.Channel8.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel8.Intensity += i.Channel8.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel9.Name = i.Channel9.Name
+						evi.Proteins[j].ModUniqueLabels.Channel9.CustomName = i.Channel9.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel9.Mz = i.Channel9.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel9.Intensity += i.Channel9.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel10.Name = i.Channel10.Name
+						evi.Proteins[j].ModUniqueLabels.Channel10.CustomName = i.Channel10.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel10.Mz = i.Channel10.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel10.Intensity += i.Channel10.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel11.Name = i.Channel11.Name
+						evi.Proteins[j].ModUniqueLabels.Channel11.CustomName = i.Channel11.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel11.Mz = i.Channel11.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel11.Intensity += i.Channel11.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel12.Name = i.Channel12.Name
+						evi.Proteins[j].ModUniqueLabels.Channel12.CustomName = i.Channel12.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel12.Mz = i.Channel12.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel12.Intensity += i.Channel12.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel13.Name = i.Channel13.Name
+						evi.Proteins[j].ModUniqueLabels.Channel13.CustomName = i.Channel13.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel13.Mz = i.Channel13.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel13.Intensity += i.Channel13.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel14.Name = i.Channel14.Name
+						evi.Proteins[j].ModUniqueLabels.Channel14.CustomName = i.Channel14.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel14.Mz = i.Channel14.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel14.Intensity += i.Channel14.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel15.Name = i.Channel15.Name
+						evi.Proteins[j].ModUniqueLabels.Channel15.CustomName = i.Channel15.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel15.Mz = i.Channel15.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel15.Intensity += i.Channel15.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel16.Name = i.Channel16.Name
+						evi.Proteins[j].ModUniqueLabels.Channel16.CustomName = i.Channel16.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel16.Mz = i.Channel16.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel16.Intensity += i.Channel16.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel17.Name = i.Channel17.Name
+						evi.Proteins[j].ModUniqueLabels.Channel17.CustomName = i.Channel17.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel17.Mz = i.Channel17.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel17.Intensity += i.Channel17.Intensity
+
+						evi.Proteins[j].ModUniqueLabels.Channel18.Name = i.Channel18.Name
+						evi.Proteins[j].ModUniqueLabels.Channel18.CustomName = i.Channel18.CustomName
+						evi.Proteins[j].ModUniqueLabels.Channel18.Mz = i.Channel18.Mz
+						evi.Proteins[j].ModUniqueLabels.Channel18.Intensity += i.Channel18.Intensity
 					}
 
 					if k.IsURazor {
-						evi.Proteins[j].PhosphoURazorLabels.Channel1.Name = i.Channel1.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel1.CustomName = i.Channel1.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel1.Mz = i.Channel1.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel1.Intensity += i.Channel1.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel2.Name = i.Channel2.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel2.CustomName = i.Channel2.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel2.Mz = i.Channel2.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel2.Intensity += i.Channel2.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel3.Name = i.Channel3.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel3.CustomName = i.Channel3.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel3.Mz = i.Channel3.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel3.Intensity += i.Channel3.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel4.Name = i.Channel4.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel4.CustomName = i.Channel4.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel4.Mz = i.Channel4.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel4.Intensity += i.Channel4.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel5.Name = i.Channel5.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel5.CustomName = i.Channel5.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel5.Mz = i.Channel5.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel5.Intensity += i.Channel5.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel6.Name = i.Channel6.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel6.CustomName = i.Channel6.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel6.Mz = i.Channel6.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel6.Intensity += i.Channel6.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel7.Name = i.Channel7.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel7.CustomName = i.Channel7.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel7.Mz = i.Channel7.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel7.Intensity += i.Channel7.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel8.Name = i.Channel8.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel8.CustomName = i.Channel8.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel8.Mz = i.Channel8.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel8.Intensity += i.Channel8.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel9.Name = i.Channel9.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel9.CustomName = i.Channel9.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel9.Mz = i.Channel9.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel9.Intensity += i.Channel9.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel10.Name = i.Channel10.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel10.CustomName = i.Channel10.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel10.Mz = i.Channel10.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel10.Intensity += i.Channel10.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel11.Name = i.Channel11.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel11.CustomName = i.Channel11.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel11.Mz = i.Channel11.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel11.Intensity += i.Channel11.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel12.Name = i.Channel12.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel12.CustomName = i.Channel12.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel12.Mz = i.Channel12.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel12.Intensity += i.Channel12.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel13.Name = i.Channel13.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel13.CustomName = i.Channel13.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel13.Mz = i.Channel13.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel13.Intensity += i.Channel13.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel14.Name = i.Channel14.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel14.CustomName = i.Channel14.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel14.Mz = i.Channel14.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel14.Intensity += i.Channel14.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel15.Name = i.Channel15.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel15.CustomName = i.Channel15.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel15.Mz = i.Channel15.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel15.Intensity += i.Channel15.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel16.Name = i.Channel16.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel16.CustomName = i.Channel16.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel16.Mz = i.Channel16.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel16.Intensity += i.Channel16.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel17.Name = i.Channel17.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel17.CustomName = i.Channel17.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel17.Mz = i.Channel17.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel17.Intensity += i.Channel17.Intensity
-
-						evi.Proteins[j].PhosphoURazorLabels.Channel18.Name = i.Channel18.Name
-						evi.Proteins[j].PhosphoURazorLabels.Channel18.CustomName = i.Channel18.CustomName
-						evi.Proteins[j].PhosphoURazorLabels.Channel18.Mz = i.Channel18.Mz
-						evi.Proteins[j].PhosphoURazorLabels.Channel18.Intensity += i.Channel18.Intensity
+						evi.Proteins[j].ModURazorLabels.Channel1.Name = i.Channel1.Name
+						evi.Proteins[j].ModURazorLabels.Channel1.CustomName = i.Channel1.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel1.Mz = i.Channel1.Mz
+						evi.Proteins[j].ModURazorLabels.Channel1.Intensity += i.Channel1.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel2.Name = i.Channel2.Name
+						evi.Proteins[j].ModURazorLabels.Channel2.CustomName = i.Channel2.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel2.Mz = i.Channel2.Mz
+						evi.Proteins[j].ModURazorLabels.Channel2.Intensity += i.Channel2.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel3.Name = i.Channel3.Name
+						evi.Proteins[j].ModURazorLabels.Channel3.CustomName = i.Channel3.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel3.Mz = i.Channel3.Mz
+						evi.Proteins[j].ModURazorLabels.Channel3.Intensity += i.Channel3.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel4.Name = i.Channel4.Name
+						evi.Proteins[j].ModURazorLabels.Channel4.CustomName = i.Channel4.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel4.Mz = i.Channel4.Mz
+						evi.Proteins[j].ModURazorLabels.Channel4.Intensity += i.Channel4.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel5.Name = i.Channel5.Name
+						evi.Proteins[j].ModURazorLabels.Channel5.CustomName = i.Channel5.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel5.Mz = i.Channel5.Mz
+						evi.Proteins[j].ModURazorLabels.Channel5.Intensity += i.Channel5.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel6.Name = i.Channel6.Name
+						evi.Proteins[j].ModURazorLabels.Channel6.CustomName = i.Channel6.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel6.Mz = i.Channel6.Mz
+						evi.Proteins[j].ModURazorLabels.Channel6.Intensity += i.Channel6.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel7.Name = i.Channel7.Name
+						evi.Proteins[j].ModURazorLabels.Channel7.CustomName = i.Channel7.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel7.Mz = i.Channel7.Mz
+						evi.Proteins[j].ModURazorLabels.Channel7.Intensity += i.Channel7.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel8.Name = i.Channel8.Name
+						evi.Proteins[j].ModURazorLabels.Channel8.CustomName = i.Channel8.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel8.Mz = i.Channel8.Mz
+						evi.Proteins[j].ModURazorLabels.Channel8.Intensity += i.Channel8.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel9.Name = i.Channel9.Name
+						evi.Proteins[j].ModURazorLabels.Channel9.CustomName = i.Channel9.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel9.Mz = i.Channel9.Mz
+						evi.Proteins[j].ModURazorLabels.Channel9.Intensity += i.Channel9.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel10.Name = i.Channel10.Name
+						evi.Proteins[j].ModURazorLabels.Channel10.CustomName = i.Channel10.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel10.Mz = i.Channel10.Mz
+						evi.Proteins[j].ModURazorLabels.Channel10.Intensity += i.Channel10.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel11.Name = i.Channel11.Name
+						evi.Proteins[j].ModURazorLabels.Channel11.CustomName = i.Channel11.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel11.Mz = i.Channel11.Mz
+						evi.Proteins[j].ModURazorLabels.Channel11.Intensity += i.Channel11.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel12.Name = i.Channel12.Name
+						evi.Proteins[j].ModURazorLabels.Channel12.CustomName = i.Channel12.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel12.Mz = i.Channel12.Mz
+						evi.Proteins[j].ModURazorLabels.Channel12.Intensity += i.Channel12.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel13.Name = i.Channel13.Name
+						evi.Proteins[j].ModURazorLabels.Channel13.CustomName = i.Channel13.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel13.Mz = i.Channel13.Mz
+						evi.Proteins[j].ModURazorLabels.Channel13.Intensity += i.Channel13.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel14.Name = i.Channel14.Name
+						evi.Proteins[j].ModURazorLabels.Channel14.CustomName = i.Channel14.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel14.Mz = i.Channel14.Mz
+						evi.Proteins[j].ModURazorLabels.Channel14.Intensity += i.Channel14.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel15.Name = i.Channel15.Name
+						evi.Proteins[j].ModURazorLabels.Channel15.CustomName = i.Channel15.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel15.Mz = i.Channel15.Mz
+						evi.Proteins[j].ModURazorLabels.Channel15.Intensity += i.Channel15.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel16.Name = i.Channel16.Name
+						evi.Proteins[j].ModURazorLabels.Channel16.CustomName = i.Channel16.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel16.Mz = i.Channel16.Mz
+						evi.Proteins[j].ModURazorLabels.Channel16.Intensity += i.Channel16.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel17.Name = i.Channel17.Name
+						evi.Proteins[j].ModURazorLabels.Channel17.CustomName = i.Channel17.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel17.Mz = i.Channel17.Mz
+						evi.Proteins[j].ModURazorLabels.Channel17.Intensity += i.Channel17.Intensity
+
+						evi.Proteins[j].ModURazorLabels.Channel18.Name = i.Channel18.Name
+						evi.Proteins[j].ModURazorLabels.Channel18.CustomName = i.Channel18.CustomName
+						evi.Proteins[j].ModURazorLabels.Channel18.Mz = i.Channel18.Mz
+						evi.Proteins[j].ModURazorLabels.Channel18.Intensity += i.Channel18.Intensity
 					}
 				}
 
@@ -6,6 +6,7 @@ import (
 	"math"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"philosopher/lib/ext/rawfilereader"
@@ -21,6 +22,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// modificationTolerance is the mass tolerance in Daltons used to match the enriched modification
+const modificationTolerance = 0.01
+
 // Pair ...
 type Pair struct {
 	Key   string
@@ -51,8 +55,24 @@ func RunLabelFreeQuantification(p met.Quantify) {
 
 }
 
+// PhosphoModification is the modification summarized by --mapmods before the modification could be chosen
+const PhosphoModification = "STY:79.9663"
+
+// MapmodsModification returns the modification to summarize, workspaces filtered with --mapmods keep the
+// phosphorylation roll-up when no modification is given
+func MapmodsModification(modification string, mapmods bool) string {
+
+	if len(modification) > 0 || !mapmods {
+		return modification
+	}
+
+	logrus.Warn("Using --mapmods to summarize phosphorylated PSMs is deprecated, use --mod ", PhosphoModification, " instead")
+
+	return PhosphoModification
+}
+
 // RunIsobaricLabelQuantification is the top function for label quantification
-func RunIsobaricLabelQuantification(p met.Quantify, mods bool) met.Quantify {
+func RunIsobaricLabelQuantification(p met.Quantify) met.Quantify {
 
 	var psmMap = make(map[string]rep.PSMEvidence)
 	var sourceMap = make(map[string][]rep.PSMEvidence)
@@ -62,6 +82,16 @@ func RunIsobaricLabelQuantification(p met.Quantify, mods bool) met.Quantify {
 		msg.NoParametersFound(errors.New("you need to specify a brand type (tmt or itraq)"), "fatal")
 	}
 
+	// the selected uniqueness categories replace the unique peptides from the protein inference
+	categories, e := rep.ParseUniqueness(p.UniqueBy)
+	if e != nil {
+		msg.Custom(e, "fatal")
+	}
+
+	if len(categories) > 0 {
+		p.Unique = true
+	}
+
 	var evi rep.Evidence
 	evi.RestoreGranular()
 
@@ -159,7 +189,7 @@ func RunIsobaricLabelQuantification(p met.Quantify, mods bool) met.Quantify {
 
 	// classification and filtering based on quality filters
 	logrus.Info("Filtering spectra for label quantification")
-	spectrumMap, phosphoSpectrumMap := classification(evi, mods, p.BestPSM, p.RemoveLow, p.Purity, p.MinProb)
+	spectrumMap, modSpectrumMap := classification(evi, p.Mod, p.BestPSM, p.RemoveLow, p.Purity, p.MinProb)
 
 	// assignment happens only for general PSMs
 	evi = assignUsage(evi, spectrumMap)
@@ -167,11 +197,11 @@ func RunIsobaricLabelQuantification(p met.Quantify, mods bool) met.Quantify {
 	// forces psms with no label to have 0 intensities
 	evi = correctUnlabelledSpectra(evi)
 
-	evi = rollUpPeptides(evi, spectrumMap, phosphoSpectrumMap)
+	evi = rollUpPeptides(evi, spectrumMap, modSpectrumMap)
 
-	evi = rollUpPeptideIons(evi, spectrumMap, phosphoSpectrumMap)
+	evi = rollUpPeptideIons(evi, spectrumMap, modSpectrumMap)
 
-	evi = rollUpProteins(evi, spectrumMap, phosphoSpectrumMap)
+	evi = rollUpProteins(evi, spectrumMap, modSpectrumMap, uniqueIonSelector(evi, categories))
 
 	// normalize to the total protein levels
 	logrus.Info("Calculating normalized protein levels")
@@ -204,7 +234,7 @@ func RunBioQuantification(c met.Data) {
 
 	// parse the cluster file
 	logrus.Info("Parsing clusters")
-	clusters := parseClusterFile(clusterFile, clusterFasta)
+	clusters := parseClusterFile(clusterFile, clusterFasta, c.Database.Tag)
 
 	// maps all proteins from the db against the clusters
 	logrus.Info("Mapping proteins to clusters")
@@ -451,13 +481,23 @@ func assignLabelNames(labels map[string]iso.Labels, labelNames map[string]string
 	return labels
 }
 
-func classification(evi rep.Evidence, mods, best bool, remove, purity, probability float64) (map[string]iso.Labels, map[string]iso.Labels) {
+func classification(evi rep.Evidence, modification string, best bool, remove, purity, probability float64) (map[string]iso.Labels, map[string]iso.Labels) {
 
 	var spectrumMap = make(map[string]iso.Labels)
-	var phosphoSpectrumMap = make(map[string]iso.Labels)
+	var modSpectrumMap = make(map[string]iso.Labels)
 	var bestMap = make(map[string]uint8)
 	var psmLabelSumList PairList
 	var quantCheckUp bool
+	var residues string
+	var mass float64
+
+	if len(modification) > 0 {
+		var e error
+		residues, mass, e = parseModificationDefinition(modification)
+		if e != nil {
+			msg.Custom(e, "fatal")
+		}
+	}
 
 	// 1st check: Purity the score and the Probability levels
 	for _, i := range evi.PSM {
@@ -466,13 +506,8 @@ func classification(evi rep.Evidence, mods, best bool, remove, purity, probabili
 			spectrumMap[i.Spectrum] = i.Labels
 			bestMap[i.Spectrum] = 0
 
-			if mods {
-				_, ok1 := i.LocalizedPTMSites["PTMProphet_STY79.9663"]
-				_, ok2 := i.LocalizedPTMSites["PTMProphet_STY79.96633"]
-				_, ok3 := i.LocalizedPTMSites["PTMProphet_STY79.966331"]
-				if ok1 || ok2 || ok3 {
-					phosphoSpectrumMap[i.Spectrum] = i.Labels
-				}
+			if len(residues) > 0 && hasModification(i, residues, mass) {
+				modSpectrumMap[i.Spectrum] = i.Labels
 			}
 
 		}
@@ -560,7 +595,7 @@ func classification(evi rep.Evidence, mods, best bool, remove, purity, probabili
 	}
 
 	var toDelete = make(map[string]uint8)
-	var toDeletePhospho = make(map[string]uint8)
+	var toDeleteMod = make(map[string]uint8)
 
 	// 3rd check: remove the lower 3%
 	// Ignore all PSMs that fall under the lower 3% based on their summed TMT labels
@@ -571,7 +606,7 @@ func classification(evi rep.Evidence, mods, best bool, remove, purity, probabili
 
 		for i := 0; i <= lowerFiveInt; i++ {
 			toDelete[psmLabelSumList[i].Key] = 0
-			toDeletePhospho[psmLabelSumList[i].Key] = 0
+			toDeleteMod[psmLabelSumList[i].Key] = 0
 		}
 	}
 
@@ -586,19 +621,112 @@ func classification(evi rep.Evidence, mods, best bool, remove, purity, probabili
 		delete(spectrumMap, i)
 	}
 
-	for k := range phosphoSpectrumMap {
+	for k := range modSpectrumMap {
 		_, ok := bestMap[k]
 		if !ok {
-			toDeletePhospho[k] = 0
+			toDeleteMod[k] = 0
 		}
 	}
 
 	logrus.Info("Removing ", len(toDelete), " PSMs from isobaric quantification")
-	for i := range toDeletePhospho {
-		delete(phosphoSpectrumMap, i)
+	for i := range toDeleteMod {
+		delete(modSpectrumMap, i)
+	}
+
+	return spectrumMap, modSpectrumMap
+}
+
+// parseModificationDefinition reads a modification given as residues and mass, like STY:79.9663 or K:114.0429.
+// Protein or peptide termini are represented by n and c
+func parseModificationDefinition(s string) (string, float64, error) {
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || len(parts[0]) == 0 {
+		return "", 0, errors.New("the modification must be defined by residues and mass, e.g. STY:79.9663")
+	}
+
+	mass, e := strconv.ParseFloat(parts[1], 64)
+	if e != nil {
+		return "", 0, errors.New("the modification mass is not a valid number")
+	}
+
+	return parts[0], mass, nil
+}
+
+// hasModification checks if a PSM carries the modification on one of the given residues, either assigned by
+// the search engine, localized by PTMProphet, or observed as a mass shift in open searches. Observed mass
+// shifts only count when MSFragger localized them on one of the residues
+func hasModification(psm rep.PSMEvidence, residues string, mass float64) bool {
+
+	for _, i := range psm.Modifications.Index {
+
+		if math.Abs(i.MassDiff-mass) > modificationTolerance {
+			continue
+		}
+
+		if i.Type == "Observed" {
+			if isLocalizedOn(psm.MSFragerLocalization, residues) {
+				return true
+			}
+			continue
+		}
+
+		if i.Type == "Assigned" {
+			aa := i.AminoAcid
+			if aa == "N-term" {
+				aa = "n"
+			} else if aa == "C-term" {
+				aa = "c"
+			}
+
+			if strings.Contains(residues, aa) {
+				return true
+			}
+		}
+	}
+
+	// PTMProphet reports the modifications like PTMProphet_STY79.9663
+	for k := range psm.LocalizedPTMSites {
+
+		ptm := strings.TrimPrefix(k, "PTMProphet_")
+		idx := strings.IndexAny(ptm, "-0123456789")
+		if idx < 1 {
+			continue
+		}
+
+		m, e := strconv.ParseFloat(ptm[idx:], 64)
+		if e != nil || math.Abs(m-mass) > modificationTolerance {
+			continue
+		}
+
+		if strings.ContainsAny(ptm[:idx], residues) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isLocalizedOn checks if the MSFragger localization, where lower case residues carry the mass shift, places
+// the shift on one of the given residues. The n and c termini match the first and last residues
+func isLocalizedOn(localization, residues string) bool {
+
+	for i, j := range localization {
+
+		if j < 'a' || j > 'z' {
+			continue
+		}
+
+		if strings.ContainsRune(residues, j-'a'+'A') {
+			return true
+		}
+
+		if (i == 0 && strings.Contains(residues, "n")) || (i == len(localization)-1 && strings.Contains(residues, "c")) {
+			return true
+		}
 	}
 
-	return spectrumMap, phosphoSpectrumMap
+	return false
 }
 
 // calculateIonPurity verifies how much interference there is on the precursor scans for each fragment
@@ -0,0 +1,123 @@
+package qua
+
+import (
+	"testing"
+
+	"philosopher/lib/mod"
+	"philosopher/lib/rep"
+)
+
+func TestParseModificationDefinition(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		def      string
+		residues string
+		mass     float64
+		wantErr  bool
+	}{
+		{"Phosphorylation", "STY:79.9663", "STY", 79.9663, false},
+		{"Protein N-terminus", "n:42.0106", "n", 42.0106, false},
+		{"Negative mass", "M:-1.0", "M", -1, false},
+		{"Missing mass", "STY", "", 0, true},
+		{"Missing residues", ":79.9663", "", 0, true},
+		{"Invalid mass", "STY:phospho", "", 0, true},
+		{"Too many fields", "STY:79.9663:1", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			residues, mass, e := parseModificationDefinition(tt.def)
+			if (e != nil) != tt.wantErr {
+				t.Fatalf("parseModificationDefinition() error = %v, wantErr %v", e, tt.wantErr)
+			}
+
+			if residues != tt.residues || mass != tt.mass {
+				t.Errorf("parseModificationDefinition() = %v, %v, want %v, %v", residues, mass, tt.residues, tt.mass)
+			}
+		})
+	}
+}
+
+func TestHasModification(t *testing.T) {
+
+	assigned := func(aa string, mass float64) mod.Modifications {
+		return mod.Modifications{Index: map[string]mod.Modification{aa: {Type: "Assigned", AminoAcid: aa, MassDiff: mass}}}
+	}
+
+	observed := mod.Modifications{Index: map[string]mod.Modification{"79.9663": {Type: "Observed", MassDiff: 79.9663}}}
+
+	tests := []struct {
+		name     string
+		psm      rep.PSMEvidence
+		residues string
+		want     bool
+	}{
+		{"Assigned on a serine", rep.PSMEvidence{Modifications: assigned("S", 79.9663)}, "STY", true},
+		{"Assigned on a cysteine", rep.PSMEvidence{Modifications: assigned("C", 79.9663)}, "STY", false},
+		{"Assigned with another mass", rep.PSMEvidence{Modifications: assigned("S", 15.9949)}, "STY", false},
+		{"Assigned on the N-terminus", rep.PSMEvidence{Modifications: assigned("N-term", 79.9663)}, "n", true},
+		{"Observed on a threonine", rep.PSMEvidence{Modifications: observed, MSFragerLocalization: "PEPtIDEK"}, "STY", true},
+		{"Observed on a cysteine", rep.PSMEvidence{Modifications: observed, MSFragerLocalization: "PEPcIDEK"}, "STY", false},
+		{"Observed on the N-terminus", rep.PSMEvidence{Modifications: observed, MSFragerLocalization: "pEPTIDEK"}, "STY", false},
+		{"Observed without localization", rep.PSMEvidence{Modifications: observed}, "STY", false},
+		{"Observed on the first residue for an N-terminal modification", rep.PSMEvidence{Modifications: observed, MSFragerLocalization: "pEPTIDEK"}, "n", true},
+		{"Localized by PTMProphet", rep.PSMEvidence{LocalizedPTMSites: map[string]int{"PTMProphet_STY79.9663": 1}}, "STY", true},
+		{"Localized by PTMProphet on other residues", rep.PSMEvidence{LocalizedPTMSites: map[string]int{"PTMProphet_C79.9663": 1}}, "STY", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasModification(tt.psm, tt.residues, 79.9663); got != tt.want {
+				t.Errorf("hasModification() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDecoyMember(t *testing.T) {
+
+	tests := []struct {
+		line string
+		tag  string
+		want bool
+	}{
+		{"0\t120aa, >sp|P00001|A_HUMAN... *", "rev_", false},
+		{"1\t120aa, >rev_sp|P00001|A_HUMAN... at 100.00%", "rev_", true},
+		{"1\t120aa, >sp|P00001|A_HUMAN_REVERSED... *", "suffix:_REVERSED", true},
+		{"1\t120aa, >DECOY_sp|P00001|A_HUMAN... *", "rev_,DECOY_", true},
+		{">Cluster 0", "rev_", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := isDecoyMember(tt.line, tt.tag); got != tt.want {
+				t.Errorf("isDecoyMember() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapmodsModification(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		modification string
+		mapmods      bool
+		want         string
+	}{
+		{"No modification", "", false, ""},
+		{"Filtered with --mapmods", "", true, PhosphoModification},
+		{"Chosen modification", "K:114.0429", true, "K:114.0429"},
+		{"Chosen modification without --mapmods", "K:114.0429", false, "K:114.0429"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MapmodsModification(tt.modification, tt.mapmods); got != tt.want {
+				t.Errorf("MapmodsModification() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"philosopher/lib/bio"
+	"philosopher/lib/msg"
+	"philosopher/lib/obo"
+)
+
+// annotationTolerance is the maximum mass error in Daltons accepted for a mass bin explanation
+const annotationTolerance = 0.02
+
+// isotopeSpacing is the mass difference between the 13C and 12C isotopes
+const isotopeSpacing = 1.003355
+
+// maxAnnotations is the number of candidates kept for each mass bin
+const maxAnnotations = 10
+
+// MassAnnotation is a candidate explanation for a mass bin
+type MassAnnotation struct {
+	Type        string
+	Name        string
+	Mass        float64
+	Error       float64
+	Sites       []string
+	Consistency float64
+	Score       float64
+}
+
+// substitution represents an amino acid exchange and the mass shift it causes
+type substitution struct {
+	From string
+	To   string
+	Mass float64
+}
+
+// AnnotateMassBins explains each observed mass bin with UniMod entries, pairs of modifications,
+// amino acid substitutions and isotope errors. Candidates are ranked by their mass error, by how
+// plausible the explanation type is and by the agreement with the localized residues
+func (evi *Evidence) AnnotateMassBins() {
+
+	o := obo.NewUniModOntology()
+
+	var terms []obo.Term
+	for _, i := range o.Terms {
+		if i.MonoIsotopicMass != 0 {
+			terms = append(terms, i)
+		}
+	}
+
+	evi.annotateMassBins(terms)
+}
+
+// annotateMassBins ranks the candidate explanations of the observed mass bins from a list of UniMod terms
+func (evi *Evidence) annotateMassBins(terms []obo.Term) {
+
+	sort.Slice(terms, func(i, j int) bool {
+		return terms[i].MonoIsotopicMass < terms[j].MonoIsotopicMass
+	})
+
+	substitutions := aminoAcidSubstitutions()
+
+	for i := range evi.Modifications.MassBins {
+
+		bin := &evi.Modifications.MassBins[i]
+
+		if len(bin.ObservedMods) == 0 || bin.MassCenter == 0 {
+			continue
+		}
+
+		bin.LocalizedResidues = countLocalizedResidues(bin.ObservedMods)
+
+		var candidates []MassAnnotation
+		mass := bin.CorrectedMass
+
+		// single modifications
+		for _, t := range termsInWindow(terms, mass) {
+			candidates = append(candidates, newAnnotation("UniMod", t.Name, t.MonoIsotopicMass, mass, siteList(t.Sites), 1.0, bin.LocalizedResidues))
+		}
+
+		// isotope errors, alone or on top of a modification
+		for n := -1; n <= 3; n++ {
+
+			if n == 0 {
+				continue
+			}
+
+			shift := float64(n) * isotopeSpacing
+
+			if math.Abs(mass-shift) <= annotationTolerance {
+				candidates = append(candidates, newAnnotation("Isotope Error", fmt.Sprintf("%+d 13C", n), shift, mass, nil, 0.9, bin.LocalizedResidues))
+			}
+
+			for _, t := range termsInWindow(terms, mass-shift) {
+				name := fmt.Sprintf("%s %+d 13C", t.Name, n)
+				candidates = append(candidates, newAnnotation("UniMod + Isotope Error", name, t.MonoIsotopicMass+shift, mass, siteList(t.Sites), 0.6, bin.LocalizedResidues))
+			}
+		}
+
+		// amino acid substitutions
+		for _, s := range substitutions {
+			if math.Abs(mass-s.Mass) <= annotationTolerance {
+				name := fmt.Sprintf("%s->%s", s.From, s.To)
+				candidates = append(candidates, newAnnotation("Substitution", name, s.Mass, mass, []string{s.From}, 0.7, bin.LocalizedResidues))
+			}
+		}
+
+		// pairs of modifications, each pair is visited only once
+		for j, a := range terms {
+
+			if a.MonoIsotopicMass > mass-a.MonoIsotopicMass+annotationTolerance {
+				break
+			}
+
+			for _, b := range termsInWindow(terms[j:], mass-a.MonoIsotopicMass) {
+
+				sites := siteList(a.Sites)
+				sites = append(sites, siteList(b.Sites)...)
+
+				name := fmt.Sprintf("%s + %s", a.Name, b.Name)
+				candidates = append(candidates, newAnnotation("Combination", name, a.MonoIsotopicMass+b.MonoIsotopicMass, mass, sites, 0.5, bin.LocalizedResidues))
+			}
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Score > candidates[j].Score
+		})
+
+		if len(candidates) > maxAnnotations {
+			candidates = candidates[:maxAnnotations]
+		}
+
+		bin.Annotations = candidates
+	}
+}
+
+// termsInWindow returns the UniMod terms that match the mass within the annotation tolerance.
+// The list must be sorted by mass
+func termsInWindow(terms []obo.Term, mass float64) []obo.Term {
+
+	start := sort.Search(len(terms), func(i int) bool {
+		return terms[i].MonoIsotopicMass >= mass-annotationTolerance
+	})
+
+	end := start
+	for end < len(terms) && terms[end].MonoIsotopicMass <= mass+annotationTolerance {
+		end++
+	}
+
+	return terms[start:end]
+}
+
+// newAnnotation creates a scored candidate. The prior weights the type of explanation, single
+// modifications being more likely than isotope errors, substitutions and combinations
+func newAnnotation(t, name string, mass, observed float64, sites []string, prior float64, localized map[string]int) MassAnnotation {
+
+	var a MassAnnotation
+
+	a.Type = t
+	a.Name = name
+	a.Mass = mass
+	a.Error = observed - mass
+	a.Sites = sites
+	a.Consistency = localizationConsistency(sites, localized)
+	a.Score = prior * (1 - 0.5*math.Abs(a.Error)/annotationTolerance) * (0.5 + 0.5*a.Consistency)
+
+	return a
+}
+
+// localizationConsistency is the fraction of localized observations that fall on the candidate residues.
+// When there is nothing to compare the value is neutral
+func localizationConsistency(sites []string, localized map[string]int) float64 {
+
+	var total int
+	for _, v := range localized {
+		total += v
+	}
+
+	if total == 0 || len(sites) == 0 {
+		return 0.5
+	}
+
+	var matched = make(map[string]uint8)
+	for _, i := range sites {
+		matched[i] = 0
+	}
+
+	var hits int
+	for k, v := range localized {
+		if _, ok := matched[k]; ok {
+			hits += v
+		}
+	}
+
+	return float64(hits) / float64(total)
+}
+
+// countLocalizedResidues counts the residues where MSFragger placed the mass shift, each PSM counts once per residue
+func countLocalizedResidues(psm PSMEvidenceList) map[string]int {
+
+	var residues = make(map[string]int)
+
+	for _, i := range psm {
+
+		var seen = make(map[string]uint8)
+
+		for _, j := range i.MSFragerLocalization {
+			if j >= 'a' && j <= 'z' {
+				seen[strings.ToUpper(string(j))] = 0
+			}
+		}
+
+		for k := range seen {
+			residues[k]++
+		}
+	}
+
+	return residues
+}
+
+// siteList returns the sorted residues from a UniMod site map
+func siteList(sites map[string]uint8) []string {
+
+	var list []string
+	for k := range sites {
+		list = append(list, k)
+	}
+
+	sort.Strings(list)
+
+	return list
+}
+
+// aminoAcidSubstitutions lists the mass shifts for all single amino acid exchanges
+func aminoAcidSubstitutions() []substitution {
+
+	aas := bio.AminoAcids()
+
+	var list []substitution
+	for _, i := range aas {
+		for _, j := range aas {
+
+			mass := j.MonoIsotopeMass - i.MonoIsotopeMass
+
+			// isobaric residues do not explain a mass shift
+			if math.Abs(mass) <= annotationTolerance {
+				continue
+			}
+
+			list = append(list, substitution{From: i.Code, To: j.Code, Mass: mass})
+		}
+	}
+
+	return list
+}
+
+// formatResidues prints the localized residues sorted by their counts
+func formatResidues(residues map[string]int) string {
+
+	var keys []string
+	for k := range residues {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if residues[keys[i]] != residues[keys[j]] {
+			return residues[keys[i]] > residues[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	var list []string
+	for _, i := range keys {
+		list = append(list, fmt.Sprintf("%s(%d)", i, residues[i]))
+	}
+
+	return strings.Join(list, ", ")
+}
+
+// ModificationSummaryReport summarizes the mass shifts by their best explanation
+func (evi *Evidence) ModificationSummaryReport(workspace string) {
+
+	type summary struct {
+		Name      string
+		Type      string
+		Mass      float64
+		Bins      int
+		PSMs      int
+		Localized map[string]int
+	}
+
+	var summaryMap = make(map[string]*summary)
+	var total int
+
+	for _, i := range evi.Modifications.MassBins {
+
+		if len(i.ObservedMods) == 0 || i.MassCenter == 0 {
+			continue
+		}
+
+		total += len(i.ObservedMods)
+
+		name := "Unannotated"
+		t := ""
+		mass := i.CorrectedMass
+		if len(i.Annotations) > 0 {
+			name = i.Annotations[0].Name
+			t = i.Annotations[0].Type
+			mass = i.Annotations[0].Mass
+		}
+
+		s, ok := summaryMap[name]
+		if !ok {
+			s = &summary{Name: name, Type: t, Mass: mass, Localized: make(map[string]int)}
+			summaryMap[name] = s
+		}
+
+		s.Bins++
+		s.PSMs += len(i.ObservedMods)
+		for k, v := range i.LocalizedResidues {
+			s.Localized[k] += v
+		}
+	}
+
+	var list []*summary
+	for _, v := range summaryMap {
+		list = append(list, v)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].PSMs != list[j].PSMs {
+			return list[i].PSMs > list[j].PSMs
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	output := fmt.Sprintf("%s%smodifications_summary.tsv", workspace, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("could not create report files"), "error")
+	}
+	defer file.Close()
+
+	_, e = io.WriteString(file, "Annotation\tAnnotation Type\tMass\tMass Bins\tPSMs\tPercent of Mass-Shifted PSMs\tLocalized Residues\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for _, i := range list {
+
+		line := fmt.Sprintf("%s\t%s\t%.4f\t%d\t%d\t%.2f\t%s\n",
+			i.Name,
+			i.Type,
+			i.Mass,
+			i.Bins,
+			i.PSMs,
+			100*float64(i.PSMs)/float64(total),
+			formatResidues(i.Localized),
+		)
+
+		_, e = io.WriteString(file, line)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"philosopher/lib/dat"
+	"philosopher/lib/msg"
+
+	"github.com/sirupsen/logrus"
+)
+
+// classCounts holds the target and decoy identifications of a sequence class
+type classCounts struct {
+	targetPSMs     int
+	decoyPSMs      int
+	targetPeptides map[string]uint8
+	decoyPeptides  map[string]uint8
+	targetProteins int
+	decoyProteins  int
+}
+
+// HasSequenceClasses reports whether the evidences came from a proteogenomic database
+func (evi Evidence) HasSequenceClasses() bool {
+
+	for _, i := range evi.PSM {
+		if i.Class == dat.VariantClass || i.Class == dat.NovelClass {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SequenceClassReport reports the decoy to target proportion of the canonical, variant and novel identifications
+// that passed the global filter. The thresholds are shared by every class, so the proportion is a post-filter
+// estimate of the false discovery proportion of each class, not a separately controlled FDR
+func (evi Evidence) SequenceClassReport(workspace string) {
+
+	classes := []string{dat.CanonicalClass, dat.VariantClass, dat.NovelClass}
+
+	var counts = make(map[string]*classCounts)
+	for _, i := range classes {
+		counts[i] = &classCounts{
+			targetPeptides: make(map[string]uint8),
+			decoyPeptides:  make(map[string]uint8),
+		}
+	}
+
+	for _, i := range evi.PSM {
+
+		c, ok := counts[i.Class]
+		if !ok {
+			c = counts[dat.CanonicalClass]
+		}
+
+		if i.IsDecoy {
+			c.decoyPSMs++
+			c.decoyPeptides[i.Peptide] = 0
+		} else {
+			c.targetPSMs++
+			c.targetPeptides[i.Peptide] = 0
+		}
+	}
+
+	for _, i := range evi.Proteins {
+
+		c, ok := counts[i.Class]
+		if !ok {
+			c = counts[dat.CanonicalClass]
+		}
+
+		if i.IsDecoy {
+			c.decoyProteins++
+		} else {
+			c.targetProteins++
+		}
+	}
+
+	output := fmt.Sprintf("%s%ssequence_class.tsv", workspace, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("cannot create the sequence class report"), "error")
+	}
+	defer file.Close()
+
+	_, e = io.WriteString(file, "Sequence Class\tTarget PSMs\tDecoy PSMs\tPSM FDP\tTarget Peptides\tDecoy Peptides\tPeptide FDP\tTarget Proteins\tDecoy Proteins\tProtein FDP\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for _, i := range classes {
+
+		c := counts[i]
+
+		psmFDP := classFDP(c.targetPSMs, c.decoyPSMs)
+		pepFDP := classFDP(len(c.targetPeptides), len(c.decoyPeptides))
+		proFDP := classFDP(c.targetProteins, c.decoyProteins)
+
+		logrus.WithFields(logrus.Fields{
+			"psms":     c.targetPSMs,
+			"peptides": len(c.targetPeptides),
+			"psm fdp":  fmt.Sprintf("%.4f", psmFDP),
+		}).Info("Sequence class ", strings.Title(i))
+
+		line := fmt.Sprintf("%s\t%d\t%d\t%.4f\t%d\t%d\t%.4f\t%d\t%d\t%.4f\n",
+			i,
+			c.targetPSMs,
+			c.decoyPSMs,
+			psmFDP,
+			len(c.targetPeptides),
+			len(c.decoyPeptides),
+			pepFDP,
+			c.targetProteins,
+			c.decoyProteins,
+			proFDP,
+		)
+
+		_, e = io.WriteString(file, line)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+}
+
+// classFDP is the decoy to target ratio of the filtered identifications
+func classFDP(targets, decoys int) float64 {
+
+	if targets == 0 {
+		return 0
+	}
+
+	return float64(decoys) / float64(targets)
+}
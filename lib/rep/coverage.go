@@ -0,0 +1,412 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"philosopher/lib/bio"
+	"philosopher/lib/msg"
+)
+
+// peptide classes on the coverage maps, from the most to the least specific
+const (
+	UniqueCoverage = "unique"
+	RazorCoverage  = "razor"
+	SharedCoverage = "shared"
+)
+
+// coverageLine is the number of residues in each line of the sequence view
+const coverageLine = 60
+
+// coverageStyle colors the residues of the sequence view by the most specific peptide covering them
+const coverageStyle = `body { font-family: Helvetica, Arial, sans-serif; margin: 20px; color: #222; }
+h1 { font-size: 22px; }
+h2 { font-size: 15px; margin-top: 25px; border-bottom: 1px solid #ccc; }
+pre { font-size: 13px; line-height: 1.5; }
+.unique { background: #4c78a8; color: #fff; }
+.razor { background: #9ecae9; }
+.shared { background: #e0e0e0; }
+.mod { font-weight: bold; text-decoration: underline; color: #e45756; }
+.pos { color: #999; }
+`
+
+// PeptidePosition is an occurrence of an identified peptide on a protein sequence
+type PeptidePosition struct {
+	Peptide       string
+	Peptidoform   string
+	Start         int
+	End           int
+	Class         string
+	Spc           int
+	Modifications []string
+}
+
+// CoverageMap is the residue-level coverage of a protein, Depth counts the peptides covering each residue
+type CoverageMap struct {
+	Protein   string
+	ProteinID string
+	GeneNames string
+	Sequence  string
+	Coverage  float64
+	IsDecoy   bool
+	Depth     []int
+	Modified  map[int][]string
+	Peptides  []PeptidePosition
+}
+
+// coveragePeptide collects the PSMs of a peptidoform mapped to a protein
+type coveragePeptide struct {
+	sequence string
+	class    string
+	spc      int
+	mods     map[int]string
+}
+
+// CoverageMaps places the identified peptides on the sequence of each reported protein. Each peptidoform is
+// placed on its own, so the modifications of different PSMs of the same sequence are not merged
+func (evi Evidence) CoverageMaps() []CoverageMap {
+
+	var proteins = make(map[string]map[string]*coveragePeptide)
+
+	for _, i := range evi.PSM {
+
+		var mapped = []string{i.Protein}
+		for j := range i.MappedProteins {
+			if j != i.Protein {
+				mapped = append(mapped, j)
+			}
+		}
+
+		for _, j := range mapped {
+
+			class := SharedCoverage
+			if i.IsUnique {
+				class = UniqueCoverage
+			} else if i.IsURazor && j == i.Protein {
+				class = RazorCoverage
+			}
+
+			if _, ok := proteins[j]; !ok {
+				proteins[j] = make(map[string]*coveragePeptide)
+			}
+
+			p, ok := proteins[j][i.Peptidoform()]
+			if !ok {
+				p = &coveragePeptide{sequence: i.Peptide, class: class, mods: make(map[int]string)}
+				proteins[j][i.Peptidoform()] = p
+			}
+
+			if coverageRank(class) < coverageRank(p.class) {
+				p.class = class
+			}
+
+			p.spc++
+
+			for _, k := range i.Modifications.Index {
+
+				if k.Type != "Assigned" || k.Name == "Unknown" {
+					continue
+				}
+
+				position, e := strconv.Atoi(k.Position)
+				if e != nil {
+					switch k.AminoAcid {
+					case "N-term":
+						position = 1
+					case "C-term":
+						position = len(i.Peptide)
+					default:
+						continue
+					}
+				}
+
+				p.mods[position] = fmt.Sprintf("%s(%.4f)", k.AminoAcid, k.MassDiff)
+			}
+		}
+	}
+
+	var maps []CoverageMap
+	for _, i := range evi.Proteins {
+
+		peptides, ok := proteins[i.PartHeader]
+		if !ok || len(i.Sequence) == 0 {
+			continue
+		}
+
+		c := CoverageMap{
+			Protein:   i.PartHeader,
+			ProteinID: i.ProteinID,
+			GeneNames: i.GeneNames,
+			Sequence:  i.Sequence,
+			IsDecoy:   i.IsDecoy,
+			Modified:  make(map[int][]string),
+		}
+
+		var forms []string
+		var unique = make(map[string]uint8)
+		for k, v := range peptides {
+			forms = append(forms, k)
+			unique[v.sequence] = 0
+		}
+		sort.Strings(forms)
+
+		var sequences []string
+		for k := range unique {
+			sequences = append(sequences, k)
+		}
+		sort.Strings(sequences)
+
+		for _, k := range forms {
+
+			p := peptides[k]
+
+			for _, start := range bio.PeptidePositions(i.Sequence, p.sequence) {
+
+				pos := PeptidePosition{Peptide: p.sequence, Peptidoform: k, Start: start, End: start + len(p.sequence) - 1, Class: p.class, Spc: p.spc}
+
+				var offsets []int
+				for j := range p.mods {
+					offsets = append(offsets, j)
+				}
+				sort.Ints(offsets)
+
+				for _, j := range offsets {
+					position := start + j - 1
+					pos.Modifications = append(pos.Modifications, fmt.Sprintf("%d%s", position, p.mods[j]))
+					c.Modified[position] = appendOnce(c.Modified[position], p.mods[j])
+				}
+
+				c.Peptides = append(c.Peptides, pos)
+			}
+		}
+
+		sort.SliceStable(c.Peptides, func(a, b int) bool {
+			if c.Peptides[a].Start != c.Peptides[b].Start {
+				return c.Peptides[a].Start < c.Peptides[b].Start
+			}
+			if c.Peptides[a].End != c.Peptides[b].End {
+				return c.Peptides[a].End < c.Peptides[b].End
+			}
+			return c.Peptides[a].Peptidoform < c.Peptides[b].Peptidoform
+		})
+
+		c.Depth = bio.CoverageMap(i.Sequence, sequences)
+		c.Coverage = bio.Coverage(i.Sequence, sequences)
+
+		maps = append(maps, c)
+	}
+
+	return maps
+}
+
+// coverageRank orders the peptide classes, lower is more specific
+func coverageRank(class string) int {
+
+	switch class {
+	case UniqueCoverage:
+		return 0
+	case RazorCoverage:
+		return 1
+	}
+
+	return 2
+}
+
+// appendOnce adds a string to a list when it is not there yet
+func appendOnce(list []string, s string) []string {
+
+	for _, i := range list {
+		if i == s {
+			return list
+		}
+	}
+
+	return append(list, s)
+}
+
+// residueClasses returns the most specific peptide class covering each residue
+func (c CoverageMap) residueClasses() []string {
+
+	var classes = make([]string, len(c.Sequence))
+
+	for _, i := range c.Peptides {
+		for j := i.Start - 1; j < i.End && j < len(classes); j++ {
+			if len(classes[j]) == 0 || coverageRank(i.Class) < coverageRank(classes[j]) {
+				classes[j] = i.Class
+			}
+		}
+	}
+
+	return classes
+}
+
+// CoverageReport writes the peptide positions of each protein to coverage.tsv, the residue depths to coverage_depth.tsv
+// and the sequence views to coverage.html
+func (evi Evidence) CoverageReport(workspace string, hasDecoys bool) {
+
+	var printSet []CoverageMap
+	for _, i := range evi.CoverageMaps() {
+		if !hasDecoys && i.IsDecoy {
+			continue
+		}
+		printSet = append(printSet, i)
+	}
+
+	output := fmt.Sprintf("%s%scoverage.tsv", workspace, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("cannot create the coverage report"), "error")
+	}
+	defer file.Close()
+
+	_, e = io.WriteString(file, "Protein\tProtein ID\tGene\tLength\tPercent Coverage\tPeptide\tModified Peptide\tStart\tEnd\tClass\tSpectral Count\tModifications\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for _, i := range printSet {
+		for _, j := range i.Peptides {
+
+			line := fmt.Sprintf("%s\t%s\t%s\t%d\t%.2f\t%s\t%s\t%d\t%d\t%s\t%d\t%s\n",
+				i.Protein,
+				i.ProteinID,
+				i.GeneNames,
+				len(i.Sequence),
+				i.Coverage,
+				j.Peptide,
+				j.Peptidoform,
+				j.Start,
+				j.End,
+				j.Class,
+				j.Spc,
+				strings.Join(j.Modifications, ", "),
+			)
+
+			_, e = io.WriteString(file, line)
+			if e != nil {
+				msg.WriteToFile(e, "fatal")
+			}
+		}
+	}
+
+	output = fmt.Sprintf("%s%scoverage_depth.tsv", workspace, string(filepath.Separator))
+
+	depth, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("cannot create the coverage depth report"), "error")
+	}
+	defer depth.Close()
+
+	_, e = io.WriteString(depth, "Protein\tProtein ID\tGene\tPosition\tResidue\tDepth\tClass\tModifications\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for _, i := range printSet {
+
+		classes := i.residueClasses()
+
+		for j := range i.Depth {
+
+			line := fmt.Sprintf("%s\t%s\t%s\t%d\t%c\t%d\t%s\t%s\n",
+				i.Protein,
+				i.ProteinID,
+				i.GeneNames,
+				j+1,
+				i.Sequence[j],
+				i.Depth[j],
+				classes[j],
+				strings.Join(i.Modified[j+1], ", "),
+			)
+
+			_, e = io.WriteString(depth, line)
+			if e != nil {
+				msg.WriteToFile(e, "fatal")
+			}
+		}
+	}
+
+	output = fmt.Sprintf("%s%scoverage.html", workspace, string(filepath.Separator))
+
+	view, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("cannot create the coverage view"), "error")
+	}
+	defer view.Close()
+
+	var b strings.Builder
+
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Philosopher sequence coverage</title>\n")
+	b.WriteString("<style>\n" + coverageStyle + "</style>\n</head>\n<body>\n")
+	b.WriteString("<h1>Philosopher sequence coverage</h1>\n")
+	b.WriteString("<p><span class=\"unique\">unique</span> <span class=\"razor\">razor</span> <span class=\"shared\">shared</span> <span class=\"mod\">modified</span></p>\n")
+
+	for _, i := range printSet {
+		b.WriteString(fmt.Sprintf("<h2>%s %s (%.2f%%)</h2>\n", html.EscapeString(i.Protein), html.EscapeString(i.GeneNames), i.Coverage))
+		b.WriteString(i.sequenceView())
+	}
+
+	b.WriteString("</body>\n</html>\n")
+
+	_, e = io.WriteString(view, b.String())
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+}
+
+// sequenceView prints the protein sequence in numbered lines with the residues marked by coverage class
+func (c CoverageMap) sequenceView() string {
+
+	var b strings.Builder
+
+	classes := c.residueClasses()
+
+	b.WriteString("<pre>")
+
+	for i := 0; i < len(c.Sequence); i++ {
+
+		if i%coverageLine == 0 {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(fmt.Sprintf("<span class=\"pos\">%6d</span> ", i+1))
+		} else if i%10 == 0 {
+			b.WriteString(" ")
+		}
+
+		var css []string
+		if len(classes[i]) > 0 {
+			css = append(css, classes[i])
+		}
+
+		mods, ok := c.Modified[i+1]
+		if ok {
+			css = append(css, "mod")
+		}
+
+		residue := html.EscapeString(string(c.Sequence[i]))
+
+		if len(css) == 0 {
+			b.WriteString(residue)
+			continue
+		}
+
+		b.WriteString(fmt.Sprintf("<span class=\"%s\"", strings.Join(css, " ")))
+		if ok {
+			b.WriteString(fmt.Sprintf(" title=\"%s\"", html.EscapeString(strings.Join(mods, ", "))))
+		}
+		b.WriteString(">" + residue + "</span>")
+	}
+
+	b.WriteString("</pre>\n")
+
+	return b.String()
+}
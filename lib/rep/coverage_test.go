@@ -0,0 +1,136 @@
+package rep
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"philosopher/lib/mod"
+)
+
+func TestEvidence_CoverageMaps(t *testing.T) {
+
+	phospho := mod.Modifications{Index: map[string]mod.Modification{
+		"T4": {Type: "Assigned", Name: "Phospho", AminoAcid: "T", Position: "4", MassDiff: 79.9663},
+	}}
+
+	acetyl := mod.Modifications{Index: map[string]mod.Modification{
+		"n": {Type: "Assigned", Name: "Acetyl", AminoAcid: "N-term", MassDiff: 42.0106},
+	}}
+
+	evi := Evidence{
+		PSM: PSMEvidenceList{
+			{Peptide: "PEPTIDEK", Protein: "sp|P2|B", MappedProteins: map[string]int{"sp|P1|A": 0}, IsURazor: true},
+			{Peptide: "PEPTIDEK", Protein: "sp|P1|A", IsURazor: true},
+			{Peptide: "PEPTIDEK", ModifiedPeptide: "PEPT[181]IDEK", Protein: "sp|P1|A", IsUnique: true, Modifications: phospho},
+			{Peptide: "LLMPEPTIDEK", ModifiedPeptide: "n[43]LLMPEPTIDEK", Protein: "sp|P2|B", MappedProteins: map[string]int{"sp|P1|A": 0}, Modifications: acetyl},
+		},
+		Proteins: ProteinEvidenceList{
+			{PartHeader: "sp|P1|A", Sequence: "MKPEPTIDEKLLMPEPTIDEKR"},
+		},
+	}
+
+	maps := evi.CoverageMaps()
+	if len(maps) != 1 {
+		t.Fatalf("CoverageMaps() returned %d proteins, want 1", len(maps))
+	}
+
+	want := []PeptidePosition{
+		{Peptide: "PEPTIDEK", Peptidoform: "PEPTIDEK", Start: 3, End: 10, Class: RazorCoverage, Spc: 2},
+		{Peptide: "PEPTIDEK", Peptidoform: "PEPT[181]IDEK", Start: 3, End: 10, Class: UniqueCoverage, Spc: 1, Modifications: []string{"6T(79.9663)"}},
+		{Peptide: "LLMPEPTIDEK", Peptidoform: "n[43]LLMPEPTIDEK", Start: 11, End: 21, Class: SharedCoverage, Spc: 1, Modifications: []string{"11N-term(42.0106)"}},
+		{Peptide: "PEPTIDEK", Peptidoform: "PEPTIDEK", Start: 14, End: 21, Class: RazorCoverage, Spc: 2},
+		{Peptide: "PEPTIDEK", Peptidoform: "PEPT[181]IDEK", Start: 14, End: 21, Class: UniqueCoverage, Spc: 1, Modifications: []string{"17T(79.9663)"}},
+	}
+
+	if !reflect.DeepEqual(maps[0].Peptides, want) {
+		t.Errorf("CoverageMaps() peptides = %v, want %v", maps[0].Peptides, want)
+	}
+
+	modified := map[int][]string{
+		6:  {"T(79.9663)"},
+		11: {"N-term(42.0106)"},
+		17: {"T(79.9663)"},
+	}
+
+	if !reflect.DeepEqual(maps[0].Modified, modified) {
+		t.Errorf("CoverageMaps() modified residues = %v, want %v", maps[0].Modified, modified)
+	}
+
+	classes := maps[0].residueClasses()
+
+	// the depth counts the peptide sequences, PEPTIDEK and LLMPEPTIDEK overlap from residue 14
+	tests := []struct {
+		position int
+		want     string
+		depth    int
+	}{
+		{1, "", 0},
+		{3, UniqueCoverage, 1},
+		{10, UniqueCoverage, 1},
+		{11, SharedCoverage, 1},
+		{13, SharedCoverage, 1},
+		{14, UniqueCoverage, 2},
+		{21, UniqueCoverage, 2},
+		{22, "", 0},
+	}
+
+	for _, tt := range tests {
+		if classes[tt.position-1] != tt.want {
+			t.Errorf("residue %d class = %q, want %q", tt.position, classes[tt.position-1], tt.want)
+		}
+		if maps[0].Depth[tt.position-1] != tt.depth {
+			t.Errorf("residue %d depth = %d, want %d", tt.position, maps[0].Depth[tt.position-1], tt.depth)
+		}
+	}
+}
+
+func TestEvidence_CoverageReport(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "coverage")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	evi := Evidence{
+		PSM: PSMEvidenceList{
+			{Peptide: "PEPTIDEK", Protein: "sp|P1|A", IsUnique: true},
+			{Peptide: "TIDEKLR", Protein: "sp|P1|A", IsUnique: true},
+		},
+		Proteins: ProteinEvidenceList{
+			{PartHeader: "sp|P1|A", ProteinID: "P1", GeneNames: "A", Sequence: "MPEPTIDEKLRG"},
+		},
+	}
+
+	evi.CoverageReport(dir, false)
+
+	b, e := ioutil.ReadFile(dir + string(os.PathSeparator) + "coverage_depth.tsv")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != 13 {
+		t.Fatalf("coverage_depth.tsv has %d lines, want a header and 12 residues", len(lines))
+	}
+
+	tests := []struct {
+		line int
+		want string
+	}{
+		{1, "sp|P1|A\tP1\tA\t1\tM\t0\t\t"},
+		{2, "sp|P1|A\tP1\tA\t2\tP\t1\tunique\t"},
+		{5, "sp|P1|A\tP1\tA\t5\tT\t2\tunique\t"},
+		{11, "sp|P1|A\tP1\tA\t11\tR\t1\tunique\t"},
+		{12, "sp|P1|A\tP1\tA\t12\tG\t0\t\t"},
+	}
+
+	for _, tt := range tests {
+		if lines[tt.line] != tt.want {
+			t.Errorf("coverage_depth.tsv line %d = %q, want %q", tt.line, lines[tt.line], tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"philosopher/lib/msg"
+	"philosopher/lib/sys"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmihailenco/msgpack"
+)
+
+// GeneEvidence is a gene collapsed from the proteins of its isoforms. Unique peptides map to a single gene and
+// razor peptides are the ones assigned to the gene, unique peptides included
+type GeneEvidence struct {
+	GeneName        string
+	Proteins        []string
+	ProteinIDs      []string
+	TopPepProb      float64
+	TotalPeptides   map[string]uint8
+	UniquePeptides  map[string]uint8
+	RazorPeptides   map[string]uint8
+	TotalSpC        int
+	UniqueSpC       int
+	RazorSpC        int
+	TotalIntensity  float64
+	UniqueIntensity float64
+	RazorIntensity  float64
+	IsDecoy         bool
+}
+
+// GeneEvidenceList is a list of gene evidences
+type GeneEvidenceList []GeneEvidence
+
+func (a GeneEvidenceList) Len() int           { return len(a) }
+func (a GeneEvidenceList) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a GeneEvidenceList) Less(i, j int) bool { return a[i].GeneName < a[j].GeneName }
+
+// CombinedGeneEvidence represents a gene across all data sets
+type CombinedGeneEvidence struct {
+	GeneName       string
+	Proteins       map[string]uint8
+	TopPepProb     map[string]float64
+	Spc            map[string]int
+	RazorSpc       map[string]int
+	RazorIntensity map[string]float64
+}
+
+// CombinedGeneEvidenceList is a list of Combined Gene Evidences
+type CombinedGeneEvidenceList []CombinedGeneEvidence
+
+func (a CombinedGeneEvidenceList) Len() int           { return len(a) }
+func (a CombinedGeneEvidenceList) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a CombinedGeneEvidenceList) Less(i, j int) bool { return a[i].GeneName < a[j].GeneName }
+
+// MetaGeneReport creates the gene report
+func (evi Evidence) MetaGeneReport(workspace string, hasDecoys bool) {
+
+	output := fmt.Sprintf("%s%sgene.tsv", workspace, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("cannot create gene report"), "error")
+	}
+	defer file.Close()
+
+	// building the printing set tat may or not contain decoys
+	var printSet GeneEvidenceList
+	for _, i := range evi.Genes {
+		if !hasDecoys && i.IsDecoy {
+			continue
+		}
+		printSet = append(printSet, i)
+	}
+
+	sort.Sort(printSet)
+
+	_, e = io.WriteString(file, "Gene\tProteins\tProtein IDs\tTop Peptide Probability\tTotal Peptides\tUnique Peptides\tRazor Peptides\tTotal Spectral Count\tUnique Spectral Count\tRazor Spectral Count\tTotal Intensity\tUnique Intensity\tRazor Intensity\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for _, i := range printSet {
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%.4f\t%d\t%d\t%d\t%d\t%d\t%d\t%.4f\t%.4f\t%.4f\n",
+			i.GeneName,
+			strings.Join(i.Proteins, ", "),
+			strings.Join(i.ProteinIDs, ", "),
+			i.TopPepProb,
+			len(i.TotalPeptides),
+			len(i.UniquePeptides),
+			len(i.RazorPeptides),
+			i.TotalSpC,
+			i.UniqueSpC,
+			i.RazorSpC,
+			i.TotalIntensity,
+			i.UniqueIntensity,
+			i.RazorIntensity,
+		)
+
+		_, e = io.WriteString(file, line)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+}
+
+// SerializeGenes creates an ev serial with the gene data
+func SerializeGenes(evi *GeneEvidenceList) {
+
+	b, e := msgpack.Marshal(&evi)
+	if e != nil {
+		logrus.Trace("Cannot marshal Genes data:", e)
+	}
+
+	e = ioutil.WriteFile(sys.GeneBin(), b, sys.FilePermission())
+	if e != nil {
+		logrus.Trace("Cannot serialize Genes data:", e)
+	}
+}
+
+// RestoreGenes restores the gene data, workspaces filtered without the gene
+// inference have no gene data and are left empty
+func RestoreGenes(evi *GeneEvidenceList) {
+	restoreGenes(evi, sys.GeneBin())
+}
+
+// RestoreGenesWithPath restores the gene data from a workspace
+func RestoreGenesWithPath(evi *GeneEvidenceList, p string) {
+	restoreGenes(evi, fmt.Sprintf("%s%s%s", p, string(filepath.Separator), sys.GeneBin()))
+}
+
+// restoreGenes reads a gene bin file when it exists
+func restoreGenes(evi *GeneEvidenceList, path string) {
+
+	b, e := ioutil.ReadFile(path)
+	if e != nil {
+		return
+	}
+
+	e = msgpack.Unmarshal(b, &evi)
+	if e != nil {
+		logrus.Fatal("Cannot unmarshal file:", e)
+	}
+}
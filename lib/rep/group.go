@@ -0,0 +1,150 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"philosopher/lib/cla"
+	"philosopher/lib/inf"
+	"philosopher/lib/msg"
+)
+
+// ProteinGroups builds the protein groups from the peptide to protein mappings of the PSMs
+func (evi Evidence) ProteinGroups() inf.ProteinGroupList {
+
+	var mapping = make(map[string]map[string]uint8)
+	for _, i := range evi.PSM {
+
+		if _, ok := mapping[i.Peptide]; !ok {
+			mapping[i.Peptide] = make(map[string]uint8)
+		}
+
+		mapping[i.Peptide][i.Protein] = 0
+		for j := range i.MappedProteins {
+			mapping[i.Peptide][j] = 0
+		}
+	}
+
+	var peptides = make(map[string][]string)
+	for k, v := range mapping {
+		for j := range v {
+			peptides[k] = append(peptides[k], j)
+		}
+	}
+
+	return inf.GroupProteins(peptides)
+}
+
+// UpdateProteinGroups replaces the protein groups with the ones from the native protein grouping,
+// the members of a group are numbered as subgroups in the order they are reported
+func (evi *Evidence) UpdateProteinGroups() {
+
+	type membership struct {
+		group    uint32
+		subgroup string
+		indi     []string
+	}
+
+	var members = make(map[string]membership)
+	for _, g := range evi.ProteinGroups() {
+
+		var indi []string
+		for _, i := range g.Members {
+			if i.Label == inf.IndistinguishableProtein {
+				indi = append(indi, i.Protein)
+			}
+		}
+
+		for n, i := range g.Members {
+			m := membership{group: uint32(g.Number), subgroup: siblingID(n)}
+			if i.Label == inf.LeadingProtein {
+				m.indi = indi
+			}
+			members[i.Protein] = m
+		}
+	}
+
+	for i := range evi.Proteins {
+
+		m, ok := members[evi.Proteins[i].PartHeader]
+		if !ok {
+			continue
+		}
+
+		evi.Proteins[i].ProteinGroup = m.group
+		evi.Proteins[i].ProteinSubGroup = m.subgroup
+
+		evi.Proteins[i].IndiProtein = make(map[string]uint8)
+		for _, j := range m.indi {
+			evi.Proteins[i].IndiProtein[j] = 0
+		}
+	}
+}
+
+// siblingID converts a member position into a letter code, a to z followed by aa, ab and so on
+func siblingID(n int) string {
+
+	var id string
+	for n >= 0 {
+		id = string(rune('a'+n%26)) + id
+		n = n/26 - 1
+	}
+
+	return id
+}
+
+// ProteinGroupReport writes the protein groups with the membership of each protein
+func (evi Evidence) ProteinGroupReport(workspace, decoyTag string, hasDecoys bool) {
+
+	output := fmt.Sprintf("%s%sprotein_groups.tsv", workspace, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("cannot create the protein groups report"), "error")
+	}
+	defer file.Close()
+
+	var proteinIDs = make(map[string]string)
+	var genes = make(map[string]string)
+	for _, i := range evi.Proteins {
+		proteinIDs[i.PartHeader] = i.ProteinID
+		genes[i.PartHeader] = i.GeneNames
+	}
+
+	_, e = io.WriteString(file, "Group\tSubGroup\tComponent\tProtein\tProtein ID\tGene\tMembership\tLeading Protein\tTotal Peptides\tPeptides\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for _, g := range evi.ProteinGroups() {
+
+		if !hasDecoys && cla.IsDecoy(g.Leading, decoyTag) {
+			continue
+		}
+
+		for n, i := range g.Members {
+
+			line := fmt.Sprintf("%d\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+				g.Number,
+				siblingID(n),
+				g.Component,
+				i.Protein,
+				proteinIDs[i.Protein],
+				genes[i.Protein],
+				i.Label,
+				g.Leading,
+				len(i.Peptides),
+				strings.Join(i.Peptides, ", "),
+			)
+
+			_, e = io.WriteString(file, line)
+			if e != nil {
+				msg.WriteToFile(e, "fatal")
+			}
+		}
+	}
+}
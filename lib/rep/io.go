@@ -3,6 +3,7 @@ package rep
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"philosopher/lib/sys"
@@ -25,6 +26,16 @@ func (evi *Evidence) SerializeGranular() {
 
 	// create Protein Bin
 	SerializeProteins(&evi.Proteins)
+
+	// create Peptidoform Bin, only when the peptidoform level was filtered
+	if len(evi.Peptidoforms) > 0 {
+		SerializePeptidoforms(&evi.Peptidoforms)
+	} else {
+		os.Remove(sys.PeptidoformBin())
+	}
+
+	// create Gene Bin
+	SerializeGenes(&evi.Genes)
 }
 
 // SerializePSM creates an ev serial with Evidence data
@@ -100,6 +111,12 @@ func (evi *Evidence) RestoreGranular() {
 
 	// Protein
 	RestoreProtein(&evi.Proteins)
+
+	// Peptidoform
+	RestorePeptidoforms(&evi.Peptidoforms)
+
+	// Gene
+	RestoreGenes(&evi.Genes)
 }
 
 // RestorePSM restores PSM data
@@ -176,6 +193,12 @@ func (evi *Evidence) RestoreGranularWithPath(p string) {
 
 	// Protein
 	RestoreProteinWithPath(&evi.Proteins, p)
+
+	// Peptidoform
+	RestorePeptidoformsWithPath(&evi.Peptidoforms, p)
+
+	// Gene
+	RestoreGenesWithPath(&evi.Genes, p)
 }
 
 // RestorePSMWithPath restores PSM data
@@ -107,7 +107,7 @@ func (evi *Evidence) AssembleIonReport(ion id.PepIDList, decoyTag string) {
 }
 
 // MetaIonReport reports consist on ion reporting
-func (evi Evidence) MetaIonReport(workspace, brand string, channels int, hasDecoys, hasLabels bool) {
+func (evi Evidence) MetaIonReport(workspace, brand, modification string, channels int, hasDecoys, hasLabels bool) {
 
 	var header string
 	output := fmt.Sprintf("%s%sion.tsv", workspace, string(filepath.Separator))
@@ -120,6 +120,7 @@ func (evi Evidence) MetaIonReport(workspace, brand string, channels int, hasDeco
 
 	// building the printing set tat may or not contain decoys
 	var printSet IonEvidenceList
+	var hasContaminants bool
 	for _, i := range evi.Ions {
 		// This inclusion is necessary to avoid unexistent observations from being included after using the filter --mods options
 		if i.Probability > 0 {
@@ -131,9 +132,17 @@ func (evi Evidence) MetaIonReport(workspace, brand string, channels int, hasDeco
 				printSet = append(printSet, i)
 			}
 		}
+
+		if i.IsContaminant {
+			hasContaminants = true
+		}
 	}
 
-	header = "Peptide Sequence\tModified Sequence\tPrev AA\tNext AA\tPeptide Length\tM/Z\tCharge\tObserved Mass\tProbability\tExpectation\tSpectral Count\tIntensity\tAssigned Modifications\tObserved Modifications\tProtein\tProtein ID\tEntry Name\tGene\tProtein Description\tMapped Genes\tMapped Proteins"
+	header = "Peptide Sequence\tModified Sequence\tPrev AA\tNext AA\tPeptide Length\tM/Z\tCharge\tObserved Mass\tProbability\tExpectation\tSpectral Count\tIntensity\tAssigned Modifications\tObserved Modifications\tProtein\tProtein ID\tEntry Name\tGene\tProtein Description\tMapped Genes\tMapped Proteins\tUniqueness"
+
+	if hasContaminants {
+		header += "\tIs Contaminant"
+	}
 
 	if brand == "tmt" {
 		switch channels {
@@ -161,6 +170,12 @@ func (evi Evidence) MetaIonReport(workspace, brand string, channels int, hasDeco
 		}
 	}
 
+	if len(modification) > 0 && len(brand) > 0 && len(printSet) > 0 {
+		for _, i := range LabelNames(printSet[0].Labels, channels, false) {
+			header += "\t" + i + " " + modification
+		}
+	}
+
 	header += "\n"
 
 	// verify if the structure has labels, if so, replace the original channel names by them.
@@ -240,7 +255,7 @@ func (evi Evidence) MetaIonReport(workspace, brand string, channels int, hasDeco
 		sort.Strings(assL)
 		sort.Strings(obs)
 
-		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%d\t%.4f\t%d\t%.4f\t%.4f\t%.14f\t%d\t%.4f\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s",
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%d\t%.4f\t%d\t%.4f\t%.4f\t%.14f\t%d\t%.4f\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s",
 			i.Sequence,
 			i.ModifiedSequence,
 			i.PrevAA,
@@ -262,8 +277,16 @@ func (evi Evidence) MetaIonReport(workspace, brand string, channels int, hasDeco
 			i.ProteinDescription,
 			strings.Join(mappedGenes, ","),
 			strings.Join(mappedProteins, ","),
+			i.Uniqueness,
 		)
 
+		if hasContaminants {
+			line = fmt.Sprintf("%s\t%t",
+				line,
+				i.IsContaminant,
+			)
+		}
+
 		switch channels {
 		case 4:
 			line = fmt.Sprintf("%s\t%.4f\t%.4f\t%.4f\t%.4f",
@@ -370,6 +393,12 @@ func (evi Evidence) MetaIonReport(workspace, brand string, channels int, hasDeco
 			header += ""
 		}
 
+		if len(modification) > 0 && len(brand) > 0 {
+			for _, j := range LabelIntensities(i.ModLabels, channels) {
+				line = fmt.Sprintf("%s\t%.4f", line, j)
+			}
+		}
+
 		line += "\n"
 
 		_, e = io.WriteString(file, line)
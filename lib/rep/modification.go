@@ -7,6 +7,8 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"philosopher/lib/msg"
 	"philosopher/lib/obo"
@@ -87,28 +89,44 @@ func (evi *Evidence) MapMods() {
 	}
 }
 
+// mass bins cover the open search window in fixed steps, each bin spans half a step on both sides of its center
+const (
+	massBinSize      = float64(0.1)
+	massBinWindow    = float64(0.5)
+	massBinAmplitude = float64(1000)
+)
+
+// massBinIndex returns the position of the bin centered closest to the mass, or -1 when the mass is
+// outside the binned range
+func massBinIndex(mass float64, nBins int) int {
+
+	i := int(math.Round((mass + massBinAmplitude) / massBinSize))
+
+	if i < 0 || i >= nBins {
+		return -1
+	}
+
+	return i
+}
+
 // AssembleModificationReport cretaes the modifications lists
 func (evi *Evidence) AssembleModificationReport() {
 
 	var modEvi ModificationEvidence
 
-	var massWindow = float64(0.5)
-	var binsize = float64(0.1)
-	var amplitude = float64(1000)
-
 	var bins []MassBin
 
-	nBins := (amplitude*(1/binsize) + 1) * 2
+	nBins := (massBinAmplitude*(1/massBinSize) + 1) * 2
 	for i := 0; i <= int(nBins); i++ {
 		var b MassBin
 
-		b.LowerMass = -(amplitude) - (massWindow * binsize) + (float64(i) * binsize)
+		b.LowerMass = -(massBinAmplitude) - (massBinWindow * massBinSize) + (float64(i) * massBinSize)
 		b.LowerMass = uti.Round(b.LowerMass, 5, 4)
 
-		b.HigherRight = -(amplitude) + (massWindow * binsize) + (float64(i) * binsize)
+		b.HigherRight = -(massBinAmplitude) + (massBinWindow * massBinSize) + (float64(i) * massBinSize)
 		b.HigherRight = uti.Round(b.HigherRight, 5, 4)
 
-		b.MassCenter = -(amplitude) + (float64(i) * binsize)
+		b.MassCenter = -(massBinAmplitude) + (float64(i) * massBinSize)
 		b.MassCenter = uti.Round(b.MassCenter, 5, 4)
 
 		bins = append(bins, b)
@@ -120,35 +138,33 @@ func (evi *Evidence) AssembleModificationReport() {
 		// the checklist will not allow the same PSM to be added multiple times to the
 		// same bin in case multiple identical mods are present in te sequence
 		var assignChecklist = make(map[float64]uint8)
-		var obsChecklist = make(map[float64]uint8)
-
-		for j := range bins {
-
-			// for assigned mods
-			// 0 here means something that doest not map to the pepXML header
-			// like multiple mods on n-term
-			for _, l := range evi.PSM[i].Modifications.Index {
-
-				if l.MassDiff > bins[j].LowerMass && l.MassDiff <= bins[j].HigherRight && l.MassDiff != 0 {
-					_, ok := assignChecklist[l.MassDiff]
-					if !ok {
-						if l.Type == "Assigned" {
-							bins[j].AssignedMods = append(bins[j].AssignedMods, evi.PSM[i])
-							assignChecklist[l.MassDiff] = 0
-						}
-					}
-				}
+
+		// for assigned mods
+		// 0 here means something that doest not map to the pepXML header
+		// like multiple mods on n-term
+		for _, l := range evi.PSM[i].Modifications.Index {
+
+			if l.Type != "Assigned" || l.MassDiff == 0 {
+				continue
 			}
 
-			// for delta masses
-			if evi.PSM[i].Massdiff > bins[j].LowerMass && evi.PSM[i].Massdiff <= bins[j].HigherRight {
-				_, ok := obsChecklist[evi.PSM[i].Massdiff]
-				if !ok {
-					bins[j].ObservedMods = append(bins[j].ObservedMods, evi.PSM[i])
-					obsChecklist[evi.PSM[i].Massdiff] = 0
-				}
+			if _, ok := assignChecklist[l.MassDiff]; ok {
+				continue
+			}
+
+			j := massBinIndex(l.MassDiff, len(bins))
+			if j == -1 {
+				continue
 			}
 
+			bins[j].AssignedMods = append(bins[j].AssignedMods, evi.PSM[i])
+			assignChecklist[l.MassDiff] = 0
+		}
+
+		// for delta masses
+		j := massBinIndex(evi.PSM[i].Massdiff, len(bins))
+		if j != -1 {
+			bins[j].ObservedMods = append(bins[j].ObservedMods, evi.PSM[i])
 		}
 	}
 
@@ -190,7 +206,7 @@ func (evi *Evidence) AssembleModificationReport() {
 	evi.Modifications = modEvi
 }
 
-// ModificationReport ...
+// ModificationReport reports the mass bins ranked by the number of observed PSMs, with their best explanations
 func (evi *Evidence) ModificationReport(workspace string) {
 
 	// create result file
@@ -203,21 +219,55 @@ func (evi *Evidence) ModificationReport(workspace string) {
 	}
 	defer file.Close()
 
-	line := "Mass Bin\tPSMs with Assigned Modifications\tPSMs with Observed Modifications\n"
+	line := "Mass Bin\tPSMs with Assigned Modifications\tPSMs with Observed Modifications\tLocalized Residues\tAnnotation\tAnnotation Type\tAnnotation Mass\tMass Error\tCandidate Sites\tScore\tAlternative Annotations\n"
 
 	_, e = io.WriteString(file, line)
 	if e != nil {
 		msg.WriteToFile(e, "fatal")
 	}
 
+	// the bins are kept in mass order for the histogram, rank a copy instead
+	var bins []MassBin
 	for _, i := range evi.Modifications.MassBins {
+		if len(i.AssignedMods) > 0 || len(i.ObservedMods) > 0 {
+			bins = append(bins, i)
+		}
+	}
+
+	sort.SliceStable(bins, func(i, j int) bool {
+		return len(bins[i].ObservedMods) > len(bins[j].ObservedMods)
+	})
 
-		line = fmt.Sprintf("%.4f\t%d\t%d",
+	for _, i := range bins {
+
+		line = fmt.Sprintf("%.4f\t%d\t%d\t%s",
 			i.CorrectedMass,
 			len(i.AssignedMods),
 			len(i.ObservedMods),
+			formatResidues(i.LocalizedResidues),
 		)
 
+		if len(i.Annotations) > 0 {
+
+			var alt []string
+			for _, j := range i.Annotations[1:] {
+				alt = append(alt, fmt.Sprintf("%s (%.4f)", j.Name, j.Score))
+			}
+
+			line = fmt.Sprintf("%s\t%s\t%s\t%.4f\t%.4f\t%s\t%.4f\t%s",
+				line,
+				i.Annotations[0].Name,
+				i.Annotations[0].Type,
+				i.Annotations[0].Mass,
+				i.Annotations[0].Error,
+				strings.Join(i.Annotations[0].Sites, ", "),
+				i.Annotations[0].Score,
+				strings.Join(alt, "; "),
+			)
+		} else {
+			line += "\t\t\t\t\t\t\t"
+		}
+
 		line += "\n"
 		_, e = io.WriteString(file, line)
 		if e != nil {
@@ -0,0 +1,149 @@
+package rep
+
+import (
+	"math"
+	"testing"
+
+	"philosopher/lib/mod"
+	"philosopher/lib/obo"
+)
+
+func TestMassBinIndex(t *testing.T) {
+
+	tests := []struct {
+		name string
+		mass float64
+		want int
+	}{
+		{"lower edge", -1000, 0},
+		{"zero", 0, 10000},
+		{"inside the zero bin", 0.04, 10000},
+		{"next bin", 0.06, 10001},
+		{"phospho", 79.9663, 10800},
+		{"negative", -17.0265, 9830},
+		{"below the range", -1000.2, -1},
+		{"above the range", 1000.3, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := massBinIndex(tt.mass, 20003); got != tt.want {
+				t.Errorf("massBinIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvidence_AssembleModificationReport(t *testing.T) {
+
+	oxidation := mod.Modification{Type: "Assigned", MassDiff: 15.9949}
+
+	var evi Evidence
+	evi.PSM = PSMEvidenceList{
+		{Spectrum: "a", Massdiff: 79.9663, Modifications: mod.Modifications{Index: map[string]mod.Modification{"M3": oxidation, "M5": oxidation}}},
+		{Spectrum: "b", Massdiff: 79.9701},
+		{Spectrum: "c", Massdiff: 0.0012},
+	}
+
+	evi.AssembleModificationReport()
+
+	bins := evi.Modifications.MassBins
+
+	if len(bins) != 20003 {
+		t.Fatalf("AssembleModificationReport() = %d bins, want 20003", len(bins))
+	}
+
+	tests := []struct {
+		index    int
+		center   float64
+		assigned int
+		observed int
+	}{
+		{10000, 0, 0, 1},
+		{10160, 16, 1, 0},
+		{10800, 80, 0, 2},
+	}
+
+	for _, tt := range tests {
+
+		b := bins[tt.index]
+
+		if b.MassCenter != tt.center || len(b.AssignedMods) != tt.assigned || len(b.ObservedMods) != tt.observed {
+			t.Errorf("bin %d = center %v, %d assigned, %d observed, want center %v, %d assigned, %d observed",
+				tt.index, b.MassCenter, len(b.AssignedMods), len(b.ObservedMods), tt.center, tt.assigned, tt.observed)
+		}
+	}
+}
+
+func TestEvidence_annotateMassBins(t *testing.T) {
+
+	terms := []obo.Term{
+		{Name: "Phospho", MonoIsotopicMass: 79.966331, Sites: map[string]uint8{"S": 0, "T": 0, "Y": 0}},
+		{Name: "Oxidation", MonoIsotopicMass: 15.994915, Sites: map[string]uint8{"M": 0, "W": 0}},
+	}
+
+	tests := []struct {
+		name     string
+		mass     float64
+		local    string
+		wantName string
+		wantType string
+	}{
+		{"single modification", 79.9663, "PEPsIDE", "Phospho", "UniMod"},
+		{"isotope error", 80.9697, "PEPsIDE", "Phospho +1 13C", "UniMod + Isotope Error"},
+		{"combination", 95.9612, "PEPsIDmE", "Oxidation + Phospho", "Combination"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var evi Evidence
+			evi.Modifications.MassBins = []MassBin{
+				{MassCenter: 0, CorrectedMass: 0, ObservedMods: PSMEvidenceList{{}}},
+				{MassCenter: math.Round(tt.mass*10) / 10, CorrectedMass: tt.mass, ObservedMods: PSMEvidenceList{{MSFragerLocalization: tt.local}}},
+			}
+
+			evi.annotateMassBins(terms)
+
+			if len(evi.Modifications.MassBins[0].Annotations) != 0 {
+				t.Errorf("the zero bin should not be annotated")
+			}
+
+			got := evi.Modifications.MassBins[1].Annotations
+			if len(got) == 0 {
+				t.Fatal("annotateMassBins() found no annotation")
+			}
+
+			if got[0].Name != tt.wantName || got[0].Type != tt.wantType {
+				t.Errorf("annotateMassBins() = %s (%s), want %s (%s)", got[0].Name, got[0].Type, tt.wantName, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestNewAnnotation(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		mass      float64
+		observed  float64
+		sites     []string
+		prior     float64
+		localized map[string]int
+		want      float64
+	}{
+		{"exact and consistent", 79.9663, 79.9663, []string{"S", "T", "Y"}, 1, map[string]int{"S": 3, "M": 1}, 0.875},
+		{"no localization", 79.9663, 79.9663, []string{"S", "T", "Y"}, 1, nil, 0.75},
+		{"half the tolerance", 79.9663, 79.9763, []string{"S"}, 1, map[string]int{"S": 1}, 0.75},
+		{"inconsistent combination", 95.9612, 95.9612, []string{"M"}, 0.5, map[string]int{"S": 2}, 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newAnnotation("UniMod", "test", tt.mass, tt.observed, tt.sites, tt.prior, tt.localized)
+			if math.Abs(got.Score-tt.want) > 1e-9 {
+				t.Errorf("newAnnotation() score = %v, want %v", got.Score, tt.want)
+			}
+		})
+	}
+}
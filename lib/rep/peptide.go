@@ -127,7 +127,7 @@ func (evi *Evidence) AssemblePeptideReport(pep id.PepIDList, decoyTag string) {
 }
 
 // MetaPeptideReport report consist on ion reporting
-func (evi Evidence) MetaPeptideReport(workspace, brand string, channels int, hasDecoys, hasLabels bool) {
+func (evi Evidence) MetaPeptideReport(workspace, brand, modification string, channels int, hasDecoys, hasLabels bool) {
 
 	var header string
 	output := fmt.Sprintf("%s%speptide.tsv", workspace, string(filepath.Separator))
@@ -140,6 +140,7 @@ func (evi Evidence) MetaPeptideReport(workspace, brand string, channels int, has
 
 	// building the printing set tat may or not contain decoys
 	var printSet PeptideEvidenceList
+	var hasContaminants bool
 	for _, i := range evi.Peptides {
 		if !hasDecoys {
 			if !i.IsDecoy {
@@ -148,9 +149,17 @@ func (evi Evidence) MetaPeptideReport(workspace, brand string, channels int, has
 		} else {
 			printSet = append(printSet, i)
 		}
+
+		if i.IsContaminant {
+			hasContaminants = true
+		}
 	}
 
-	header = "Peptide\tPrev AA\tNext AA\tPeptide Length\tCharges\tProbability\tSpectral Count\tIntensity\tAssigned Modifications\tObserved Modifications\tProtein\tProtein ID\tEntry Name\tGene\tProtein Description\tMapped Genes\tMapped Proteins"
+	header = "Peptide\tPrev AA\tNext AA\tPeptide Length\tCharges\tProbability\tSpectral Count\tIntensity\tAssigned Modifications\tObserved Modifications\tProtein\tProtein ID\tEntry Name\tGene\tProtein Description\tMapped Genes\tMapped Proteins\tUniqueness"
+
+	if hasContaminants {
+		header += "\tIs Contaminant"
+	}
 
 	if brand == "tmt" {
 		switch channels {
@@ -178,6 +187,12 @@ func (evi Evidence) MetaPeptideReport(workspace, brand string, channels int, has
 		}
 	}
 
+	if len(modification) > 0 && len(brand) > 0 && len(printSet) > 0 {
+		for _, i := range LabelNames(printSet[0].Labels, channels, false) {
+			header += "\t" + i + " " + modification
+		}
+	}
+
 	header += "\n"
 
 	// verify if the structure has labels, if so, replace the original channel names by them.
@@ -263,7 +278,7 @@ func (evi Evidence) MetaPeptideReport(workspace, brand string, channels int, has
 		sort.Strings(obs)
 		sort.Strings(cs)
 
-		line := fmt.Sprintf("%s\t%s\t%s\t%d\t%s\t%.4f\t%d\t%f\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s",
+		line := fmt.Sprintf("%s\t%s\t%s\t%d\t%s\t%.4f\t%d\t%f\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s",
 			i.Sequence,
 			i.PrevAA,
 			i.NextAA,
@@ -281,8 +296,16 @@ func (evi Evidence) MetaPeptideReport(workspace, brand string, channels int, has
 			i.ProteinDescription,
 			strings.Join(mappedGenes, ", "),
 			strings.Join(mappedProteins, ", "),
+			i.Uniqueness,
 		)
 
+		if hasContaminants {
+			line = fmt.Sprintf("%s\t%t",
+				line,
+				i.IsContaminant,
+			)
+		}
+
 		switch channels {
 		case 4:
 			line = fmt.Sprintf("%s\t%.4f\t%.4f\t%.4f\t%.4f",
@@ -389,6 +412,12 @@ func (evi Evidence) MetaPeptideReport(workspace, brand string, channels int, has
 			header += ""
 		}
 
+		if len(modification) > 0 && len(brand) > 0 {
+			for _, j := range LabelIntensities(i.ModLabels, channels) {
+				line = fmt.Sprintf("%s\t%.4f", line, j)
+			}
+		}
+
 		line += "\n"
 
 		_, e = io.WriteString(file, line)
@@ -0,0 +1,224 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"philosopher/lib/cla"
+	"philosopher/lib/id"
+	"philosopher/lib/mod"
+	"philosopher/lib/msg"
+	"philosopher/lib/sys"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmihailenco/msgpack"
+)
+
+// PeptidoformEvidence is a modified form of a peptide, identified by its modified sequence
+type PeptidoformEvidence struct {
+	ModifiedSequence string
+	Sequence         string
+	ChargeState      map[uint8]uint8
+	Spectra          map[string]uint8
+	Protein          string
+	ProteinID        string
+	GeneName         string
+	MappedProteins   map[string]int
+	Intensity        float64
+	Probability      float64
+	IsUnique         bool
+	IsURazor         bool
+	IsDecoy          bool
+	Modifications    mod.Modifications
+}
+
+// PeptidoformEvidenceList is a list of peptidoform evidences
+type PeptidoformEvidenceList []PeptidoformEvidence
+
+func (a PeptidoformEvidenceList) Len() int      { return len(a) }
+func (a PeptidoformEvidenceList) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a PeptidoformEvidenceList) Less(i, j int) bool {
+	if a[i].Sequence != a[j].Sequence {
+		return a[i].Sequence < a[j].Sequence
+	}
+	return a[i].ModifiedSequence < a[j].ModifiedSequence
+}
+
+// Peptidoform returns the modified sequence of the PSM, unmodified peptides are identified by their stripped sequence
+func (p PSMEvidence) Peptidoform() string {
+
+	if len(p.ModifiedPeptide) > 0 {
+		return p.ModifiedPeptide
+	}
+
+	return p.Peptide
+}
+
+// AssemblePeptidoformReport collects the PSMs of the peptidoforms accepted by the peptidoform FDR
+func (evi *Evidence) AssemblePeptidoformReport(forms id.PepIDList, decoyTag string) {
+
+	var formMap = make(map[string]*PeptidoformEvidence)
+
+	for _, i := range forms {
+		formMap[i.Peptidoform()] = &PeptidoformEvidence{
+			ModifiedSequence: i.Peptidoform(),
+			Sequence:         i.Peptide,
+			ChargeState:      make(map[uint8]uint8),
+			Spectra:          make(map[string]uint8),
+			MappedProteins:   make(map[string]int),
+			Modifications:    mod.Modifications{Index: make(map[string]mod.Modification)},
+			IsDecoy:          cla.IsDecoyPSM(i, decoyTag),
+		}
+	}
+
+	for _, i := range evi.PSM {
+
+		f, ok := formMap[i.Peptidoform()]
+		if !ok {
+			continue
+		}
+
+		f.ChargeState[i.AssumedCharge] = 0
+		f.Spectra[i.Spectrum] = 0
+
+		f.Protein = i.Protein
+		f.ProteinID = i.ProteinID
+		f.GeneName = i.GeneName
+		f.IsUnique = i.IsUnique
+		f.IsURazor = i.IsURazor
+
+		for j := range i.MappedProteins {
+			f.MappedProteins[j] = 0
+		}
+
+		for k, v := range i.Modifications.Index {
+			f.Modifications.Index[k] = v
+		}
+
+		if i.Intensity > f.Intensity {
+			f.Intensity = i.Intensity
+		}
+
+		if i.Probability > f.Probability {
+			f.Probability = i.Probability
+		}
+	}
+
+	// peptidoforms without PSMs left after the protein filtering are dropped
+	var list PeptidoformEvidenceList
+	for _, v := range formMap {
+		if len(v.Spectra) > 0 {
+			list = append(list, *v)
+		}
+	}
+
+	sort.Sort(list)
+	evi.Peptidoforms = list
+}
+
+// MetaPeptidoformReport creates the peptidoform report
+func (evi Evidence) MetaPeptidoformReport(workspace string, hasDecoys bool) {
+
+	output := fmt.Sprintf("%s%speptidoform.tsv", workspace, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("peptidoform output file"), "fatal")
+	}
+	defer file.Close()
+
+	_, e = io.WriteString(file, "Modified Peptide\tPeptide\tCharges\tProbability\tSpectral Count\tIntensity\tAssigned Modifications\tObserved Modifications\tProtein\tProtein ID\tGene\tMapped Proteins\tIs Unique\n")
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for _, i := range evi.Peptidoforms {
+
+		if !hasDecoys && i.IsDecoy {
+			continue
+		}
+
+		var charges []string
+		for j := range i.ChargeState {
+			charges = append(charges, strconv.Itoa(int(j)))
+		}
+		sort.Strings(charges)
+
+		var mapped []string
+		for j := range i.MappedProteins {
+			mapped = append(mapped, j)
+		}
+		sort.Strings(mapped)
+
+		assL, obs := getModsList(i.Modifications.Index)
+		sort.Strings(assL)
+		sort.Strings(obs)
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%.4f\t%d\t%f\t%s\t%s\t%s\t%s\t%s\t%s\t%t\n",
+			i.ModifiedSequence,
+			i.Sequence,
+			strings.Join(charges, ", "),
+			i.Probability,
+			len(i.Spectra),
+			i.Intensity,
+			strings.Join(assL, ", "),
+			strings.Join(obs, ", "),
+			i.Protein,
+			i.ProteinID,
+			i.GeneName,
+			strings.Join(mapped, ", "),
+			i.IsUnique,
+		)
+
+		_, e = io.WriteString(file, line)
+		if e != nil {
+			msg.WriteToFile(e, "fatal")
+		}
+	}
+}
+
+// SerializePeptidoforms creates an ev serial with the peptidoform data
+func SerializePeptidoforms(evi *PeptidoformEvidenceList) {
+
+	b, e := msgpack.Marshal(&evi)
+	if e != nil {
+		logrus.Trace("Cannot marshal Peptidoforms data:", e)
+	}
+
+	e = ioutil.WriteFile(sys.PeptidoformBin(), b, sys.FilePermission())
+	if e != nil {
+		logrus.Trace("Cannot serialize Peptidoforms data:", e)
+	}
+}
+
+// RestorePeptidoforms restores the peptidoform data, workspaces filtered without the peptidoform
+// level are left empty
+func RestorePeptidoforms(evi *PeptidoformEvidenceList) {
+	restorePeptidoforms(evi, sys.PeptidoformBin())
+}
+
+// RestorePeptidoformsWithPath restores the peptidoform data from a workspace
+func RestorePeptidoformsWithPath(evi *PeptidoformEvidenceList, p string) {
+	restorePeptidoforms(evi, fmt.Sprintf("%s%s%s", p, string(filepath.Separator), sys.PeptidoformBin()))
+}
+
+// restorePeptidoforms reads a peptidoform bin file when it exists
+func restorePeptidoforms(evi *PeptidoformEvidenceList, path string) {
+
+	b, e := ioutil.ReadFile(path)
+	if e != nil {
+		return
+	}
+
+	e = msgpack.Unmarshal(b, &evi)
+	if e != nil {
+		logrus.Fatal("Cannot unmarshal file:", e)
+	}
+}
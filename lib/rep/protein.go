@@ -10,14 +10,16 @@ import (
 	"strconv"
 	"strings"
 
+	"philosopher/lib/cla"
 	"philosopher/lib/dat"
+	"philosopher/lib/fas"
 	"philosopher/lib/id"
 	"philosopher/lib/mod"
 	"philosopher/lib/msg"
 )
 
 // AssembleProteinReport creates the post processed protein strcuture
-func (evi *Evidence) AssembleProteinReport(pro id.ProtIDList, weight float64, decoyTag string) {
+func (evi *Evidence) AssembleProteinReport(pro id.ProtIDList, records []dat.Record, weight float64, decoyTag string) {
 
 	var list ProteinEvidenceList
 	var protMods = make(map[string][]mod.Modification)
@@ -56,7 +58,7 @@ func (evi *Evidence) AssembleProteinReport(pro id.ProtIDList, weight float64, de
 		rep.UniquePeptides = make(map[string]int)
 		rep.URazorPeptides = make(map[string]int)
 
-		if strings.HasPrefix(i.ProteinName, decoyTag) {
+		if cla.IsDecoy(i.ProteinName, decoyTag) {
 			rep.IsDecoy = true
 		} else {
 			rep.IsDecoy = false
@@ -176,16 +178,13 @@ func (evi *Evidence) AssembleProteinReport(pro id.ProtIDList, weight float64, de
 		list = append(list, rep)
 	}
 
-	var dtb dat.Base
-	dtb.Restore()
-
-	if len(dtb.Records) < 1 {
+	if len(records) < 1 {
 		msg.DatabaseNotFound(errors.New(""), "fatal")
 	}
 
 	// fix the name sand headers and pull database information into protein report
 	for i := range list {
-		for _, j := range dtb.Records {
+		for _, j := range records {
 
 			desc := strings.Replace(j.Description, "|", " ", -1)
 
@@ -203,6 +202,8 @@ func (evi *Evidence) AssembleProteinReport(pro id.ProtIDList, weight float64, de
 					list[i].Sequence = j.Sequence
 					list[i].ProteinName = j.ProteinName
 					list[i].Organism = j.Organism
+					list[i].Class = j.Class
+					list[i].IsContaminant = j.IsContaminant
 
 					// uniprot entries have the description on ProteinName
 					if len(j.Description) < 1 {
@@ -230,7 +231,7 @@ func (evi *Evidence) AssembleProteinReport(pro id.ProtIDList, weight float64, de
 }
 
 // MetaProteinReport creates the TSV Protein report
-func (evi Evidence) MetaProteinReport(workspace, brand string, channels int, hasDecoys, hasRazor, uniqueOnly, hasLabels bool) {
+func (evi Evidence) MetaProteinReport(workspace, brand, modification string, channels int, hasDecoys, hasRazor, uniqueOnly, hasLabels bool) {
 
 	var header string
 	output := fmt.Sprintf("%s%sprotein.tsv", workspace, string(filepath.Separator))
@@ -244,6 +245,8 @@ func (evi Evidence) MetaProteinReport(workspace, brand string, channels int, has
 
 	// building the printing set tat may or not contain decoys
 	var printSet ProteinEvidenceList
+	var hasClasses bool
+	var hasContaminants bool
 	for _, i := range evi.Proteins {
 		if !hasDecoys {
 			if !i.IsDecoy {
@@ -252,10 +255,26 @@ func (evi Evidence) MetaProteinReport(workspace, brand string, channels int, has
 		} else {
 			printSet = append(printSet, i)
 		}
+
+		if i.Class == dat.VariantClass || i.Class == dat.NovelClass {
+			hasClasses = true
+		}
+
+		if i.IsContaminant {
+			hasContaminants = true
+		}
 	}
 
 	header = "Group\tSubGroup\tProtein\tProtein ID\tEntry Name\tGene\tLength\tPercent Coverage\tOrganism\tProtein Description\tProtein Existence\tProtein Probability\tTop Peptide Probability\tTotal Peptides\tUnique Peptides\tRazor Peptides\tTotal Spectral Count\tUnique Spectral Count\tRazor Spectral Count\tTotal Intensity\tUnique Intensity\tRazor Intensity\tRazor Assigned Modifications\tRazor Observed Modifications\tIndistinguishable Proteins"
 
+	if hasClasses {
+		header += "\tSequence Class"
+	}
+
+	if hasContaminants {
+		header += "\tIs Contaminant"
+	}
+
 	if brand == "tmt" {
 		switch channels {
 		case 6:
@@ -282,6 +301,12 @@ func (evi Evidence) MetaProteinReport(workspace, brand string, channels int, has
 		}
 	}
 
+	if len(modification) > 0 && len(brand) > 0 && len(printSet) > 0 {
+		for _, i := range LabelNames(printSet[0].UniqueLabels, channels, false) {
+			header += "\t" + i + " " + modification
+		}
+	}
+
 	header += "\n"
 
 	// verify if the structure has labels, if so, replace the original channel names by them.
@@ -441,6 +466,20 @@ func (evi Evidence) MetaProteinReport(workspace, brand string, channels int, has
 			strings.Join(ip, ", "),   // Indistinguishable Proteins
 		)
 
+		if hasClasses {
+			line = fmt.Sprintf("%s\t%s",
+				line,
+				i.Class,
+			)
+		}
+
+		if hasContaminants {
+			line = fmt.Sprintf("%s\t%t",
+				line,
+				i.IsContaminant,
+			)
+		}
+
 		switch channels {
 		case 4:
 			line = fmt.Sprintf("%s\t%.4f\t%.4f\t%.4f\t%.4f",
@@ -547,6 +586,16 @@ func (evi Evidence) MetaProteinReport(workspace, brand string, channels int, has
 			header += ""
 		}
 
+		if len(modification) > 0 && len(brand) > 0 {
+			modIntensities := i.ModURazorLabels
+			if uniqueOnly || !hasRazor {
+				modIntensities = i.ModUniqueLabels
+			}
+			for _, j := range LabelIntensities(modIntensities, channels) {
+				line = fmt.Sprintf("%s\t%.4f", line, j)
+			}
+		}
+
 		line += "\n"
 
 		_, e = io.WriteString(file, line)
@@ -580,12 +629,16 @@ func (evi *Evidence) ProteinFastaReport(workspace string, hasDecoys bool) {
 		}
 	}
 
+	w := fas.NewWriter(file)
 	for _, i := range printSet {
-		header := i.OriginalHeader
-		line := ">" + header + "\n" + i.Sequence + "\n"
-		_, e = io.WriteString(file, line)
+		e = w.Write(fas.Entry{Header: i.OriginalHeader, Sequence: i.Sequence})
 		if e != nil {
 			msg.WriteToFile(e, "fatal")
 		}
 	}
+
+	e = w.Flush()
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
 }
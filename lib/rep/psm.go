@@ -20,17 +20,14 @@ import (
 )
 
 // AssemblePSMReport creates the PSM structure for reporting
-func (evi *Evidence) AssemblePSMReport(pep id.PepIDList, decoyTag string) {
+func (evi *Evidence) AssemblePSMReport(pep id.PepIDList, records []dat.Record, decoyTag string) {
 
 	var list PSMEvidenceList
 
 	// collect database information
-	var dtb dat.Base
-	dtb.Restore()
-
 	var genes = make(map[string]string)
 	var ptid = make(map[string]string)
-	for _, j := range dtb.Records {
+	for _, j := range records {
 		genes[j.PartHeader] = j.GeneNames
 		ptid[j.PartHeader] = j.ID
 	}
@@ -140,6 +137,8 @@ func (evi Evidence) MetaPSMReport(workspace, brand string, channels int, hasDeco
 	var modList []string
 	var hasCompVolt bool
 	var hasPurity bool
+	var hasClasses bool
+	var hasContaminants bool
 
 	output := fmt.Sprintf("%s%spsm.tsv", workspace, string(filepath.Separator))
 
@@ -190,6 +189,14 @@ func (evi Evidence) MetaPSMReport(workspace, brand string, channels int, hasDeco
 			hasLoc = true
 		}
 
+		if evi.PSM[i].Class == dat.VariantClass || evi.PSM[i].Class == dat.NovelClass {
+			hasClasses = true
+		}
+
+		if evi.PSM[i].IsContaminant {
+			hasContaminants = true
+		}
+
 	}
 
 	for k := range modMap {
@@ -259,6 +266,16 @@ func (evi Evidence) MetaPSMReport(workspace, brand string, channels int, hasDeco
 		}
 	}
 
+	header += "\tUniqueness"
+
+	if hasClasses {
+		header += "\tSequence Class"
+	}
+
+	if hasContaminants {
+		header += "\tIs Contaminant"
+	}
+
 	header += "\n"
 
 	// verify if the structure has labels, if so, replace the original channel names by them.
@@ -560,6 +577,25 @@ func (evi Evidence) MetaPSMReport(workspace, brand string, channels int, hasDeco
 			}
 		}
 
+		line = fmt.Sprintf("%s\t%s",
+			line,
+			i.Uniqueness,
+		)
+
+		if hasClasses {
+			line = fmt.Sprintf("%s\t%s",
+				line,
+				i.Class,
+			)
+		}
+
+		if hasContaminants {
+			line = fmt.Sprintf("%s\t%t",
+				line,
+				i.IsContaminant,
+			)
+		}
+
 		line += "\n"
 
 		_, e = io.WriteString(file, line)
@@ -0,0 +1,62 @@
+package rep
+
+import (
+	"io/ioutil"
+	"os"
+	"philosopher/lib/dat"
+	"strings"
+	"testing"
+)
+
+func TestEvidence_MetaPSMReport(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "psm")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	evi := Evidence{PSM: PSMEvidenceList{
+		{Spectrum: "runA.00001.00001.2", Peptide: "PEPTIDE", Protein: "sp|P1|A", Uniqueness: "unique", Class: dat.VariantClass, IsContaminant: true},
+	}}
+	evi.PSM[0].Labels.Channel1.Intensity = 100
+
+	evi.MetaPSMReport(dir, "tmt", 6, false, false, false, false, false)
+
+	b, e := ioutil.ReadFile(dir + string(os.PathSeparator) + "psm.tsv")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("MetaPSMReport() wrote %d lines, want 2", len(lines))
+	}
+
+	header := strings.Split(lines[0], "\t")
+	row := strings.Split(lines[1], "\t")
+
+	if len(header) != len(row) {
+		t.Fatalf("MetaPSMReport() header has %d columns and the row %d", len(header), len(row))
+	}
+
+	// the new columns follow the quantification channels
+	tests := []struct {
+		column int
+		name   string
+		value  string
+	}{
+		{len(header) - 4, "Channel 131N", "0.0000"},
+		{len(header) - 3, "Uniqueness", "unique"},
+		{len(header) - 2, "Sequence Class", dat.VariantClass},
+		{len(header) - 1, "Is Contaminant", "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if header[tt.column] != tt.name || row[tt.column] != tt.value {
+				t.Errorf("column %d = %s: %s, want %s: %s", tt.column, header[tt.column], row[tt.column], tt.name, tt.value)
+			}
+		})
+	}
+}
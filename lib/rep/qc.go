@@ -0,0 +1,691 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"philosopher/lib/cla"
+	"philosopher/lib/id"
+	"philosopher/lib/msg"
+	"philosopher/lib/mzn"
+	"philosopher/lib/sys"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmihailenco/msgpack"
+)
+
+// chart dimensions for the QC report
+const (
+	chartWidth  = 640
+	chartHeight = 320
+	chartMargin = 50
+)
+
+// qcStyle is embedded in the QC report so it can be opened without network access
+const qcStyle = `body { font-family: Helvetica, Arial, sans-serif; margin: 20px; color: #222; }
+h1 { font-size: 22px; }
+h2 { font-size: 17px; margin-top: 30px; border-bottom: 1px solid #ccc; }
+table { border-collapse: collapse; font-size: 13px; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th { background: #f0f0f0; }
+.charts { display: flex; flex-wrap: wrap; }
+.chart { margin: 10px; }
+.chart text { font-size: 11px; }
+.chart .title { font-size: 13px; font-weight: bold; }
+.bar { fill: #4c78a8; }
+.bar:hover { fill: #f58518; }
+.line { fill: none; stroke: #4c78a8; stroke-width: 2; }
+.box { fill: #9ecae9; stroke: #4c78a8; }
+.whisker { stroke: #4c78a8; }
+.axis { stroke: #555; }
+.threshold { stroke: #e45756; stroke-dasharray: 4; }
+`
+
+// qcScript shows the value of the bars hovered by the mouse
+const qcScript = `document.querySelectorAll('.bar').forEach(function (b) {
+  b.addEventListener('mouseover', function () {
+    var t = b.closest('svg').querySelector('.hover');
+    if (t) { t.textContent = b.getAttribute('data-label') + ': ' + b.getAttribute('data-value'); }
+  });
+});
+`
+
+// QCScore is a PSM of the unfiltered search results, the score is oriented so that higher is better
+type QCScore struct {
+	Score   float64
+	IsDecoy bool
+}
+
+// QCScoreList is the unfiltered list of target and decoy PSMs ranked from the best to the worst score
+type QCScoreList []QCScore
+
+// NewQCScores ranks the unfiltered PSMs by the score used for the filter
+func NewQCScores(psm id.PepIDList, score id.Score, decoyTag string) QCScoreList {
+
+	var list = make(QCScoreList, 0, len(psm))
+
+	for _, i := range psm {
+
+		v := score.Value(i)
+		if !score.HigherBetter {
+			v = -v
+		}
+
+		list = append(list, QCScore{Score: v, IsDecoy: cla.IsDecoyPSM(i, decoyTag)})
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Score > list[j].Score
+	})
+
+	return list
+}
+
+// SerializeQCScores saves the unfiltered PSM scores for the QC report
+func SerializeQCScores(s *QCScoreList) {
+
+	b, e := msgpack.Marshal(&s)
+	if e != nil {
+		logrus.Trace("Cannot marshal QC data:", e)
+	}
+
+	e = ioutil.WriteFile(sys.QCBin(), b, sys.FilePermission())
+	if e != nil {
+		logrus.Trace("Cannot serialize QC data:", e)
+	}
+}
+
+// RestoreQCScores restores the unfiltered PSM scores, workspaces filtered before they were saved are left empty
+func RestoreQCScores(s *QCScoreList) {
+
+	b, e := ioutil.ReadFile(sys.QCBin())
+	if e != nil {
+		return
+	}
+
+	e = msgpack.Unmarshal(b, &s)
+	if e != nil {
+		logrus.Fatal("Cannot unmarshal file:", e)
+	}
+}
+
+// QCReport creates a self-contained HTML file with the quality control metrics of the workspace. The scans
+// hold the number of MS2 spectra of each run, the identification rate is only reported for the runs listed there,
+// and the FDR curve is drawn from the unfiltered PSM scores
+func (evi *Evidence) QCReport(workspace string, channels int, psmFDR float64, scans map[string]int, scores QCScoreList) {
+
+	output := fmt.Sprintf("%s%sqc.html", workspace, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("could not create the QC report"), "fatal")
+	}
+	defer file.Close()
+
+	var b strings.Builder
+
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Philosopher QC report</title>\n")
+	b.WriteString("<style>\n" + qcStyle + "</style>\n</head>\n<body>\n")
+	b.WriteString("<h1>Philosopher QC report</h1>\n")
+
+	summary := evi.qcRunSummary(scans)
+
+	// identifications per run
+	b.WriteString("<h2>Identifications</h2>\n")
+	b.WriteString(evi.qcRunTable(summary))
+
+	var runs []string
+	var runPSMs []float64
+	var runMissing []float64
+	var rateRuns []string
+	var runRates []float64
+	for _, i := range summary {
+		runs = append(runs, i.Name)
+		runPSMs = append(runPSMs, float64(i.PSMs))
+		runMissing = append(runMissing, i.MissingRate())
+		if i.Scans > 0 {
+			rateRuns = append(rateRuns, i.Name)
+			runRates = append(runRates, i.IdentificationRate())
+		}
+	}
+
+	b.WriteString("<div class=\"charts\">\n")
+	b.WriteString(svgBarChart("Target PSMs per run", runs, runPSMs, "run", "PSMs"))
+	if len(rateRuns) > 0 {
+		b.WriteString(svgBarChart("Identification rate per run", rateRuns, runRates, "run", "% of MS2 scans"))
+	}
+	if len(scores) > 0 {
+		b.WriteString(svgLineChart("FDR curve", qcFDRCurve(scores), psmFDR, "accepted target PSMs", "FDR"))
+	} else {
+		logrus.Warn("the unfiltered PSM scores were not found, filter the workspace again to draw the FDR curve")
+	}
+	b.WriteString("</div>\n")
+
+	// mass accuracy and chromatography
+	var ppm []float64
+	var rt []float64
+	var charges = make(map[string]float64)
+	var missed = make(map[string]float64)
+
+	for _, i := range evi.PSM {
+
+		if i.IsDecoy {
+			continue
+		}
+
+		if math.Abs(i.Massdiff) < 0.5 && i.CalcNeutralPepMass > 0 {
+			ppm = append(ppm, (i.Massdiff/i.CalcNeutralPepMass)*1e6)
+		}
+
+		rt = append(rt, i.RetentionTime/60)
+		charges[strconv.Itoa(int(i.AssumedCharge))]++
+		missed[strconv.Itoa(i.NumberOfMissedCleavages)]++
+	}
+
+	b.WriteString("<h2>Mass accuracy and chromatography</h2>\n<div class=\"charts\">\n")
+	l, v := histogram(ppm, -20, 20, 40)
+	b.WriteString(svgBarChart("Precursor mass error", l, v, "ppm", "PSMs"))
+	l, v = histogram(rt, 0, maxValue(rt), 40)
+	b.WriteString(svgBarChart("Retention time", l, v, "minutes", "PSMs"))
+	b.WriteString("</div>\n")
+
+	// peptide properties
+	b.WriteString("<h2>Peptide properties</h2>\n<div class=\"charts\">\n")
+	l, v = countsToBars(charges)
+	b.WriteString(svgBarChart("Charge states", l, v, "charge", "PSMs"))
+	l, v = countsToBars(missed)
+	b.WriteString(svgBarChart("Missed cleavages", l, v, "missed cleavages", "PSMs"))
+	b.WriteString("</div>\n")
+
+	// quantification
+	b.WriteString("<h2>Quantification</h2>\n<div class=\"charts\">\n")
+	b.WriteString(svgBarChart("Missing MS1 intensities across runs", runs, runMissing, "run", "% of quantified peptides"))
+
+	if channels > 0 && len(evi.PSM) > 0 {
+
+		var names = LabelNames(evi.PSM[0].Labels, channels, true)
+		var values = make([][]float64, len(names))
+
+		for _, i := range evi.PSM {
+			if i.IsDecoy {
+				continue
+			}
+			for j, k := range LabelIntensities(i.Labels, channels) {
+				if k > 0 {
+					values[j] = append(values[j], math.Log2(k))
+				}
+			}
+		}
+
+		b.WriteString(svgBoxPlot("Reporter ion intensities", names, values, "log2 intensity"))
+	}
+
+	b.WriteString("</div>\n")
+
+	b.WriteString("<script>\n" + qcScript + "</script>\n</body>\n</html>\n")
+
+	_, e = io.WriteString(file, b.String())
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+}
+
+// qcRun holds the identification numbers for a single run
+type qcRun struct {
+	Name       string
+	Scans      int
+	PSMs       int
+	Decoys     int
+	Peptides   int
+	Quantified int
+	Missing    int
+}
+
+// IdentificationRate is the percentage of MS2 scans identified by a target PSM
+func (r qcRun) IdentificationRate() float64 {
+
+	if r.Scans == 0 {
+		return 0
+	}
+
+	return 100 * float64(r.PSMs) / float64(r.Scans)
+}
+
+// MissingRate is the percentage of the peptides quantified in the experiment that have no intensity in the run
+func (r qcRun) MissingRate() float64 {
+
+	if r.Quantified == 0 {
+		return 0
+	}
+
+	return 100 * float64(r.Missing) / float64(r.Quantified)
+}
+
+// qcRunSummary groups the PSMs by the run they come from. A peptide with an MS1 intensity in any run is
+// expected in every run, and counts as missing in the runs where none of its PSMs has an intensity
+func (evi *Evidence) qcRunSummary(scans map[string]int) []qcRun {
+
+	var runMap = make(map[string]*qcRun)
+	var peptides = make(map[string]map[string]uint8)
+	var quantified = make(map[string]map[string]uint8)
+	var experiment = make(map[string]uint8)
+
+	for _, i := range evi.PSM {
+
+		name := strings.Split(i.Spectrum, ".")[0]
+
+		r, ok := runMap[name]
+		if !ok {
+			r = &qcRun{Name: name, Scans: scans[name]}
+			runMap[name] = r
+			peptides[name] = make(map[string]uint8)
+			quantified[name] = make(map[string]uint8)
+		}
+
+		if i.IsDecoy {
+			r.Decoys++
+			continue
+		}
+
+		r.PSMs++
+		peptides[name][i.Peptide] = 0
+
+		if i.Intensity > 0 {
+			quantified[name][i.Peptide] = 0
+			experiment[i.Peptide] = 0
+		}
+	}
+
+	var list []qcRun
+	for k, v := range runMap {
+
+		v.Peptides = len(peptides[k])
+		v.Quantified = len(experiment)
+
+		for i := range experiment {
+			if _, ok := quantified[k][i]; !ok {
+				v.Missing++
+			}
+		}
+
+		list = append(list, *v)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list
+}
+
+// qcRunTable prints the identification numbers per run as an HTML table
+func (evi *Evidence) qcRunTable(summary []qcRun) string {
+
+	var b strings.Builder
+
+	b.WriteString("<table>\n<tr><th>Run</th><th>MS2 scans</th><th>Target PSMs</th><th>Identification rate</th><th>Decoy PSMs</th><th>Peptides</th><th>Missing intensities</th></tr>\n")
+
+	var total qcRun
+	for _, i := range summary {
+
+		b.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%d</td><td>%s</td><td>%d</td><td>%d</td><td>%.1f %%</td></tr>\n",
+			html.EscapeString(i.Name), qcScans(i), i.PSMs, qcRate(i), i.Decoys, i.Peptides, i.MissingRate()))
+
+		total.Scans += i.Scans
+		total.PSMs += i.PSMs
+		total.Decoys += i.Decoys
+		total.Quantified += i.Quantified
+		total.Missing += i.Missing
+	}
+
+	// the rate is only defined when every run has its scans
+	for _, i := range summary {
+		if i.Scans == 0 {
+			total.Scans = 0
+			break
+		}
+	}
+
+	b.WriteString(fmt.Sprintf("<tr><th>Total</th><th>%s</th><th>%d</th><th>%s</th><th>%d</th><th>%d</th><th>%.1f %%</th></tr>\n</table>\n",
+		qcScans(total), total.PSMs, qcRate(total), total.Decoys, len(evi.Peptides), total.MissingRate()))
+
+	return b.String()
+}
+
+// qcScans prints the number of MS2 scans of a run, or NA when the spectra were not found
+func qcScans(r qcRun) string {
+	if r.Scans == 0 {
+		return "NA"
+	}
+	return strconv.Itoa(r.Scans)
+}
+
+// qcRate prints the identification rate of a run, or NA when the spectra were not found
+func qcRate(r qcRun) string {
+	if r.Scans == 0 {
+		return "NA"
+	}
+	return fmt.Sprintf("%.1f %%", r.IdentificationRate())
+}
+
+// MS2ScanCounts counts the MS2 spectra of each run from the mzML files in a directory,
+// the runs without a spectra file are left out
+func MS2ScanCounts(dir string, runs []string) map[string]int {
+
+	var scans = make(map[string]int)
+
+	for _, i := range runs {
+
+		fileName := fmt.Sprintf("%s%s%s.mzML", dir, string(filepath.Separator), i)
+		if _, e := os.Stat(fileName); e != nil {
+			continue
+		}
+
+		var mz mzn.MsData
+		mz.Read(fileName)
+
+		for _, j := range mz.Spectra {
+			if j.Level == "2" {
+				scans[i]++
+			}
+		}
+	}
+
+	return scans
+}
+
+// RunNames lists the runs the PSMs come from
+func (evi *Evidence) RunNames() []string {
+
+	var runs = make(map[string]uint8)
+	for _, i := range evi.PSM {
+		runs[strings.Split(i.Spectrum, ".")[0]] = 0
+	}
+
+	var list []string
+	for k := range runs {
+		list = append(list, k)
+	}
+
+	sort.Strings(list)
+
+	return list
+}
+
+// qcFDRCurve calculates the decoy based FDR for each number of accepted targets along the ranked PSMs. The FDR
+// only falls between two decoys, so the points are kept around each decoy and at the end of the list
+func qcFDRCurve(scores QCScoreList) [][2]float64 {
+
+	var curve [][2]float64
+	var targets, decoys float64
+
+	for n, i := range scores {
+		if i.IsDecoy {
+			decoys++
+		} else {
+			targets++
+		}
+
+		last := n == len(scores)-1
+		if targets > 0 && (i.IsDecoy || last || scores[n+1].IsDecoy) {
+			curve = append(curve, [2]float64{targets, decoys / targets})
+		}
+	}
+
+	return curve
+}
+
+// histogram distributes the values into equally sized bins
+func histogram(values []float64, min, max float64, bins int) ([]string, []float64) {
+
+	var labels = make([]string, bins)
+	var counts = make([]float64, bins)
+
+	if max <= min {
+		max = min + 1
+	}
+
+	width := (max - min) / float64(bins)
+
+	for i := range labels {
+		labels[i] = fmt.Sprintf("%.2f", min+width*float64(i))
+	}
+
+	for _, i := range values {
+		if i < min || i > max {
+			continue
+		}
+		idx := int((i - min) / width)
+		if idx >= bins {
+			idx = bins - 1
+		}
+		counts[idx]++
+	}
+
+	return labels, counts
+}
+
+// countsToBars sorts a count map by its numeric keys
+func countsToBars(counts map[string]float64) ([]string, []float64) {
+
+	var keys []string
+	for k := range counts {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, _ := strconv.Atoi(keys[i])
+		b, _ := strconv.Atoi(keys[j])
+		return a < b
+	})
+
+	var values []float64
+	for _, i := range keys {
+		values = append(values, counts[i])
+	}
+
+	return keys, values
+}
+
+func maxValue(values []float64) float64 {
+	var max float64
+	for _, i := range values {
+		if i > max {
+			max = i
+		}
+	}
+	return max
+}
+
+// svgFrame opens an SVG chart with the title and axes
+func svgFrame(b *strings.Builder, title, xLabel, yLabel string, yMax float64) {
+
+	b.WriteString(fmt.Sprintf("<svg class=\"chart\" width=\"%d\" height=\"%d\">\n", chartWidth, chartHeight))
+	b.WriteString(fmt.Sprintf("<text class=\"title\" x=\"%d\" y=\"18\">%s</text>\n", chartMargin, html.EscapeString(title)))
+	b.WriteString(fmt.Sprintf("<text class=\"hover\" x=\"%d\" y=\"18\" text-anchor=\"end\"></text>\n", chartWidth-10))
+	b.WriteString(fmt.Sprintf("<line class=\"axis\" x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\"/>\n", chartMargin, chartHeight-chartMargin, chartWidth-10, chartHeight-chartMargin))
+	b.WriteString(fmt.Sprintf("<line class=\"axis\" x1=\"%d\" y1=\"30\" x2=\"%d\" y2=\"%d\"/>\n", chartMargin, chartMargin, chartHeight-chartMargin))
+	b.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%s</text>\n", chartWidth/2, chartHeight-10, html.EscapeString(xLabel)))
+	b.WriteString(fmt.Sprintf("<text x=\"12\" y=\"%d\" transform=\"rotate(-90 12 %d)\" text-anchor=\"middle\">%s</text>\n", chartHeight/2, chartHeight/2, html.EscapeString(yLabel)))
+	b.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"34\" text-anchor=\"end\">%s</text>\n", chartMargin-4, formatTick(yMax)))
+	b.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"%d\" text-anchor=\"end\">0</text>\n", chartMargin-4, chartHeight-chartMargin))
+}
+
+func formatTick(v float64) string {
+	if v >= 100 || v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+// svgBarChart draws a bar chart as an inline SVG element
+func svgBarChart(title string, labels []string, values []float64, xLabel, yLabel string) string {
+
+	var b strings.Builder
+
+	yMax := maxValue(values)
+	if yMax == 0 {
+		yMax = 1
+	}
+
+	svgFrame(&b, title, xLabel, yLabel, yMax)
+
+	plotWidth := float64(chartWidth - chartMargin - 10)
+	plotHeight := float64(chartHeight - chartMargin - 30)
+
+	if len(values) > 0 {
+
+		barWidth := plotWidth / float64(len(values))
+		labelEvery := int(math.Ceil(float64(len(values)) / 10))
+
+		for i, v := range values {
+
+			h := plotHeight * v / yMax
+			x := float64(chartMargin) + barWidth*float64(i)
+			y := float64(chartHeight-chartMargin) - h
+
+			b.WriteString(fmt.Sprintf("<rect class=\"bar\" x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" data-label=\"%s\" data-value=\"%s\"/>\n",
+				x+1, y, math.Max(barWidth-2, 1), h, html.EscapeString(labels[i]), formatTick(v)))
+
+			if i%labelEvery == 0 {
+				b.WriteString(fmt.Sprintf("<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\">%s</text>\n",
+					x+barWidth/2, chartHeight-chartMargin+14, html.EscapeString(shortLabel(labels[i]))))
+			}
+		}
+	}
+
+	b.WriteString("</svg>\n")
+
+	return b.String()
+}
+
+// svgLineChart draws a line chart with an horizontal threshold
+func svgLineChart(title string, points [][2]float64, threshold float64, xLabel, yLabel string) string {
+
+	var b strings.Builder
+
+	var xMax, yMax float64
+	for _, i := range points {
+		xMax = math.Max(xMax, i[0])
+		yMax = math.Max(yMax, i[1])
+	}
+
+	yMax = math.Max(yMax, threshold*2)
+	if xMax == 0 {
+		xMax = 1
+	}
+	if yMax == 0 {
+		yMax = 1
+	}
+
+	svgFrame(&b, title, xLabel, yLabel, yMax)
+
+	plotWidth := float64(chartWidth - chartMargin - 10)
+	plotHeight := float64(chartHeight - chartMargin - 30)
+
+	var path []string
+	for _, i := range points {
+		x := float64(chartMargin) + plotWidth*i[0]/xMax
+		y := float64(chartHeight-chartMargin) - plotHeight*i[1]/yMax
+		path = append(path, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+
+	if len(path) > 0 {
+		b.WriteString(fmt.Sprintf("<polyline class=\"line\" points=\"%s\"/>\n", strings.Join(path, " ")))
+	}
+
+	if threshold > 0 {
+		y := float64(chartHeight-chartMargin) - plotHeight*threshold/yMax
+		b.WriteString(fmt.Sprintf("<line class=\"threshold\" x1=\"%d\" y1=\"%.1f\" x2=\"%d\" y2=\"%.1f\"/>\n", chartMargin, y, chartWidth-10, y))
+	}
+
+	b.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"%d\" text-anchor=\"end\">%s</text>\n", chartWidth-10, chartHeight-chartMargin+14, formatTick(xMax)))
+	b.WriteString("</svg>\n")
+
+	return b.String()
+}
+
+// svgBoxPlot draws one box with the quartiles and whiskers for each group of values
+func svgBoxPlot(title string, labels []string, values [][]float64, yLabel string) string {
+
+	var b strings.Builder
+
+	var yMax float64
+	for _, i := range values {
+		yMax = math.Max(yMax, maxValue(i))
+	}
+	if yMax == 0 {
+		yMax = 1
+	}
+
+	svgFrame(&b, title, "channel", yLabel, yMax)
+
+	plotWidth := float64(chartWidth - chartMargin - 10)
+	plotHeight := float64(chartHeight - chartMargin - 30)
+	boxWidth := plotWidth / math.Max(1, float64(len(values)))
+
+	scale := func(v float64) float64 {
+		return float64(chartHeight-chartMargin) - plotHeight*v/yMax
+	}
+
+	for i, v := range values {
+
+		x := float64(chartMargin) + boxWidth*float64(i)
+		center := x + boxWidth/2
+
+		b.WriteString(fmt.Sprintf("<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\">%s</text>\n",
+			center, chartHeight-chartMargin+14, html.EscapeString(shortLabel(labels[i]))))
+
+		if len(v) == 0 {
+			continue
+		}
+
+		sorted := make([]float64, len(v))
+		copy(sorted, v)
+		sort.Float64s(sorted)
+
+		q1 := quantile(sorted, 0.25)
+		q2 := quantile(sorted, 0.5)
+		q3 := quantile(sorted, 0.75)
+		iqr := q3 - q1
+		low := math.Max(sorted[0], q1-1.5*iqr)
+		high := math.Min(sorted[len(sorted)-1], q3+1.5*iqr)
+
+		b.WriteString(fmt.Sprintf("<line class=\"whisker\" x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\"/>\n", center, scale(low), center, scale(high)))
+		b.WriteString(fmt.Sprintf("<rect class=\"box\" x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\"/>\n", x+boxWidth*0.2, scale(q3), boxWidth*0.6, scale(q1)-scale(q3)))
+		b.WriteString(fmt.Sprintf("<line class=\"whisker\" x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\"/>\n", x+boxWidth*0.2, scale(q2), x+boxWidth*0.8, scale(q2)))
+	}
+
+	b.WriteString("</svg>\n")
+
+	return b.String()
+}
+
+// quantile returns the value at the given fraction of a sorted list
+func quantile(sorted []float64, q float64) float64 {
+
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+// shortLabel trims long axis labels
+func shortLabel(s string) string {
+	if len(s) > 12 {
+		return s[:11] + "~"
+	}
+	return s
+}
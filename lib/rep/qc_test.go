@@ -0,0 +1,125 @@
+package rep
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"philosopher/lib/id"
+)
+
+func TestEvidence_qcRunSummary(t *testing.T) {
+
+	evi := Evidence{PSM: PSMEvidenceList{
+		{Spectrum: "runA.00001.00001.2", Peptide: "PEPTIDE", Intensity: 10},
+		{Spectrum: "runA.00002.00002.2", Peptide: "PEPTIDEK", Intensity: 0},
+		{Spectrum: "runA.00003.00003.2", Peptide: "EDITPEP", IsDecoy: true},
+		{Spectrum: "runB.00001.00001.2", Peptide: "PEPTIDE", Intensity: 5},
+		{Spectrum: "runB.00002.00002.2", Peptide: "ELVISLIVES", Intensity: 7},
+	}}
+
+	summary := evi.qcRunSummary(map[string]int{"runA": 10})
+
+	if len(summary) != 2 {
+		t.Fatalf("qcRunSummary() returned %d runs, want 2", len(summary))
+	}
+
+	tests := []struct {
+		name     string
+		run      qcRun
+		psms     int
+		decoys   int
+		peptides int
+		rate     float64
+		missing  float64
+	}{
+		{"Run with scans and a missing peptide", summary[0], 2, 1, 2, 20, 50},
+		{"Run without scans", summary[1], 2, 0, 2, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if tt.run.PSMs != tt.psms || tt.run.Decoys != tt.decoys || tt.run.Peptides != tt.peptides {
+				t.Errorf("qcRunSummary() = %d PSMs, %d decoys, %d peptides, want %d, %d, %d",
+					tt.run.PSMs, tt.run.Decoys, tt.run.Peptides, tt.psms, tt.decoys, tt.peptides)
+			}
+
+			if got := tt.run.IdentificationRate(); got != tt.rate {
+				t.Errorf("IdentificationRate() = %v, want %v", got, tt.rate)
+			}
+
+			if got := tt.run.MissingRate(); got != tt.missing {
+				t.Errorf("MissingRate() = %v, want %v", got, tt.missing)
+			}
+		})
+	}
+}
+
+func TestEvidence_QCReport(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "qc")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	evi := Evidence{PSM: PSMEvidenceList{
+		{Spectrum: "runA.00001.00001.2", Peptide: "PEPTIDE", Intensity: 10, Probability: 0.99},
+	}}
+	evi.PSM[0].Labels.Channel1.Intensity = 100
+
+	// more channels than the labels hold must not misalign the boxes and their names
+	evi.QCReport(dir, 20, 0.01, map[string]int{"runA": 4}, QCScoreList{{Score: 0.99}, {Score: 0.5, IsDecoy: true}})
+
+	b, e := ioutil.ReadFile(dir + string(os.PathSeparator) + "qc.html")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !strings.Contains(string(b), "<td>25.0 %</td>") {
+		t.Errorf("QCReport() is missing the identification rate of the run")
+	}
+
+	if !strings.Contains(string(b), "FDR curve") {
+		t.Errorf("QCReport() is missing the FDR curve")
+	}
+}
+
+func TestNewQCScores(t *testing.T) {
+
+	// the unfiltered PSMs, including the ones the filter would reject
+	psm := id.PepIDList{
+		{Protein: "sp|P1|A", Expectation: 0.5},
+		{Protein: "rev_sp|P2|B", Expectation: 0.01},
+		{Protein: "sp|P3|C", Expectation: 0.001},
+		{Protein: "sp|P4|D", Expectation: 0.002},
+		{Protein: "rev_sp|P5|E", Expectation: 0.9},
+	}
+
+	score, e := id.NewScore(id.ExpectationScore, "")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	scores := NewQCScores(psm, score, "rev_")
+
+	want := QCScoreList{
+		{Score: -0.001},
+		{Score: -0.002},
+		{Score: -0.01, IsDecoy: true},
+		{Score: -0.5},
+		{Score: -0.9, IsDecoy: true},
+	}
+
+	if !reflect.DeepEqual(scores, want) {
+		t.Fatalf("NewQCScores() = %v, want %v", scores, want)
+	}
+
+	curve := [][2]float64{{2, 0}, {2, 0.5}, {3, 1.0 / 3}, {3, 2.0 / 3}}
+	if got := qcFDRCurve(scores); !reflect.DeepEqual(got, curve) {
+		t.Errorf("qcFDRCurve() = %v, want %v", got, curve)
+	}
+}
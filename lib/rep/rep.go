@@ -20,10 +20,14 @@ type Evidence struct {
 	Ions            IonEvidenceList
 	Peptides        PeptideEvidenceList
 	Proteins        ProteinEvidenceList
+	Peptidoforms    PeptidoformEvidenceList
+	Genes           GeneEvidenceList
 	Mods            mod.Modifications
 	Modifications   ModificationEvidence
+	Sites           SiteEvidenceList
 	CombinedProtein CombinedProteinEvidenceList
 	CombinedPeptide CombinedPeptideEvidenceList
+	CombinedSite    CombinedSiteEvidenceList
 }
 
 // SearchParametersEvidence ...
@@ -129,6 +133,7 @@ type PSMEvidence struct {
 	GeneName                             string
 	ModifiedPeptide                      string
 	CompensationVoltage                  string
+	Class                                string
 	MappedProteins                       map[string]int
 	MappedGenes                          map[string]int
 	AssumedCharge                        uint8
@@ -160,6 +165,8 @@ type PSMEvidence struct {
 	IonMobility                          float64
 	Purity                               float64
 	IsDecoy                              bool
+	IsContaminant                        bool
+	Uniqueness                           string
 	IsUnique                             bool
 	IsURazor                             bool
 	Labels                               iso.Labels
@@ -201,16 +208,18 @@ type IonEvidence struct {
 	Probability              float64
 	Expectation              float64
 	SummedLabelIntensity     float64
+	Uniqueness               string
 	IsUnique                 bool
 	IsURazor                 bool
 	IsDecoy                  bool
+	IsContaminant            bool
 	Protein                  string
 	ProteinID                string
 	GeneName                 string
 	EntryName                string
 	ProteinDescription       string
 	Labels                   iso.Labels
-	PhosphoLabels            iso.Labels
+	ModLabels                iso.Labels
 	Modifications            mod.Modifications
 }
 
@@ -246,11 +255,13 @@ type PeptideEvidence struct {
 	Probability            float64
 	ModifiedObservations   int
 	UnModifiedObservations int
+	Uniqueness             string
 	IsUnique               bool
 	IsURazor               bool
 	IsDecoy                bool
+	IsContaminant          bool
 	Labels                 iso.Labels
-	PhosphoLabels          iso.Labels
+	ModLabels              iso.Labels
 	Modifications          mod.Modifications
 }
 
@@ -283,6 +294,7 @@ type ProteinEvidence struct {
 	GeneNames              string
 	ProteinExistence       string
 	Sequence               string
+	Class                  string
 	SupportingSpectra      map[string]int
 	IndiProtein            map[string]uint8
 	UniqueStrippedPeptides int
@@ -303,9 +315,9 @@ type ProteinEvidence struct {
 	TotalLabels            iso.Labels
 	UniqueLabels           iso.Labels
 	URazorLabels           iso.Labels // Unique + razor
-	PhosphoTotalLabels     iso.Labels
-	PhosphoUniqueLabels    iso.Labels
-	PhosphoURazorLabels    iso.Labels // Unique + razor
+	ModTotalLabels         iso.Labels
+	ModUniqueLabels        iso.Labels
+	ModURazorLabels        iso.Labels // Unique + razor
 	Modifications          mod.Modifications
 }
 
@@ -386,14 +398,16 @@ type ModificationEvidence struct {
 
 // MassBin represents each bin from the mass distribution
 type MassBin struct {
-	LowerMass     float64
-	HigherRight   float64
-	MassCenter    float64
-	AverageMass   float64
-	CorrectedMass float64
-	Modifications []string
-	AssignedMods  PSMEvidenceList
-	ObservedMods  PSMEvidenceList
+	LowerMass         float64
+	HigherRight       float64
+	MassCenter        float64
+	AverageMass       float64
+	CorrectedMass     float64
+	Modifications     []string
+	AssignedMods      PSMEvidenceList
+	ObservedMods      PSMEvidenceList
+	LocalizedResidues map[string]int
+	Annotations       []MassAnnotation
 }
 
 // New constructor
@@ -444,20 +458,44 @@ func Run(m met.Data) {
 	repo.MetaPSMReport(m.Home, isoBrand, isoChannels, m.Report.Decoys, isComet, hasLoc, m.Report.IonMob, hasLabels)
 
 	// Ion
-	repo.MetaIonReport(m.Home, isoBrand, isoChannels, m.Report.Decoys, hasLabels)
+	repo.MetaIonReport(m.Home, isoBrand, m.Quantify.Mod, isoChannels, m.Report.Decoys, hasLabels)
 
 	// Peptide
-	repo.MetaPeptideReport(m.Home, isoBrand, isoChannels, m.Report.Decoys, hasLabels)
+	repo.MetaPeptideReport(m.Home, isoBrand, m.Quantify.Mod, isoChannels, m.Report.Decoys, hasLabels)
+
+	// Peptidoform
+	if len(repo.Peptidoforms) > 0 {
+		repo.MetaPeptidoformReport(m.Home, m.Report.Decoys)
+	}
 
 	// Protein
 	if len(m.Filter.Pox) > 0 || m.Filter.Inference {
-		repo.MetaProteinReport(m.Home, isoBrand, isoChannels, m.Report.Decoys, m.Filter.Razor, m.Quantify.Unique, hasLabels)
+		repo.MetaProteinReport(m.Home, isoBrand, m.Quantify.Mod, isoChannels, m.Report.Decoys, m.Filter.Razor, m.Quantify.Unique, hasLabels)
 		repo.ProteinFastaReport(m.Home, m.Report.Decoys)
+
+		if m.Filter.Inference && len(m.Filter.Pox) == 0 {
+			repo.ProteinGroupReport(m.Home, m.Filter.Tag, m.Report.Decoys)
+		}
+
+		if m.Report.CovMap {
+			repo.CoverageReport(m.Home, m.Report.Decoys)
+		}
+	}
+
+	// Gene
+	if m.Filter.Gene {
+		repo.MetaGeneReport(m.Home, m.Report.Decoys)
 	}
 
 	// Modifications
+	if m.Filter.Mapmods {
+		repo.AssembleModificationReport()
+		repo.AnnotateMassBins()
+	}
+
 	if len(repo.Modifications.MassBins) > 0 {
 		repo.ModificationReport(m.Home)
+		repo.ModificationSummaryReport(m.Home)
 
 		if m.PTMProphet.InputFiles != nil || len(m.PTMProphet.InputFiles) > 0 {
 			repo.PSMLocalizationReport(m.Home, m.Filter.Tag, m.Filter.Razor, m.Report.Decoys)
@@ -466,6 +504,32 @@ func Run(m met.Data) {
 		repo.PlotMassHist()
 	}
 
+	// Sites
+	if m.Report.Sites {
+		repo.AssembleSiteReport()
+		repo.MetaSiteReport(m.Home, isoBrand, isoChannels, m.Report.Decoys, hasLabels)
+	}
+
+	// Proteogenomics
+	if repo.HasSequenceClasses() {
+		repo.SequenceClassReport(m.Home)
+	}
+
+	// QC
+	if m.Report.QC {
+
+		// the spectra are looked up where the quantification found them, or in the workspace
+		spectra := m.Quantify.Dir
+		if len(spectra) == 0 {
+			spectra = m.Home
+		}
+
+		var scores QCScoreList
+		RestoreQCScores(&scores)
+
+		repo.QCReport(m.Home, isoChannels, m.Filter.PsmFDR, MS2ScanCounts(spectra, repo.RunNames()), scores)
+	}
+
 	// MSstats
 	if m.Report.MSstats {
 		repo.MetaMSstatsReport(m.Home, isoBrand, isoChannels, m.Report.Decoys)
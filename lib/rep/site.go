@@ -0,0 +1,578 @@
+package rep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"philosopher/lib/iso"
+	"philosopher/lib/msg"
+)
+
+// localizationThreshold is the minimum site probability for a PSM to be counted as localized
+const localizationThreshold = 0.75
+
+// SiteEvidence represents a modified residue on a protein sequence
+type SiteEvidence struct {
+	Protein      string
+	ProteinID    string
+	EntryName    string
+	GeneName     string
+	Modification string
+	AminoAcid    string
+	Position     int
+	Probability  float64
+	LocalizedSpc int
+	Intensity    float64
+	IsDecoy      bool
+	Scored       bool
+	Peptides     map[string]int
+	Spectra      map[string]int
+	Multiplicity map[int]int
+	Labels       iso.Labels
+}
+
+// SiteEvidenceList is a list of protein sites
+type SiteEvidenceList []SiteEvidence
+
+func (a SiteEvidenceList) Len() int      { return len(a) }
+func (a SiteEvidenceList) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a SiteEvidenceList) Less(i, j int) bool {
+	if a[i].Protein != a[j].Protein {
+		return a[i].Protein < a[j].Protein
+	}
+	if a[i].Position != a[j].Position {
+		return a[i].Position < a[j].Position
+	}
+	return a[i].Modification < a[j].Modification
+}
+
+// CombinedSiteEvidence represents a protein site across multiple data sets
+type CombinedSiteEvidence struct {
+	Protein      string
+	ProteinID    string
+	EntryName    string
+	GeneName     string
+	Modification string
+	AminoAcid    string
+	Position     int
+	Probability  map[string]float64
+	Spc          map[string]int
+	LocalizedSpc map[string]int
+	Intensity    map[string]float64
+	Labels       map[string]iso.Labels
+}
+
+// CombinedSiteEvidenceList is a list of Combined Site Evidences
+type CombinedSiteEvidenceList []CombinedSiteEvidence
+
+func (a CombinedSiteEvidenceList) Len() int      { return len(a) }
+func (a CombinedSiteEvidenceList) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a CombinedSiteEvidenceList) Less(i, j int) bool {
+	if a[i].Protein != a[j].Protein {
+		return a[i].Protein < a[j].Protein
+	}
+	if a[i].Position != a[j].Position {
+		return a[i].Position < a[j].Position
+	}
+	return a[i].Modification < a[j].Modification
+}
+
+// localizedResidue is a single peptide position with its localization probability
+type localizedResidue struct {
+	Index       int
+	AminoAcid   string
+	Probability float64
+}
+
+// localizedForm holds the candidate residues of a modification on a PSM
+type localizedForm struct {
+	residues  []localizedResidue
+	scored    bool
+	ambiguous bool
+}
+
+// siteKey is a PTMProphet modification with the residues and mass it was defined with
+type siteKey struct {
+	name     string
+	residues string
+	mass     float64
+}
+
+// AssembleSiteReport maps the localized modifications from each PSM to the protein positions
+func (evi *Evidence) AssembleSiteReport() {
+
+	var siteMap = make(map[string]*SiteEvidence)
+
+	keys := evi.ptmProphetKeys()
+
+	for _, i := range evi.PSM {
+
+		// the protein start comes from the annotated database, without it we cannot place the site
+		if i.ProteinStart < 1 {
+			continue
+		}
+
+		var forms = make(map[string]localizedForm)
+
+		for k, v := range i.LocalizedPTMMassDiff {
+			residues := parsePTMProphetPeptide(v)
+			forms[k] = localizedForm{residues: selectLocalizedResidues(residues), scored: true}
+		}
+
+		// PTMProphet localizations take precedence, so each PSM is counted from a single source.
+		// MSFragger has no localization probability, tied positions are kept as ambiguous sites
+		if len(forms) == 0 && len(i.MSFragerLocalization) > 0 {
+			residues := parseMSFraggerLocalization(i.MSFragerLocalization)
+			name := evi.massShiftSiteName(keys, i.Massdiff, residues)
+			if len(residues) > 0 && len(name) > 0 {
+				forms[name] = localizedForm{residues: residues, ambiguous: len(residues) > 1}
+			}
+		}
+
+		for k, v := range forms {
+
+			multiplicity := len(v.residues)
+			if !v.scored {
+				multiplicity = 1
+			}
+
+			for _, j := range v.residues {
+
+				position := i.ProteinStart + j.Index
+				key := fmt.Sprintf("%s#%d#%s", i.Protein, position, k)
+
+				s, ok := siteMap[key]
+				if !ok {
+					s = &SiteEvidence{
+						Protein:      i.Protein,
+						ProteinID:    i.ProteinID,
+						EntryName:    i.EntryName,
+						GeneName:     i.GeneName,
+						Modification: k,
+						AminoAcid:    j.AminoAcid,
+						Position:     position,
+						IsDecoy:      i.IsDecoy,
+						Peptides:     make(map[string]int),
+						Spectra:      make(map[string]int),
+						Multiplicity: make(map[int]int),
+					}
+					siteMap[key] = s
+				}
+
+				s.Peptides[i.Peptide]++
+				s.Spectra[i.Spectrum]++
+				s.Multiplicity[multiplicity]++
+				s.Intensity += i.Intensity
+				s.Labels = sumLabels(s.Labels, i.Labels)
+
+				if v.scored {
+
+					s.Scored = true
+					if j.Probability > s.Probability {
+						s.Probability = j.Probability
+					}
+
+					if j.Probability >= localizationThreshold {
+						s.LocalizedSpc++
+					}
+
+				} else if !v.ambiguous {
+					s.LocalizedSpc++
+				}
+			}
+		}
+	}
+
+	var list SiteEvidenceList
+	for _, v := range siteMap {
+		list = append(list, *v)
+	}
+
+	sort.Sort(list)
+
+	evi.Sites = list
+}
+
+// ptmProphetKeys collects the PTMProphet modifications reported on the PSMs
+func (evi *Evidence) ptmProphetKeys() []siteKey {
+
+	var names = make(map[string]uint8)
+	for _, i := range evi.PSM {
+		for k := range i.LocalizedPTMMassDiff {
+			names[k] = 0
+		}
+	}
+
+	var keys []siteKey
+	for k := range names {
+		if residues, mass, ok := parsePTMProphetKey(k); ok {
+			keys = append(keys, siteKey{name: k, residues: residues, mass: mass})
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+
+	return keys
+}
+
+// parsePTMProphetKey reads the residues and the mass of a PTMProphet modification,
+// like PTMProphet_STY79.9663 or STY:79.9663
+func parsePTMProphetKey(k string) (string, float64, bool) {
+
+	k = strings.TrimPrefix(k, "PTMProphet_")
+
+	end := strings.IndexFunc(k, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z') && !(r >= 'a' && r <= 'z')
+	})
+	if end < 1 {
+		return "", 0, false
+	}
+
+	mass, e := strconv.ParseFloat(strings.TrimPrefix(k[end:], ":"), 64)
+	if e != nil {
+		return "", 0, false
+	}
+
+	return k[:end], mass, true
+}
+
+// massShiftSiteName names an MSFragger mass shift after the modification it matches, so the same site is reported
+// once whatever the localization source. The PTMProphet modifications come first, then the UniMod annotation of
+// the mass bin, and the mass bin itself when the shift is not annotated
+func (evi *Evidence) massShiftSiteName(keys []siteKey, mass float64, residues []localizedResidue) string {
+
+	name := massShiftName(mass)
+	if len(name) == 0 {
+		return ""
+	}
+
+	for _, i := range keys {
+		if math.Abs(i.mass-mass) <= massBinSize/2 && residuesIn(residues, i.residues) {
+			return i.name
+		}
+	}
+
+	bins := evi.Modifications.MassBins
+	if idx := massBinIndex(mass, len(bins)); idx >= 0 && len(bins[idx].Annotations) > 0 {
+		return bins[idx].Annotations[0].Name
+	}
+
+	return name
+}
+
+// residuesIn checks if every candidate residue is one of the modified amino acids
+func residuesIn(residues []localizedResidue, aminoAcids string) bool {
+
+	for _, i := range residues {
+		if !strings.Contains(aminoAcids, i.AminoAcid) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// massShiftName names an MSFragger mass shift after the mass bin it falls in, so PSMs carrying the same
+// modification end on the same site. Shifts in the zero bin are not modifications
+func massShiftName(mass float64) string {
+
+	center := math.Round(mass/massBinSize) * massBinSize
+	if math.Abs(center) < massBinSize/2 {
+		return ""
+	}
+
+	return fmt.Sprintf("MSFragger_%.1f", center)
+}
+
+// parsePTMProphetPeptide reads a PTMProphet peptide string like PEPS(0.950)T(0.050)IDE
+// and returns every candidate residue with its probability
+func parsePTMProphetPeptide(s string) []localizedResidue {
+
+	var residues []localizedResidue
+	var index = -1
+
+	for i := 0; i < len(s); i++ {
+
+		if s[i] == '(' {
+			end := strings.IndexByte(s[i:], ')')
+			if end == -1 {
+				break
+			}
+
+			p, e := strconv.ParseFloat(s[i+1:i+end], 64)
+			if e == nil && index >= 0 {
+				residues = append(residues, localizedResidue{Index: index, AminoAcid: string(s[index]), Probability: p})
+			}
+
+			// the index is kept relative to the unmodified sequence
+			s = s[:i] + s[i+end+1:]
+			i--
+			continue
+		}
+
+		index = i
+	}
+
+	return residues
+}
+
+// selectLocalizedResidues keeps the most probable residues, as many as there are modifications on the peptide.
+// PTMProphet probabilities add up to the number of modifications, so multiply-modified forms collapse
+// into their individual sites
+func selectLocalizedResidues(residues []localizedResidue) []localizedResidue {
+
+	var sum float64
+	for _, i := range residues {
+		sum += i.Probability
+	}
+
+	n := int(math.Round(sum))
+	if n < 1 {
+		n = 1
+	}
+
+	sort.SliceStable(residues, func(i, j int) bool {
+		return residues[i].Probability > residues[j].Probability
+	})
+
+	if n > len(residues) {
+		n = len(residues)
+	}
+
+	return residues[:n]
+}
+
+// parseMSFraggerLocalization reads the MSFragger localization peptide where lower case residues are
+// the best scoring positions for the delta mass. MSFragger gives no probability, so none is assigned
+func parseMSFraggerLocalization(s string) []localizedResidue {
+
+	var residues []localizedResidue
+
+	for i, j := range s {
+		if j >= 'a' && j <= 'z' {
+			residues = append(residues, localizedResidue{Index: i, AminoAcid: strings.ToUpper(string(j))})
+		}
+	}
+
+	return residues
+}
+
+// MetaSiteReport report all localized modification sites mapped to proteins
+func (evi Evidence) MetaSiteReport(workspace, brand string, channels int, hasDecoys, hasLabels bool) {
+
+	output := fmt.Sprintf("%s%ssite.tsv", workspace, string(filepath.Separator))
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(errors.New("site output file"), "fatal")
+	}
+	defer file.Close()
+
+	// building the printing set tat may or not contain decoys
+	var printSet SiteEvidenceList
+	for _, i := range evi.Sites {
+		if !hasDecoys {
+			if !i.IsDecoy {
+				printSet = append(printSet, i)
+			}
+		} else {
+			printSet = append(printSet, i)
+		}
+	}
+
+	header := "Protein\tProtein ID\tEntry Name\tGene\tPosition\tAmino Acid\tModification\tBest Localization Probability\tSpectral Count\tLocalized Spectral Count\tMultiplicity\tIntensity\tPeptides"
+
+	if len(brand) > 0 && len(printSet) > 0 {
+		for _, i := range LabelNames(printSet[0].Labels, channels, hasLabels) {
+			header += "\t" + i
+		}
+	}
+
+	header += "\n"
+
+	_, e = io.WriteString(file, header)
+	if e != nil {
+		msg.WriteToFile(errors.New("cannot print sites to file"), "fatal")
+	}
+
+	for _, i := range printSet {
+
+		var peptides []string
+		for j := range i.Peptides {
+			peptides = append(peptides, j)
+		}
+		sort.Strings(peptides)
+
+		var multiplicity []int
+		for j := range i.Multiplicity {
+			multiplicity = append(multiplicity, j)
+		}
+		sort.Ints(multiplicity)
+
+		var mult []string
+		for _, j := range multiplicity {
+			mult = append(mult, fmt.Sprintf("%d:%d", j, i.Multiplicity[j]))
+		}
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%d\t%d\t%s\t%f\t%s",
+			i.Protein,
+			i.ProteinID,
+			i.EntryName,
+			i.GeneName,
+			i.Position,
+			i.AminoAcid,
+			i.Modification,
+			SiteProbability(i.Probability, i.Scored),
+			len(i.Spectra),
+			i.LocalizedSpc,
+			strings.Join(mult, ", "),
+			i.Intensity,
+			strings.Join(peptides, ", "),
+		)
+
+		if len(brand) > 0 {
+			for _, j := range LabelIntensities(i.Labels, channels) {
+				line = fmt.Sprintf("%s\t%.4f", line, j)
+			}
+		}
+
+		line += "\n"
+
+		_, e = io.WriteString(file, line)
+		if e != nil {
+			msg.WriteToFile(errors.New("cannot print sites to file"), "fatal")
+		}
+	}
+}
+
+// SiteProbability prints the best localization probability, empty when the site was localized without one
+func SiteProbability(p float64, scored bool) string {
+
+	if !scored {
+		return ""
+	}
+
+	return fmt.Sprintf("%.4f", p)
+}
+
+// sumLabels adds the reporter ion intensities from b into a
+func sumLabels(a, b iso.Labels) iso.Labels {
+
+	a.Channel1.Name, a.Channel1.CustomName = b.Channel1.Name, b.Channel1.CustomName
+	a.Channel2.Name, a.Channel2.CustomName = b.Channel2.Name, b.Channel2.CustomName
+	a.Channel3.Name, a.Channel3.CustomName = b.Channel3.Name, b.Channel3.CustomName
+	a.Channel4.Name, a.Channel4.CustomName = b.Channel4.Name, b.Channel4.CustomName
+	a.Channel5.Name, a.Channel5.CustomName = b.Channel5.Name, b.Channel5.CustomName
+	a.Channel6.Name, a.Channel6.CustomName = b.Channel6.Name, b.Channel6.CustomName
+	a.Channel7.Name, a.Channel7.CustomName = b.Channel7.Name, b.Channel7.CustomName
+	a.Channel8.Name, a.Channel8.CustomName = b.Channel8.Name, b.Channel8.CustomName
+	a.Channel9.Name, a.Channel9.CustomName = b.Channel9.Name, b.Channel9.CustomName
+	a.Channel10.Name, a.Channel10.CustomName = b.Channel10.Name, b.Channel10.CustomName
+	a.Channel11.Name, a.Channel11.CustomName = b.Channel11.Name, b.Channel11.CustomName
+	a.Channel12.Name, a.Channel12.CustomName = b.Channel12.Name, b.Channel12.CustomName
+	a.Channel13.Name, a.Channel13.CustomName = b.Channel13.Name, b.Channel13.CustomName
+	a.Channel14.Name, a.Channel14.CustomName = b.Channel14.Name, b.Channel14.CustomName
+	a.Channel15.Name, a.Channel15.CustomName = b.Channel15.Name, b.Channel15.CustomName
+	a.Channel16.Name, a.Channel16.CustomName = b.Channel16.Name, b.Channel16.CustomName
+	a.Channel17.Name, a.Channel17.CustomName = b.Channel17.Name, b.Channel17.CustomName
+	a.Channel18.Name, a.Channel18.CustomName = b.Channel18.Name, b.Channel18.CustomName
+
+	a.Channel1.Intensity += b.Channel1.Intensity
+	a.Channel2.Intensity += b.Channel2.Intensity
+	a.Channel3.Intensity += b.Channel3.Intensity
+	a.Channel4.Intensity += b.Channel4.Intensity
+	a.Channel5.Intensity += b.Channel5.Intensity
+	a.Channel6.Intensity += b.Channel6.Intensity
+	a.Channel7.Intensity += b.Channel7.Intensity
+	a.Channel8.Intensity += b.Channel8.Intensity
+	a.Channel9.Intensity += b.Channel9.Intensity
+	a.Channel10.Intensity += b.Channel10.Intensity
+	a.Channel11.Intensity += b.Channel11.Intensity
+	a.Channel12.Intensity += b.Channel12.Intensity
+	a.Channel13.Intensity += b.Channel13.Intensity
+	a.Channel14.Intensity += b.Channel14.Intensity
+	a.Channel15.Intensity += b.Channel15.Intensity
+	a.Channel16.Intensity += b.Channel16.Intensity
+	a.Channel17.Intensity += b.Channel17.Intensity
+	a.Channel18.Intensity += b.Channel18.Intensity
+
+	return a
+}
+
+// LabelNames returns the column names for the first n channels
+func LabelNames(l iso.Labels, channels int, hasLabels bool) []string {
+
+	var names = [][2]string{
+		{l.Channel1.Name, l.Channel1.CustomName},
+		{l.Channel2.Name, l.Channel2.CustomName},
+		{l.Channel3.Name, l.Channel3.CustomName},
+		{l.Channel4.Name, l.Channel4.CustomName},
+		{l.Channel5.Name, l.Channel5.CustomName},
+		{l.Channel6.Name, l.Channel6.CustomName},
+		{l.Channel7.Name, l.Channel7.CustomName},
+		{l.Channel8.Name, l.Channel8.CustomName},
+		{l.Channel9.Name, l.Channel9.CustomName},
+		{l.Channel10.Name, l.Channel10.CustomName},
+		{l.Channel11.Name, l.Channel11.CustomName},
+		{l.Channel12.Name, l.Channel12.CustomName},
+		{l.Channel13.Name, l.Channel13.CustomName},
+		{l.Channel14.Name, l.Channel14.CustomName},
+		{l.Channel15.Name, l.Channel15.CustomName},
+		{l.Channel16.Name, l.Channel16.CustomName},
+		{l.Channel17.Name, l.Channel17.CustomName},
+		{l.Channel18.Name, l.Channel18.CustomName},
+	}
+
+	if channels > len(names) {
+		channels = len(names)
+	}
+
+	var list []string
+	for _, i := range names[:channels] {
+		if hasLabels && len(i[1]) > 0 {
+			list = append(list, i[1])
+		} else {
+			list = append(list, "Channel "+i[0])
+		}
+	}
+
+	return list
+}
+
+// LabelIntensities returns the reporter ion intensities for the first n channels
+func LabelIntensities(l iso.Labels, channels int) []float64 {
+
+	var intensities = []float64{
+		l.Channel1.Intensity,
+		l.Channel2.Intensity,
+		l.Channel3.Intensity,
+		l.Channel4.Intensity,
+		l.Channel5.Intensity,
+		l.Channel6.Intensity,
+		l.Channel7.Intensity,
+		l.Channel8.Intensity,
+		l.Channel9.Intensity,
+		l.Channel10.Intensity,
+		l.Channel11.Intensity,
+		l.Channel12.Intensity,
+		l.Channel13.Intensity,
+		l.Channel14.Intensity,
+		l.Channel15.Intensity,
+		l.Channel16.Intensity,
+		l.Channel17.Intensity,
+		l.Channel18.Intensity,
+	}
+
+	if channels > len(intensities) {
+		channels = len(intensities)
+	}
+
+	return intensities[:channels]
+}
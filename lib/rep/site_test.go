@@ -0,0 +1,166 @@
+package rep
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePTMProphetPeptide(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		peptide string
+		want    []localizedResidue
+	}{
+		{"single site", "PEPS(1.000)TIDE", []localizedResidue{{3, "S", 1}}},
+		{"ambiguous site", "PEPS(0.950)T(0.050)IDE", []localizedResidue{{3, "S", 0.95}, {4, "T", 0.05}}},
+		{"first and last residues", "S(0.500)PEPTIDES(0.500)", []localizedResidue{{0, "S", 0.5}, {8, "S", 0.5}}},
+		{"unterminated probability", "PEPS(0.950", nil},
+		{"unmodified", "PEPTIDE", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePTMProphetPeptide(tt.peptide); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePTMProphetPeptide() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectLocalizedResidues(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		residues []localizedResidue
+		want     []localizedResidue
+	}{
+		{"one modification", []localizedResidue{{3, "S", 0.95}, {4, "T", 0.05}}, []localizedResidue{{3, "S", 0.95}}},
+		{"two modifications", []localizedResidue{{1, "S", 0.5}, {3, "T", 0.6}, {5, "Y", 0.9}}, []localizedResidue{{5, "Y", 0.9}, {3, "T", 0.6}}},
+		{"low probabilities", []localizedResidue{{1, "S", 0.1}, {3, "T", 0.2}}, []localizedResidue{{3, "T", 0.2}}},
+		{"more modifications than residues", []localizedResidue{{1, "S", 2}}, []localizedResidue{{1, "S", 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectLocalizedResidues(tt.residues); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectLocalizedResidues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMassShiftName(t *testing.T) {
+
+	tests := []struct {
+		mass float64
+		want string
+	}{
+		{79.9663, "MSFragger_80.0"},
+		{79.9701, "MSFragger_80.0"},
+		{-17.0265, "MSFragger_-17.0"},
+		{0.0213, ""},
+	}
+
+	for _, tt := range tests {
+		if got := massShiftName(tt.mass); got != tt.want {
+			t.Errorf("massShiftName(%v) = %v, want %v", tt.mass, got, tt.want)
+		}
+	}
+}
+
+func TestParsePTMProphetKey(t *testing.T) {
+
+	tests := []struct {
+		key      string
+		residues string
+		mass     float64
+		ok       bool
+	}{
+		{"PTMProphet_STY79.9663", "STY", 79.9663, true},
+		{"STY:79.9663", "STY", 79.9663, true},
+		{"PTMProphet_nK42.0106", "nK", 42.0106, true},
+		{"PTMProphet_M-1.0", "M", -1, true},
+		{"PTMProphet_79.9663", "", 0, false},
+		{"STY", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			residues, mass, ok := parsePTMProphetKey(tt.key)
+			if residues != tt.residues || mass != tt.mass || ok != tt.ok {
+				t.Errorf("parsePTMProphetKey() = %v, %v, %v, want %v, %v, %v", residues, mass, ok, tt.residues, tt.mass, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseMSFraggerLocalization(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		peptide string
+		want    []localizedResidue
+	}{
+		{"single position", "PEPsTIDE", []localizedResidue{{Index: 3, AminoAcid: "S"}}},
+		{"tied positions", "PEPstIDE", []localizedResidue{{Index: 3, AminoAcid: "S"}, {Index: 4, AminoAcid: "T"}}},
+		{"not localized", "PEPSTIDE", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMSFraggerLocalization(tt.peptide); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMSFraggerLocalization() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvidence_AssembleSiteReport(t *testing.T) {
+
+	var evi Evidence
+	evi.PSM = PSMEvidenceList{
+		{
+			Spectrum:             "a",
+			Peptide:              "PEPSTIDE",
+			Protein:              "sp|P1|A",
+			ProteinStart:         10,
+			Massdiff:             79.9663,
+			MSFragerLocalization: "PEPStIDE",
+			LocalizedPTMMassDiff: map[string]string{"PTMProphet_STY79.9663": "PEPS(0.950)T(0.050)IDE"},
+		},
+		{Spectrum: "b", Peptide: "PEPSTIDE", Protein: "sp|P1|A", ProteinStart: 10, Massdiff: 79.9663, MSFragerLocalization: "PEPsTIDE"},
+		{Spectrum: "c", Peptide: "KPEPSTIDE", Protein: "sp|P1|A", ProteinStart: 9, Massdiff: 79.9701, MSFragerLocalization: "KPEPsTIDE"},
+		{Spectrum: "d", Peptide: "PEPSTIDE", Protein: "sp|P2|B", Massdiff: 79.9663, MSFragerLocalization: "PEPsTIDE"},
+		{Spectrum: "e", Peptide: "PEPSTIDE", Protein: "sp|P3|C", ProteinStart: 1, Massdiff: 79.9663, MSFragerLocalization: "PEPstIDE"},
+		{Spectrum: "f", Peptide: "PEPCTIDE", Protein: "sp|P4|D", ProteinStart: 1, Massdiff: 57.0215, MSFragerLocalization: "PEPcTIDE"},
+	}
+
+	evi.AssembleSiteReport()
+
+	type site struct {
+		protein      string
+		modification string
+		aminoAcid    string
+		position     int
+		spc          int
+		localized    int
+		probability  string
+	}
+
+	want := []site{
+		{"sp|P1|A", "PTMProphet_STY79.9663", "S", 13, 3, 3, "0.9500"},
+		{"sp|P3|C", "PTMProphet_STY79.9663", "S", 4, 1, 0, ""},
+		{"sp|P3|C", "PTMProphet_STY79.9663", "T", 5, 1, 0, ""},
+		{"sp|P4|D", "MSFragger_57.0", "C", 4, 1, 1, ""},
+	}
+
+	var got []site
+	for _, i := range evi.Sites {
+		got = append(got, site{i.Protein, i.Modification, i.AminoAcid, i.Position, len(i.Spectra), i.LocalizedSpc, SiteProbability(i.Probability, i.Scored)})
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AssembleSiteReport() = %v, want %v", got, want)
+	}
+}
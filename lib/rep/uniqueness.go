@@ -0,0 +1,150 @@
+package rep
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"philosopher/lib/cla"
+	"philosopher/lib/dat"
+)
+
+// peptide uniqueness categories, from the most to the least specific
+const (
+	ProteinUnique   = "protein-unique"
+	IsoformShared   = "isoform-shared"
+	GeneUnique      = "gene-unique"
+	CrossGeneShared = "cross-gene-shared"
+)
+
+// isoformSuffix matches the UniProt isoform number at the end of an accession, like P04637-2
+var isoformSuffix = regexp.MustCompile(`-\d+$`)
+
+// proteinOrigin is the gene and canonical accession a protein belongs to
+type proteinOrigin struct {
+	gene      string
+	canonical string
+}
+
+// UniquenessClassifier places peptides into uniqueness categories using the gene and isoform annotation
+// of the database
+type UniquenessClassifier struct {
+	decoyTag string
+	origins  map[string]proteinOrigin
+}
+
+// NewUniquenessClassifier creates a classifier from the database records
+func NewUniquenessClassifier(records []dat.Record, decoyTag string) UniquenessClassifier {
+
+	var self = UniquenessClassifier{decoyTag: decoyTag, origins: make(map[string]proteinOrigin)}
+
+	for _, i := range records {
+
+		if i.IsDecoy {
+			continue
+		}
+
+		id := i.ID
+		if len(id) == 0 {
+			id = i.PartHeader
+		}
+
+		o := proteinOrigin{gene: i.GeneNames, canonical: isoformSuffix.ReplaceAllString(id, "")}
+
+		// unannotated isoforms still belong to the same gene
+		if len(o.gene) == 0 {
+			o.gene = o.canonical
+		}
+
+		self.origins[i.PartHeader] = o
+	}
+
+	return self
+}
+
+// ParseUniqueness reads a comma-separated list of uniqueness categories
+func ParseUniqueness(s string) (map[string]bool, error) {
+
+	var categories = make(map[string]bool)
+
+	for _, i := range strings.Split(s, ",") {
+
+		i = strings.ToLower(strings.TrimSpace(i))
+
+		switch i {
+		case "":
+			continue
+		case ProteinUnique, IsoformShared, GeneUnique, CrossGeneShared:
+			categories[i] = true
+		default:
+			return categories, fmt.Errorf("unknown uniqueness category %s, use protein-unique, isoform-shared, gene-unique or cross-gene-shared", i)
+		}
+	}
+
+	return categories, nil
+}
+
+// Category returns the uniqueness of a peptide from the proteins it maps to, only the proteins on the same
+// side of the target-decoy search as the main protein are counted
+func (c UniquenessClassifier) Category(protein string, mapped map[string]int) string {
+
+	decoy := cla.IsDecoy(protein, c.decoyTag)
+
+	var proteins = map[string]uint8{protein: 0}
+	for i := range mapped {
+		if cla.IsDecoy(i, c.decoyTag) == decoy {
+			proteins[i] = 0
+		}
+	}
+
+	if len(proteins) == 1 {
+		return ProteinUnique
+	}
+
+	var genes = make(map[string]uint8)
+	var canonicals = make(map[string]uint8)
+
+	for i := range proteins {
+
+		name := i
+		if decoy {
+			name = cla.TargetName(i, c.decoyTag)
+		}
+
+		o, ok := c.origins[name]
+		if !ok {
+			o = proteinOrigin{gene: name, canonical: name}
+		}
+
+		genes[o.gene] = 0
+		canonicals[o.canonical] = 0
+	}
+
+	if len(genes) > 1 {
+		return CrossGeneShared
+	}
+
+	if len(canonicals) == 1 {
+		return IsoformShared
+	}
+
+	return GeneUnique
+}
+
+// UpdateUniqueness classifies the PSMs, ions and peptides into uniqueness categories
+func (evi *Evidence) UpdateUniqueness(records []dat.Record, decoyTag string) {
+
+	c := NewUniquenessClassifier(records, decoyTag)
+
+	for i := range evi.PSM {
+		evi.PSM[i].Uniqueness = c.Category(evi.PSM[i].Protein, evi.PSM[i].MappedProteins)
+	}
+
+	for i := range evi.Ions {
+		evi.Ions[i].Uniqueness = c.Category(evi.Ions[i].Protein, evi.Ions[i].MappedProteins)
+	}
+
+	for i := range evi.Peptides {
+		evi.Peptides[i].Uniqueness = c.Category(evi.Peptides[i].Protein, evi.Peptides[i].MappedProteins)
+	}
+}
@@ -0,0 +1,90 @@
+package rep
+
+import (
+	"testing"
+
+	"philosopher/lib/dat"
+)
+
+func TestUniquenessClassifier_Category(t *testing.T) {
+
+	records := []dat.Record{
+		{ID: "P04637", PartHeader: "sp|P04637|P53_HUMAN", GeneNames: "TP53"},
+		{ID: "P04637-2", PartHeader: "sp|P04637-2|P53_HUMAN", GeneNames: "TP53"},
+		{ID: "Q00001", PartHeader: "sp|Q00001|P53B_HUMAN", GeneNames: "TP53"},
+		{ID: "P11111", PartHeader: "sp|P11111|OTHER_HUMAN", GeneNames: "OTHER"},
+		{ID: "P22222", PartHeader: "sp|P22222|NOGENE_HUMAN"},
+		{ID: "P22222-3", PartHeader: "sp|P22222-3|NOGENE_HUMAN"},
+		{ID: "P33333", PartHeader: "rev_sp|P33333|DECOY_HUMAN", GeneNames: "TP53", IsDecoy: true},
+	}
+
+	c := NewUniquenessClassifier(records, "rev_")
+
+	tests := []struct {
+		name    string
+		protein string
+		mapped  map[string]int
+		want    string
+	}{
+		{"Single protein", "sp|P04637|P53_HUMAN", nil, ProteinUnique},
+		{"Mapped to itself", "sp|P04637|P53_HUMAN", map[string]int{"sp|P04637|P53_HUMAN": 0}, ProteinUnique},
+		{"Isoforms of the same protein", "sp|P04637|P53_HUMAN", map[string]int{"sp|P04637-2|P53_HUMAN": 0}, IsoformShared},
+		{"Proteins of the same gene", "sp|P04637|P53_HUMAN", map[string]int{"sp|Q00001|P53B_HUMAN": 0}, GeneUnique},
+		{"Proteins of different genes", "sp|P04637|P53_HUMAN", map[string]int{"sp|P11111|OTHER_HUMAN": 0}, CrossGeneShared},
+		{"Unannotated isoforms", "sp|P22222|NOGENE_HUMAN", map[string]int{"sp|P22222-3|NOGENE_HUMAN": 0}, IsoformShared},
+		{"Unannotated and annotated genes", "sp|P22222|NOGENE_HUMAN", map[string]int{"sp|P04637|P53_HUMAN": 0}, CrossGeneShared},
+		{"Protein missing from the database", "sp|P99999|MISSING_HUMAN", map[string]int{"sp|P04637|P53_HUMAN": 0}, CrossGeneShared},
+		{"Decoy mapped to a target", "rev_sp|P04637|P53_HUMAN", map[string]int{"sp|P11111|OTHER_HUMAN": 0}, ProteinUnique},
+		{"Target mapped to a decoy", "sp|P04637|P53_HUMAN", map[string]int{"rev_sp|P11111|OTHER_HUMAN": 0}, ProteinUnique},
+		{"Decoy isoforms", "rev_sp|P04637|P53_HUMAN", map[string]int{"rev_sp|P04637-2|P53_HUMAN": 0}, IsoformShared},
+		{"Decoys of different genes", "rev_sp|P04637|P53_HUMAN", map[string]int{"rev_sp|P11111|OTHER_HUMAN": 0}, CrossGeneShared},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Category(tt.protein, tt.mapped); got != tt.want {
+				t.Errorf("Category() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUniqueness(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		s       string
+		want    []string
+		wantErr bool
+	}{
+		{"Empty", "", nil, false},
+		{"Single category", "protein-unique", []string{ProteinUnique}, false},
+		{"Several categories", "protein-unique, Isoform-Shared,gene-unique", []string{ProteinUnique, IsoformShared, GeneUnique}, false},
+		{"Trailing comma", "cross-gene-shared,", []string{CrossGeneShared}, false},
+		{"Unknown category", "protein-unique,shared", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, e := ParseUniqueness(tt.s)
+			if (e != nil) != tt.wantErr {
+				t.Fatalf("ParseUniqueness() error = %v, wantErr %v", e, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseUniqueness() = %v, want %v", got, tt.want)
+			}
+
+			for _, i := range tt.want {
+				if !got[i] {
+					t.Errorf("ParseUniqueness() is missing %s", i)
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"regexp"
 	"strings"
 
+	"philosopher/lib/bio"
+	"philosopher/lib/cla"
 	"philosopher/lib/dat"
 	"philosopher/lib/id"
 	"philosopher/lib/uti"
@@ -22,7 +24,7 @@ type PeptideMap struct {
 
 // UpdateNumberOfEnzymaticTermini collects the NTT from ProteinProphet
 // and passes along to the final Protein structure.
-func (evi *Evidence) UpdateNumberOfEnzymaticTermini() {
+func (evi *Evidence) UpdateNumberOfEnzymaticTermini(decoyTag string) {
 
 	// restore the original prot.xml output
 	var p id.ProtIDList
@@ -33,7 +35,7 @@ func (evi *Evidence) UpdateNumberOfEnzymaticTermini() {
 
 	for _, i := range p {
 		for _, j := range i.PeptideIons {
-			if !strings.Contains(i.ProteinName, "rev_") {
+			if !cla.IsDecoy(i.ProteinName, decoyTag) {
 				key := fmt.Sprintf("%s#%s", j.PeptideSequence, i.ProteinName)
 				nttPeptidetoProptein[key] = j.NumberOfEnzymaticTermini
 			}
@@ -50,6 +52,33 @@ func (evi *Evidence) UpdateNumberOfEnzymaticTermini() {
 	}
 }
 
+// UpdateEnzymaticFeatures recomputes the number of enzymatic termini and missed cleavages of each
+// PSM and peptide ion from the flanking residues found in the database
+func (evi *Evidence) UpdateEnzymaticFeatures(enzyme string) {
+
+	var e bio.Enzyme
+	e.Synth(enzyme)
+
+	for i := range evi.PSM {
+
+		if len(evi.PSM[i].PrevAA) == 0 || len(evi.PSM[i].NextAA) == 0 {
+			continue
+		}
+
+		evi.PSM[i].NumberOfEnzymaticTermini = e.NumberOfEnzymaticTermini(evi.PSM[i].PrevAA, evi.PSM[i].Peptide, evi.PSM[i].NextAA)
+		evi.PSM[i].NumberOfMissedCleavages = e.MissedCleavages(evi.PSM[i].Peptide)
+	}
+
+	for i := range evi.Ions {
+
+		if len(evi.Ions[i].PrevAA) == 0 || len(evi.Ions[i].NextAA) == 0 {
+			continue
+		}
+
+		evi.Ions[i].NumberOfEnzymaticTermini = uint8(e.NumberOfEnzymaticTermini(evi.Ions[i].PrevAA, evi.Ions[i].Sequence, evi.Ions[i].NextAA))
+	}
+}
+
 // UpdateIonStatus pushes back to ion and psm evideces the uniqueness and razorness status of each peptide and ion
 func (evi *Evidence) UpdateIonStatus(decoyTag string) {
 
@@ -115,7 +144,7 @@ func (evi *Evidence) UpdateIonStatus(decoyTag string) {
 				delete(evi.PSM[i].MappedProteins, sp)
 				evi.PSM[i].Protein = sp
 
-				if strings.Contains(sp, decoyTag) {
+				if cla.IsDecoy(sp, decoyTag) {
 					evi.PSM[i].IsDecoy = true
 				}
 			}
@@ -139,7 +168,7 @@ func (evi *Evidence) UpdateIonStatus(decoyTag string) {
 			delete(evi.Ions[i].MappedProteins, rp)
 			evi.Ions[i].Protein = rp
 
-			if strings.Contains(rp, decoyTag) {
+			if cla.IsDecoy(rp, decoyTag) {
 				evi.Ions[i].IsDecoy = true
 			}
 
@@ -162,7 +191,7 @@ func (evi *Evidence) UpdateIonStatus(decoyTag string) {
 			delete(evi.Peptides[i].MappedProteins, rp)
 			evi.Peptides[i].Protein = rp
 
-			if strings.Contains(rp, decoyTag) {
+			if cla.IsDecoy(rp, decoyTag) {
 				evi.Peptides[i].IsDecoy = true
 			}
 
@@ -440,25 +469,26 @@ func (evi Evidence) SyncPSMToPeptideIons(decoy string) Evidence {
 }
 
 // UpdateLayerswithDatabase will fix the protein and gene assignments based on the database data
-func (evi *Evidence) UpdateLayerswithDatabase(decoyTag string) {
-
-	var dtb dat.Base
-	dtb.Restore()
+func (evi *Evidence) UpdateLayerswithDatabase(records []dat.Record, decoyTag string) {
 
 	var proteinIDMap = make(map[string]string)
 	var entryNameMap = make(map[string]string)
 	var geneMap = make(map[string]string)
 	var descriptionMap = make(map[string]string)
 	var sequenceMap = make(map[string]string)
+	var classMap = make(map[string]string)
+	var contaminantMap = make(map[string]bool)
 	var pepPrevAA = make(map[string]string)
 	var pepNextAA = make(map[string]string)
 
-	for _, j := range dtb.Records {
+	for _, j := range records {
 		proteinIDMap[j.PartHeader] = j.ID
 		entryNameMap[j.PartHeader] = j.EntryName
 		geneMap[j.PartHeader] = j.GeneNames
 		descriptionMap[j.PartHeader] = strings.TrimSpace(j.Description)
 		sequenceMap[j.PartHeader] = j.Sequence
+		classMap[j.PartHeader] = j.Class
+		contaminantMap[j.PartHeader] = j.IsContaminant
 	}
 
 	for i := range evi.PSM {
@@ -469,10 +499,12 @@ func (evi *Evidence) UpdateLayerswithDatabase(decoyTag string) {
 		evi.PSM[i].EntryName = entryNameMap[id]
 		evi.PSM[i].GeneName = geneMap[id]
 		evi.PSM[i].ProteinDescription = descriptionMap[id]
+		evi.PSM[i].Class = peptideClass(id, evi.PSM[i].MappedProteins, classMap)
+		evi.PSM[i].IsContaminant = contaminantMap[id]
 
 		// update mapped genes
 		for k := range evi.PSM[i].MappedProteins {
-			if !strings.Contains(k, decoyTag) {
+			if !cla.IsDecoy(k, decoyTag) {
 				evi.PSM[i].MappedGenes[geneMap[k]] = 0
 			}
 		}
@@ -495,7 +527,7 @@ func (evi *Evidence) UpdateLayerswithDatabase(decoyTag string) {
 				evi.PSM[i].PrevAA = string(sequenceMap[id][reMatch[0]-1])
 			}
 
-			if (reMatch[1] + 1) >= len(sequenceMap[id]) {
+			if reMatch[1] >= len(sequenceMap[id]) {
 				evi.PSM[i].NextAA = "-"
 			} else {
 				evi.PSM[i].NextAA = string(sequenceMap[id][reMatch[1]])
@@ -511,17 +543,18 @@ func (evi *Evidence) UpdateLayerswithDatabase(decoyTag string) {
 
 		id := evi.Ions[i].Protein
 		if evi.Ions[i].IsDecoy {
-			id = strings.Replace(id, decoyTag, "", 1)
+			id = cla.TargetName(id, decoyTag)
 		}
 
 		evi.Ions[i].ProteinID = proteinIDMap[id]
 		evi.Ions[i].EntryName = entryNameMap[id]
 		evi.Ions[i].GeneName = geneMap[id]
 		evi.Ions[i].ProteinDescription = descriptionMap[id]
+		evi.Ions[i].IsContaminant = contaminantMap[id]
 
 		// update mapped genes
 		for k := range evi.Ions[i].MappedProteins {
-			if !strings.Contains(k, decoyTag) {
+			if !cla.IsDecoy(k, decoyTag) {
 				evi.Ions[i].MappedGenes[geneMap[k]] = 0
 			}
 		}
@@ -534,17 +567,18 @@ func (evi *Evidence) UpdateLayerswithDatabase(decoyTag string) {
 
 		id := evi.Peptides[i].Protein
 		if evi.Peptides[i].IsDecoy {
-			id = strings.Replace(id, decoyTag, "", 1)
+			id = cla.TargetName(id, decoyTag)
 		}
 
 		evi.Peptides[i].ProteinID = proteinIDMap[id]
 		evi.Peptides[i].EntryName = entryNameMap[id]
 		evi.Peptides[i].GeneName = geneMap[id]
 		evi.Peptides[i].ProteinDescription = descriptionMap[id]
+		evi.Peptides[i].IsContaminant = contaminantMap[id]
 
 		// update mapped genes
 		for k := range evi.Peptides[i].MappedProteins {
-			if !strings.Contains(k, decoyTag) {
+			if !cla.IsDecoy(k, decoyTag) {
 				evi.Peptides[i].MappedGenes[geneMap[k]] = 0
 			}
 		}
@@ -719,3 +753,20 @@ func (evi *Evidence) UpdatePeptideModCount() {
 
 	}
 }
+
+// peptideClass returns the most trusted sequence class among the proteins a peptide maps to
+func peptideClass(protein string, mapped map[string]int, classMap map[string]string) string {
+
+	class, ok := classMap[protein]
+	if !ok {
+		return ""
+	}
+
+	for k := range mapped {
+		if c, ok := classMap[k]; ok && dat.ClassRank(c) < dat.ClassRank(class) {
+			class = c
+		}
+	}
+
+	return class
+}
@@ -0,0 +1,108 @@
+package spl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"philosopher/lib/msg"
+	"philosopher/lib/sys"
+
+	"github.com/vmihailenco/msgpack"
+)
+
+// magic identifies the binary library container
+const magic = "PHLSPLIB"
+
+// containerVersion is increased every time the binary layout changes
+const containerVersion = 1
+
+// WriteTSV creates a transition list compatible with EasyPQP and OpenSWATH
+func (lib *Library) WriteTSV(output string) {
+
+	file, e := os.Create(output)
+	if e != nil {
+		msg.WriteFile(e, "fatal")
+	}
+	defer file.Close()
+
+	header := "PrecursorMz\tProductMz\tAnnotation\tProteinId\tGeneName\tPeptideSequence\tModifiedPeptideSequence\tPrecursorCharge\tLibraryIntensity\tNormalizedRetentionTime\tPrecursorIonMobility\tFragmentType\tFragmentCharge\tFragmentSeriesNumber\tDecoy\n"
+
+	_, e = io.WriteString(file, header)
+	if e != nil {
+		msg.WriteToFile(e, "fatal")
+	}
+
+	for _, i := range lib.Entries {
+		for _, j := range i.Fragments {
+
+			annotation := fmt.Sprintf("%s%d^%d", j.Type, j.Ordinal, j.Charge)
+
+			line := fmt.Sprintf("%.6f\t%.6f\t%s\t%s\t%s\t%s\t%s\t%d\t%.2f\t%.2f\t%.4f\t%s\t%d\t%d\t0\n",
+				i.PrecursorMz,
+				j.Mz,
+				annotation,
+				i.Protein,
+				i.GeneName,
+				i.Peptide,
+				i.ModifiedPeptide,
+				i.Charge,
+				j.Intensity,
+				i.NormalizedRT,
+				i.IonMobility,
+				j.Type,
+				j.Charge,
+				j.Ordinal,
+			)
+
+			_, e = io.WriteString(file, line)
+			if e != nil {
+				msg.WriteToFile(e, "fatal")
+			}
+		}
+	}
+}
+
+// Serialize writes the library into a binary container, a fixed header followed by the msgpack payload
+func (lib *Library) Serialize(output string) {
+
+	b, e := msgpack.Marshal(&lib)
+	if e != nil {
+		msg.MarshalFile(e, "fatal")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+	buf.WriteByte(containerVersion)
+	buf.Write(b)
+
+	e = ioutil.WriteFile(output, buf.Bytes(), sys.FilePermission())
+	if e != nil {
+		msg.SerializeFile(e, "fatal")
+	}
+}
+
+// Restore reads a binary library container
+func (lib *Library) Restore(input string) {
+
+	b, e := ioutil.ReadFile(input)
+	if e != nil {
+		msg.ReadFile(e, "fatal")
+	}
+
+	if len(b) < len(magic)+1 || string(b[:len(magic)]) != magic {
+		msg.Custom(errors.New("the file is not a philosopher spectral library"), "fatal")
+	}
+
+	if b[len(magic)] != containerVersion {
+		msg.Custom(errors.New("unsupported spectral library version"), "fatal")
+	}
+
+	e = msgpack.Unmarshal(b[len(magic)+1:], &lib)
+	if e != nil {
+		msg.DecodeMsgPck(e, "fatal")
+	}
+}
@@ -0,0 +1,449 @@
+// Package spl (Spectral Library) builds peptide spectral libraries from filtered results
+package spl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"philosopher/lib/bio"
+	"philosopher/lib/cla"
+	"philosopher/lib/met"
+	"philosopher/lib/msg"
+	"philosopher/lib/mzn"
+	"philosopher/lib/rep"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Library is a collection of peptide ion spectra
+type Library struct {
+	Version string
+	IRT     string
+	Entries EntryList
+}
+
+// Entry is the consensus information for a peptide ion
+type Entry struct {
+	Spectrum        string
+	Peptide         string
+	ModifiedPeptide string
+	Protein         string
+	ProteinID       string
+	GeneName        string
+	Charge          uint8
+	PrecursorMz     float64
+	RetentionTime   float64
+	NormalizedRT    float64
+	IonMobility     float64
+	Probability     float64
+	Fragments       []Fragment
+}
+
+// EntryList is a list of library entries
+type EntryList []Entry
+
+func (a EntryList) Len() int      { return len(a) }
+func (a EntryList) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a EntryList) Less(i, j int) bool {
+	if a[i].ModifiedPeptide != a[j].ModifiedPeptide {
+		return a[i].ModifiedPeptide < a[j].ModifiedPeptide
+	}
+	return a[i].Charge < a[j].Charge
+}
+
+// Fragment is an annotated product ion
+type Fragment struct {
+	Type      string
+	Ordinal   int
+	Charge    int
+	Mz        float64
+	Intensity float64
+}
+
+// irtAnchors are the Biognosys iRT kit peptides and their reference values
+var irtAnchors = map[string]float64{
+	"LGGNEVTR":       -24.92,
+	"GAGSSEPVTGLDAK": 0.00,
+	"VEAEVQQEQR":     12.39,
+	"YILAGVETHK":     19.79,
+	"TPVISGGPYEYR":   28.71,
+	"TPVITGAPYEYR":   33.38,
+	"DGLDAASYYAPVR":  42.26,
+	"ADVTPADFSEWSK":  54.62,
+	"GTFIIDPGGVIR":   70.52,
+	"GTFIIDPAAVIR":   87.23,
+	"LFLQFGAQGSPFLK": 100.00,
+}
+
+// Run is the library command entry point
+func Run(m met.Data) {
+
+	var psm rep.PSMEvidenceList
+	rep.RestorePSM(&psm)
+
+	if len(psm) == 0 {
+		msg.Custom(errors.New("no PSMs found, run the filter command first"), "fatal")
+	}
+
+	anchors := irtAnchors
+	if len(m.Library.IRT) > 0 {
+		anchors = readAnchors(m.Library.IRT)
+	}
+
+	best := selectBestPSMs(psm, m.Filter.Tag)
+	logrus.Info("Selected ", len(best), " peptide ions")
+
+	var lib Library
+	lib.Version = m.Version
+	lib.IRT = "iRT"
+
+	// spectra are read one run at a time to keep the memory footprint low
+	var sourceMap = make(map[string][]rep.PSMEvidence)
+	for _, i := range best {
+		source := strings.Split(i.Spectrum, ".")[0]
+		sourceMap[source] = append(sourceMap[source], i)
+	}
+
+	var sourceList []string
+	for k := range sourceMap {
+		sourceList = append(sourceList, k)
+	}
+	sort.Strings(sourceList)
+
+	for _, i := range sourceList {
+
+		logrus.Info("Processing ", i)
+
+		var mz mzn.MsData
+		mz.Read(fmt.Sprintf("%s%s%s.mzML", m.Library.Dir, string(filepath.Separator), i))
+
+		var scanMap = make(map[string]*mzn.Spectrum)
+		for j := range mz.Spectra {
+			if mz.Spectra[j].Level == "2" {
+				scanMap[fmt.Sprintf("%05s", mz.Spectra[j].Scan)] = &mz.Spectra[j]
+			}
+		}
+
+		for _, j := range sourceMap[i] {
+
+			split := strings.Split(j.Spectrum, ".")
+			if len(split) < 3 {
+				continue
+			}
+
+			spec, ok := scanMap[split[2]]
+			if !ok {
+				continue
+			}
+
+			spec.Decode()
+
+			fragments := annotateSpectrum(j, spec.Mz.DecodedStream, spec.Intensity.DecodedStream, m.Library.Tol)
+			fragments = selectFragments(fragments, m.Library.TopN)
+
+			if len(fragments) < m.Library.MinFrag {
+				continue
+			}
+
+			lib.Entries = append(lib.Entries, newEntry(j, fragments))
+		}
+	}
+
+	if len(lib.Entries) == 0 {
+		msg.Custom(errors.New("no library entries could be built, check the spectra folder"), "fatal")
+	}
+
+	lib.normalizeRetentionTimes(anchors)
+
+	sort.Sort(lib.Entries)
+
+	logrus.Info("Writing ", len(lib.Entries), " library entries")
+
+	lib.WriteTSV(fmt.Sprintf("%s%slibrary.tsv", m.Home, string(filepath.Separator)))
+	lib.Serialize(fmt.Sprintf("%s%slibrary.speclib", m.Home, string(filepath.Separator)))
+}
+
+// selectBestPSMs keeps the highest scoring target PSM for each peptide ion
+func selectBestPSMs(psm rep.PSMEvidenceList, decoyTag string) []rep.PSMEvidence {
+
+	var ionMap = make(map[string]rep.PSMEvidence)
+
+	for _, i := range psm {
+
+		if i.IsDecoy || (len(decoyTag) > 0 && cla.Matcher(decoyTag).IsDecoy(i.Protein)) {
+			continue
+		}
+
+		// composite spectrum names carry the rank after the # sign
+		i.Spectrum = strings.Split(i.Spectrum, "#")[0]
+
+		key := fmt.Sprintf("%s#%d", i.ModifiedPeptide, i.AssumedCharge)
+		if len(i.ModifiedPeptide) == 0 {
+			key = i.IonForm
+		}
+
+		v, ok := ionMap[key]
+		if !ok || i.Probability > v.Probability || (i.Probability == v.Probability && i.Hyperscore > v.Hyperscore) {
+			ionMap[key] = i
+		}
+	}
+
+	var list []rep.PSMEvidence
+	for _, v := range ionMap {
+		list = append(list, v)
+	}
+
+	return list
+}
+
+// residueMasses returns the residue masses for a PSM, including the assigned modifications
+func residueMasses(psm rep.PSMEvidence) []float64 {
+
+	var aaMap = make(map[string]float64)
+	for _, i := range bio.AminoAcids() {
+		aaMap[i.Code] = i.MonoIsotopeMass
+	}
+
+	var masses = make([]float64, len(psm.Peptide))
+	for i := range psm.Peptide {
+		masses[i] = aaMap[string(psm.Peptide[i])]
+	}
+
+	if len(masses) == 0 {
+		return masses
+	}
+
+	for _, i := range psm.Modifications.Index {
+
+		if i.Type != "Assigned" || i.MassDiff == 0 {
+			continue
+		}
+
+		if i.AminoAcid == "N-term" || i.AminoAcid == "n-term" {
+			masses[0] += i.MassDiff
+		} else if i.AminoAcid == "C-term" || i.AminoAcid == "c-term" {
+			masses[len(masses)-1] += i.MassDiff
+		} else {
+			pos, e := strconv.Atoi(i.Position)
+			if e == nil && pos >= 1 && pos <= len(masses) {
+				masses[pos-1] += i.MassDiff
+			}
+		}
+	}
+
+	return masses
+}
+
+// theoreticalFragments calculates the b and y ion series for the given residue masses
+func theoreticalFragments(masses []float64, maxCharge int) []Fragment {
+
+	var fragments []Fragment
+
+	var total float64
+	for _, i := range masses {
+		total += i
+	}
+
+	var prefix float64
+	for i := 0; i < len(masses)-1; i++ {
+
+		prefix += masses[i]
+		suffix := total - prefix + bio.Water
+
+		for z := 1; z <= maxCharge; z++ {
+			fragments = append(fragments, Fragment{Type: "b", Ordinal: i + 1, Charge: z, Mz: (prefix + float64(z)*bio.Proton) / float64(z)})
+			fragments = append(fragments, Fragment{Type: "y", Ordinal: len(masses) - i - 1, Charge: z, Mz: (suffix + float64(z)*bio.Proton) / float64(z)})
+		}
+	}
+
+	return fragments
+}
+
+// annotateSpectrum matches the theoretical fragments to the most intense peak within the tolerance in ppm
+func annotateSpectrum(psm rep.PSMEvidence, mz, intensity []float64, tol float64) []Fragment {
+
+	maxCharge := int(psm.AssumedCharge) - 1
+	if maxCharge < 1 {
+		maxCharge = 1
+	}
+	if maxCharge > 2 {
+		maxCharge = 2
+	}
+
+	var annotated []Fragment
+
+	for _, f := range theoreticalFragments(residueMasses(psm), maxCharge) {
+
+		window := f.Mz * tol * 1e-6
+
+		start := sort.SearchFloat64s(mz, f.Mz-window)
+		for i := start; i < len(mz) && mz[i] <= f.Mz+window; i++ {
+			if intensity[i] > f.Intensity {
+				f.Intensity = intensity[i]
+			}
+		}
+
+		if f.Intensity > 0 {
+			annotated = append(annotated, f)
+		}
+	}
+
+	return annotated
+}
+
+// selectFragments keeps the n most intense fragments and scales them to the base peak
+func selectFragments(fragments []Fragment, n int) []Fragment {
+
+	sort.SliceStable(fragments, func(i, j int) bool {
+		return fragments[i].Intensity > fragments[j].Intensity
+	})
+
+	if n > 0 && len(fragments) > n {
+		fragments = fragments[:n]
+	}
+
+	if len(fragments) > 0 {
+		base := fragments[0].Intensity
+		for i := range fragments {
+			fragments[i].Intensity = math.Round(1000000*fragments[i].Intensity/base) / 100
+		}
+	}
+
+	return fragments
+}
+
+func newEntry(psm rep.PSMEvidence, fragments []Fragment) Entry {
+
+	var e Entry
+
+	e.Spectrum = psm.Spectrum
+	e.Peptide = psm.Peptide
+	e.ModifiedPeptide = psm.ModifiedPeptide
+	if len(e.ModifiedPeptide) == 0 {
+		e.ModifiedPeptide = psm.Peptide
+	}
+	e.Protein = psm.Protein
+	e.ProteinID = psm.ProteinID
+	e.GeneName = psm.GeneName
+	e.Charge = psm.AssumedCharge
+	e.PrecursorMz = (psm.CalcNeutralPepMass + float64(psm.AssumedCharge)*bio.Proton) / float64(psm.AssumedCharge)
+	e.RetentionTime = psm.RetentionTime
+	e.IonMobility = psm.IonMobility
+	e.Probability = psm.Probability
+	e.Fragments = fragments
+
+	return e
+}
+
+// normalizeRetentionTimes converts the retention times to the iRT scale with a linear fit over the anchor
+// peptides. When less than two anchors are found the retention times are scaled from 0 to 100
+func (lib *Library) normalizeRetentionTimes(anchors map[string]float64) {
+
+	var x, y []float64
+	var seen = make(map[string]uint8)
+
+	for _, i := range lib.Entries {
+		v, ok := anchors[i.Peptide]
+		if ok {
+			if _, dup := seen[i.Peptide]; dup {
+				continue
+			}
+			seen[i.Peptide] = 0
+			x = append(x, i.RetentionTime)
+			y = append(y, v)
+		}
+	}
+
+	var slope, intercept float64
+
+	if len(x) >= 2 {
+
+		slope, intercept = linearFit(x, y)
+		logrus.Info("Retention times aligned to ", len(x), " iRT anchors")
+
+	} else {
+
+		msg.Custom(errors.New("not enough iRT anchors found, retention times will be scaled from 0 to 100"), "warning")
+		lib.IRT = "scaled"
+
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, i := range lib.Entries {
+			min = math.Min(min, i.RetentionTime)
+			max = math.Max(max, i.RetentionTime)
+		}
+
+		if max > min {
+			slope = 100 / (max - min)
+			intercept = -min * slope
+		}
+	}
+
+	for i := range lib.Entries {
+		lib.Entries[i].NormalizedRT = math.Round((lib.Entries[i].RetentionTime*slope+intercept)*100) / 100
+	}
+}
+
+// linearFit returns the least squares slope and intercept
+func linearFit(x, y []float64) (float64, float64) {
+
+	var sx, sy, sxx, sxy float64
+	n := float64(len(x))
+
+	for i := range x {
+		sx += x[i]
+		sy += y[i]
+		sxx += x[i] * x[i]
+		sxy += x[i] * y[i]
+	}
+
+	d := n*sxx - sx*sx
+	if d == 0 {
+		return 0, sy / n
+	}
+
+	slope := (n*sxy - sx*sy) / d
+	intercept := (sy - slope*sx) / n
+
+	return slope, intercept
+}
+
+// readAnchors reads a tab or space separated file with the anchor peptides and their iRT values
+func readAnchors(f string) map[string]float64 {
+
+	var anchors = make(map[string]float64)
+
+	file, e := os.Open(f)
+	if e != nil {
+		msg.ReadFile(e, "fatal")
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		v, e := strconv.ParseFloat(fields[1], 64)
+		if e != nil {
+			continue
+		}
+
+		anchors[fields[0]] = v
+	}
+
+	if len(anchors) == 0 {
+		msg.Custom(errors.New("the iRT file does not contain any anchor peptides"), "fatal")
+	}
+
+	return anchors
+}
@@ -0,0 +1,189 @@
+package spl
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"philosopher/lib/rep"
+)
+
+func TestTheoreticalFragments(t *testing.T) {
+
+	// PEPTIDE residue masses
+	masses := []float64{97.052763875, 129.042593135, 97.052763875, 101.047678505, 113.084064015, 115.026943065, 129.042593135}
+
+	tests := []struct {
+		name    string
+		ftype   string
+		ordinal int
+		want    float64
+	}{
+		{"b2 ion", "b", 2, 227.102633},
+		{"y1 ion", "y", 1, 148.060434},
+		{"y6 ion", "y", 6, 703.314437},
+	}
+
+	fragments := theoreticalFragments(masses, 1)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, f := range fragments {
+				if f.Type == tt.ftype && f.Ordinal == tt.ordinal {
+					if math.Abs(f.Mz-tt.want) > 0.001 {
+						t.Errorf("theoreticalFragments() = %v, want %v", f.Mz, tt.want)
+					}
+					return
+				}
+			}
+			t.Errorf("fragment %s%d not found", tt.ftype, tt.ordinal)
+		})
+	}
+}
+
+func TestLinearFit(t *testing.T) {
+
+	slope, intercept := linearFit([]float64{10, 20, 30}, []float64{0, 50, 100})
+
+	if math.Abs(slope-5) > 1e-9 || math.Abs(intercept+50) > 1e-9 {
+		t.Errorf("linearFit() = %v, %v, want 5, -50", slope, intercept)
+	}
+}
+
+func TestSelectBestPSMs(t *testing.T) {
+
+	psm := rep.PSMEvidenceList{
+		{Spectrum: "run.00001.00001.2#1", ModifiedPeptide: "PEPTIDE", AssumedCharge: 2, Protein: "sp|P1|A", Probability: 0.90},
+		{Spectrum: "run.00002.00002.2#1", ModifiedPeptide: "PEPTIDE", AssumedCharge: 2, Protein: "sp|P1|A", Probability: 0.99, Hyperscore: 20},
+		{Spectrum: "run.00003.00003.2#1", ModifiedPeptide: "PEPTIDE", AssumedCharge: 2, Protein: "sp|P1|A", Probability: 0.99, Hyperscore: 30},
+		{Spectrum: "run.00004.00004.3#1", ModifiedPeptide: "PEPTIDE", AssumedCharge: 3, Protein: "sp|P1|A", Probability: 0.80},
+		{Spectrum: "run.00005.00005.2#1", IonForm: "ELVISLIVES#2", Protein: "sp|P2|B", Probability: 0.70},
+		{Spectrum: "run.00006.00006.2#1", ModifiedPeptide: "EDITPEP", AssumedCharge: 2, Protein: "sp|P3|C", Probability: 0.99, IsDecoy: true},
+		{Spectrum: "run.00007.00007.2#1", ModifiedPeptide: "KEDITPEP", AssumedCharge: 2, Protein: "rev_sp|P4|D", Probability: 0.99},
+	}
+
+	list := selectBestPSMs(psm, "rev_")
+
+	var got []string
+	for _, i := range list {
+		got = append(got, i.Spectrum)
+	}
+	sort.Strings(got)
+
+	// the best PSM of each ion, ties on probability go to the hyperscore, decoys are left out
+	want := []string{"run.00003.00003.2", "run.00004.00004.3", "run.00005.00005.2"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("selectBestPSMs() = %v, want %v", got, want)
+	}
+}
+
+func TestAnnotateSpectrum(t *testing.T) {
+
+	psm := rep.PSMEvidence{Peptide: "PEPTIDE", AssumedCharge: 2}
+
+	// the b2 ion has two peaks within 10 ppm, the last peak matches no fragment
+	mz := []float64{148.0604, 227.1020, 227.1030, 227.2000, 300.0000}
+	intensity := []float64{50, 30, 80, 500, 1000}
+
+	got := annotateSpectrum(psm, mz, intensity, 10)
+
+	want := []struct {
+		ftype     string
+		ordinal   int
+		intensity float64
+	}{
+		{"b", 2, 80},
+		{"y", 1, 50},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("annotateSpectrum() = %v, want %d fragments", got, len(want))
+	}
+
+	for i, tt := range want {
+		if got[i].Type != tt.ftype || got[i].Ordinal != tt.ordinal || got[i].Charge != 1 || got[i].Intensity != tt.intensity {
+			t.Errorf("annotateSpectrum() fragment %d = %v, want %s%d with intensity %v", i, got[i], tt.ftype, tt.ordinal, tt.intensity)
+		}
+	}
+}
+
+func TestLibrary_Output(t *testing.T) {
+
+	dir, e := ioutil.TempDir("", "spl")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	lib := Library{
+		Version: "test",
+		IRT:     "scaled",
+		Entries: EntryList{
+			{
+				Spectrum:        "run.00003.00003.2",
+				Peptide:         "PEPTIDE",
+				ModifiedPeptide: "PEPTIDE",
+				Protein:         "sp|P1|A",
+				GeneName:        "A",
+				Charge:          2,
+				PrecursorMz:     400.687258,
+				NormalizedRT:    42.5,
+				Probability:     0.99,
+				Fragments: []Fragment{
+					{Type: "y", Ordinal: 1, Charge: 1, Mz: 148.060434, Intensity: 100},
+					{Type: "b", Ordinal: 2, Charge: 1, Mz: 227.102633, Intensity: 62.5},
+				},
+			},
+		},
+	}
+
+	tsv := filepath.Join(dir, "library.tsv")
+	lib.WriteTSV(tsv)
+
+	b, e := ioutil.ReadFile(tsv)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+
+	want := []string{
+		"400.687258\t148.060434\ty1^1\tsp|P1|A\tA\tPEPTIDE\tPEPTIDE\t2\t100.00\t42.50\t0.0000\ty\t1\t1\t0",
+		"400.687258\t227.102633\tb2^1\tsp|P1|A\tA\tPEPTIDE\tPEPTIDE\t2\t62.50\t42.50\t0.0000\tb\t1\t2\t0",
+	}
+
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "PrecursorMz\tProductMz\tAnnotation") {
+		t.Fatalf("WriteTSV() wrote %d lines, want a header and one line per fragment", len(lines))
+	}
+
+	for i := range want {
+		if lines[i+1] != want[i] {
+			t.Errorf("WriteTSV() line %d = %q, want %q", i+1, lines[i+1], want[i])
+		}
+	}
+
+	speclib := filepath.Join(dir, "library.speclib")
+	lib.Serialize(speclib)
+
+	b, e = ioutil.ReadFile(speclib)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if !strings.HasPrefix(string(b), magic) || b[len(magic)] != containerVersion {
+		t.Errorf("Serialize() header = %q, want %s and version %d", b[:len(magic)+1], magic, containerVersion)
+	}
+
+	var restored Library
+	restored.Restore(speclib)
+
+	if !reflect.DeepEqual(restored, lib) {
+		t.Errorf("Restore() = %v, want %v", restored, lib)
+	}
+}
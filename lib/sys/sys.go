@@ -156,6 +156,12 @@ func ProBin() string {
 	return p
 }
 
+// PeptidoformBin file
+func PeptidoformBin() string {
+	p := fmt.Sprintf("%s%speptidoform.bin", MetaDir(), string(filepath.Separator))
+	return p
+}
+
 // DBBin file
 func DBBin() string {
 	p := fmt.Sprintf("%s%sdb.bin", MetaDir(), string(filepath.Separator))
@@ -174,6 +180,24 @@ func RazorBin() string {
 	return p
 }
 
+// GeneBin file
+func GeneBin() string {
+	p := fmt.Sprintf("%s%sgene.bin", MetaDir(), string(filepath.Separator))
+	return p
+}
+
+// QCBin file
+func QCBin() string {
+	p := fmt.Sprintf("%s%sqc.bin", MetaDir(), string(filepath.Separator))
+	return p
+}
+
+// GlobalBin file
+func GlobalBin() string {
+	p := fmt.Sprintf("%s%sglobal.bin", MetaDir(), string(filepath.Separator))
+	return p
+}
+
 // MetaDir dir
 func MetaDir() string {
 	return ".meta"
@@ -41,7 +41,9 @@ func writeDatabase(d met.Database, text string) string {
 
 	text = fmt.Sprintf("A protein database file was downloaded from UniProt %s (PMID:30395287) using the proteome ID %s on %s.", dbFlavor, d.ID, d.TimeStamp)
 
-	if d.Crap {
+	if len(d.ContamLib) > 0 {
+		text = fmt.Sprintf("%s Contaminant sequences from %s were also added to the database, replacing identical target sequences.", text, d.ContamLib)
+	} else if d.Crap {
 		text = fmt.Sprintf("%s A list of 153 common contaminants was also added to the database.", text)
 	}
 